@@ -36,6 +36,11 @@ type OrderCourierServer struct {
 	ClientPhoneNumber string                 `protobuf:"bytes,7,opt,name=ClientPhoneNumber,proto3" json:"ClientPhoneNumber,omitempty"`
 	DeliveryTime      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=DeliveryTime,proto3" json:"DeliveryTime,omitempty"`
 	PaymentType       int64                  `protobuf:"varint,9,opt,name=PaymentType,proto3" json:"PaymentType,omitempty"`
+	// Optional hints for vehicle-aware dispatch. Size is one of small, medium,
+	// large or xl.
+	WeightKg   float64 `protobuf:"fixed64,10,opt,name=WeightKg,proto3" json:"WeightKg,omitempty"`
+	Size       string  `protobuf:"bytes,11,opt,name=Size,proto3" json:"Size,omitempty"`
+	DistanceKm float64 `protobuf:"fixed64,12,opt,name=DistanceKm,proto3" json:"DistanceKm,omitempty"`
 }
 
 func (x *OrderCourierServer) Reset() {
@@ -133,6 +138,27 @@ func (x *OrderCourierServer) GetPaymentType() int64 {
 	return 0
 }
 
+func (x *OrderCourierServer) GetWeightKg() float64 {
+	if x != nil {
+		return x.WeightKg
+	}
+	return 0
+}
+
+func (x *OrderCourierServer) GetSize() string {
+	if x != nil {
+		return x.Size
+	}
+	return ""
+}
+
+func (x *OrderCourierServer) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
 type ServicesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -291,7 +317,7 @@ var file_courierServer_proto_rawDesc = []byte{
 	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
 	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
 	0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8e, 0x03, 0x0a,
+	0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xde, 0x03, 0x0a,
 	0x12, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x53, 0x65, 0x72,
 	0x76, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2a, 0x0a,
@@ -316,7 +342,12 @@ var file_courierServer_proto_rawDesc = []byte{
 	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x44,
 	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x50,
 	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x48, 0x0a,
+	0x52, 0x0b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x4b, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x08, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x4b, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a,
+	0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4b, 0x6d, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0a, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4b, 0x6d, 0x22, 0x48, 0x0a,
 	0x10, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
 	0x65, 0x12, 0x34, 0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20,
 	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x2e, 0x44, 0x65,
@@ -0,0 +1,97 @@
+package grpcServer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const serviceName = "courier.CourierServer"
+
+type Config struct {
+	// Addr is the address the server listens on.
+	Addr string
+	// CertFile and KeyFile enable TLS. With ClientCAFile, clients may present
+	// a certificate signed by that CA; its common name is the client's name.
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	// Tokens maps the shared secrets clients send as "authorization: Bearer
+	// <token>" metadata to the client's name.
+	Tokens map[string]string
+	// Allow lists, per full method name, the clients that may call it; "*"
+	// is any authenticated client. Methods not listed are closed to every
+	// client, except the health checks, which are open to anybody.
+	Allow map[string][]string
+	// AllowAnonymous lets calls without credentials through as "anonymous",
+	// for local development.
+	AllowAnonymous bool
+}
+
+// ConfigFromEnv reads GRPC_SERVER_ADDR (:8091 by default), GRPC_TLS_CERT,
+// GRPC_TLS_KEY, GRPC_CLIENT_CA, GRPC_CLIENT_TOKENS as
+// "name:token,name:token", GRPC_ALLOW as
+// "CreateOrder=orders,restaurants;GetDeliveryServicesList=*" and
+// GRPC_ALLOW_ANONYMOUS.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Addr:           os.Getenv("GRPC_SERVER_ADDR"),
+		CertFile:       os.Getenv("GRPC_TLS_CERT"),
+		KeyFile:        os.Getenv("GRPC_TLS_KEY"),
+		ClientCAFile:   os.Getenv("GRPC_CLIENT_CA"),
+		Tokens:         make(map[string]string),
+		Allow:          make(map[string][]string),
+		AllowAnonymous: os.Getenv("GRPC_ALLOW_ANONYMOUS") == "true",
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = ":8091"
+	}
+	for _, pair := range splitList(os.Getenv("GRPC_CLIENT_TOKENS"), ",") {
+		name, token := split(pair, ":")
+		if name == "" || token == "" {
+			return Config{}, fmt.Errorf("GRPC_CLIENT_TOKENS: expect name:token, got %q", pair)
+		}
+		cfg.Tokens[token] = name
+	}
+	for _, rule := range splitList(os.Getenv("GRPC_ALLOW"), ";") {
+		method, clients := split(rule, "=")
+		if method == "" || clients == "" {
+			return Config{}, fmt.Errorf("GRPC_ALLOW: expect method=client,client, got %q", rule)
+		}
+		cfg.Allow[fullMethod(method)] = splitList(clients, ",")
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return Config{}, fmt.Errorf("GRPC_TLS_CERT and GRPC_TLS_KEY go together")
+	}
+	if cfg.ClientCAFile != "" && cfg.CertFile == "" {
+		return Config{}, fmt.Errorf("GRPC_CLIENT_CA needs GRPC_TLS_CERT and GRPC_TLS_KEY")
+	}
+	return cfg, nil
+}
+
+// fullMethod completes method names of the courier server, like
+// CreateOrder, to /courier.CourierServer/CreateOrder.
+func fullMethod(method string) string {
+	if strings.HasPrefix(method, "/") {
+		return method
+	}
+	return fmt.Sprintf("/%s/%s", serviceName, method)
+}
+
+func split(s, sep string) (string, string) {
+	parts := strings.SplitN(s, sep, 2)
+	if len(parts) != 2 {
+		return strings.TrimSpace(parts[0]), ""
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+}
+
+func splitList(s, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,13 +2,19 @@ package grpcServer
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthProto "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"log"
 	"net"
+	"os"
 	courierProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
 )
 
@@ -17,30 +23,120 @@ type GRPCServer struct {
 	courierProto.UnimplementedCourierServerServer
 }
 
-func NewGRPCServer(service *service.Service) {
-	s := grpc.NewServer()
-	str := &GRPCServer{service: service}
-	courierProto.RegisterCourierServerServer(s, str)
-	lis, err := net.Listen("tcp", ":8091")
+// Server is the courier gRPC server together with the standard
+// grpc.health.v1 service.
+type Server struct {
+	cfg    Config
+	srv    *grpc.Server
+	health *health.Server
+}
+
+// NewGRPCServer builds the courier server. Every call has to be
+// authenticated by a client certificate or a bearer token and be allowed for
+// the client by cfg.Allow; health checks may come without credentials.
+func NewGRPCServer(service *service.Service, cfg Config) (*Server, error) {
+	opts, err := serverOptions(cfg)
 	if err != nil {
-		log.Fatalf("NewGRPCServer, Listen:%s", err)
+		return nil, fmt.Errorf("NewGRPCServer:%w", err)
 	}
-	reflection.Register(s)
-	if err = s.Serve(lis); err != nil {
-		log.Fatalf("NewGRPCServer, Serve:%s", err)
+	s := &Server{cfg: cfg, srv: grpc.NewServer(opts...), health: health.NewServer()}
+	courierProto.RegisterCourierServerServer(s.srv, &GRPCServer{service: service})
+	healthProto.RegisterHealthServer(s.srv, s.health)
+	return s, nil
+}
+
+// Run serves on cfg.Addr until Shutdown.
+func (s *Server) Run() error {
+	lis, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return err
 	}
+	return s.Serve(lis)
+}
 
+// Serve serves on the listener until Shutdown.
+func (s *Server) Serve(lis net.Listener) error {
+	s.health.SetServingStatus("", healthProto.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus(serviceName, healthProto.HealthCheckResponse_SERVING)
+	return s.srv.Serve(lis)
+}
+
+// Shutdown reports NOT_SERVING to health checks and waits for the running
+// calls; the ones still running when ctx is done are cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		return ctx.Err()
+	}
+}
+
+func serverOptions(cfg Config) ([]grpc.ServerOption, error) {
+	i := &interceptors{cfg: cfg}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(i.unary),
+		grpc.ChainStreamInterceptor(i.stream),
+	}
+	if cfg.CertFile != "" {
+		tlsCfg, err := tlsConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+	if len(cfg.Tokens) == 0 && cfg.ClientCAFile == "" && !cfg.AllowAnonymous {
+		log.Println("NewGRPCServer: neither GRPC_CLIENT_TOKENS nor GRPC_CLIENT_CA is set, every call will be rejected")
+	}
+	if len(cfg.Allow) == 0 {
+		log.Println("NewGRPCServer: GRPC_ALLOW is not set, every call but health checks will be rejected")
+	}
+	return opts, nil
+}
+
+func tlsConfig(cfg Config) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s", cfg.ClientCAFile)
+		}
+		tlsCfg.ClientCAs = pool
+		// Clients with a bearer token don't need a certificate.
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsCfg, nil
 }
 
 func (g *GRPCServer) CreateOrder(ctx context.Context, order *courierProto.OrderCourierServer) (*emptypb.Empty, error) {
-	return g.service.CreateOrder(order)
+	res, err := g.service.CreateOrder(ctx, order)
+	if err != nil {
+		log.Printf("CreateOrder:%s", err)
+		return nil, apperr.GRPCStatus(err)
+	}
+	return res, nil
 }
 
 func (g *GRPCServer) GetDeliveryServicesList(ctx context.Context, in *emptypb.Empty) (*courierProto.ServicesResponse, error) {
-	res, err := g.service.GetServices(in)
+	res, err := g.service.GetServices(ctx, in)
 	if err != nil {
-		log.Fatalf("GetServices:%s", err)
-		return nil, fmt.Errorf("GetServices:%w", err)
+		log.Printf("GetServices:%s", err)
+		return nil, apperr.GRPCStatus(err)
 	}
 	return res, nil
 }
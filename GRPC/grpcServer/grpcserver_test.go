@@ -0,0 +1,93 @@
+package grpcServer
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthProto "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"net"
+	courierProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T, get *mock_service.MockAllProjectApp) (*Server, *grpc.ClientConn) {
+	s, err := NewGRPCServer(&service.Service{AllProjectApp: get}, Config{
+		Tokens: map[string]string{"s3cret": "orders"},
+		Allow:  map[string][]string{"/courier.CourierServer/CreateOrder": {"orders"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return s, conn
+}
+
+func TestServer_Health(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	s, conn := startServer(t, mock_service.NewMockAllProjectApp(c))
+	res, err := healthProto.NewHealthClient(conn).Check(context.Background(), &healthProto.HealthCheckRequest{Service: serviceName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetStatus() != healthProto.HealthCheckResponse_SERVING {
+		t.Fatalf("got %s, want SERVING", res.GetStatus())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_ErrorCodes(t *testing.T) {
+	testTable := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{name: "OK", code: codes.OK},
+		{name: "Invalid order", err: fmt.Errorf("Error in OrderService: %w", service.ErrInvalidOrder), code: codes.InvalidArgument},
+		{name: "Database error", err: fmt.Errorf("CreateOrder:connection refused"), code: codes.Internal},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			var res *emptypb.Empty
+			if testCase.err == nil {
+				res = &emptypb.Empty{}
+			}
+			get.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(res, testCase.err)
+
+			_, conn := startServer(t, get)
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer s3cret")
+			_, err := courierProto.NewCourierServerClient(conn).CreateOrder(ctx, &courierProto.OrderCourierServer{})
+			if status.Code(err) != testCase.code {
+				t.Fatalf("got %v, want %s", err, testCase.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package grpcServer
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"log"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const (
+	requestIdHeader = "x-request-id"
+	anonymous       = "anonymous"
+	healthPrefix    = "/grpc.health.v1.Health/"
+)
+
+// call is what the interceptors learn about a call, kept in its context.
+type call struct {
+	requestId string
+	client    string
+}
+
+type callKey struct{}
+
+// RequestIdFromContext returns the request id of the call, taken from the
+// x-request-id metadata or generated.
+func RequestIdFromContext(ctx context.Context) string {
+	if c, ok := ctx.Value(callKey{}).(*call); ok {
+		return c.requestId
+	}
+	return ""
+}
+
+// ClientFromContext returns the name of the authenticated client.
+func ClientFromContext(ctx context.Context) string {
+	if c, ok := ctx.Value(callKey{}).(*call); ok {
+		return c.client
+	}
+	return ""
+}
+
+type interceptors struct {
+	cfg Config
+}
+
+func (i *interceptors) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	ctx, c := i.begin(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIdHeader, c.requestId))
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+		logCall(info.FullMethod, c, err, start)
+	}()
+	if err = i.authenticate(ctx, c, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (i *interceptors) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx, c := i.begin(ss.Context())
+	ss.SetHeader(metadata.Pairs(requestIdHeader, c.requestId))
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+		logCall(info.FullMethod, c, err, start)
+	}()
+	if err = i.authenticate(ctx, c, info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// begin takes the request id of the caller or makes one up.
+func (i *interceptors) begin(ctx context.Context) (context.Context, *call) {
+	c := &call{}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIdHeader); len(ids) > 0 {
+			c.requestId = ids[0]
+		}
+	}
+	if c.requestId == "" {
+		c.requestId = newRequestId()
+	}
+	return context.WithValue(ctx, callKey{}, c), c
+}
+
+// authenticate names the client by its certificate or its bearer token and
+// checks that it may call the method. Health checks of load balancers and
+// orchestrators come without credentials and are open to anybody; any other
+// method is only open to the clients cfg.Allow lists for it.
+func (i *interceptors) authenticate(ctx context.Context, c *call, method string) error {
+	client, err := i.identify(ctx)
+	health := strings.HasPrefix(method, healthPrefix)
+	if err != nil && health {
+		client, err = anonymous, nil
+	}
+	if err != nil {
+		return err
+	}
+	c.client = client
+	if health {
+		return nil
+	}
+	for _, name := range i.cfg.Allow[method] {
+		if name == "*" || name == client {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "client %q may not call %s", client, method)
+}
+
+func (i *interceptors) identify(ctx context.Context) (string, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token := strings.TrimPrefix(values[0], "Bearer ")
+			if token == values[0] || token == "" {
+				return "", status.Error(codes.Unauthenticated, "invalid authorization metadata")
+			}
+			for known, name := range i.cfg.Tokens {
+				if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+					return name, nil
+				}
+			}
+			return "", status.Error(codes.Unauthenticated, "unknown token")
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			if name := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName; name != "" {
+				return name, nil
+			}
+		}
+	}
+	if i.cfg.AllowAnonymous {
+		return anonymous, nil
+	}
+	return "", status.Error(codes.Unauthenticated, "client certificate or bearer token required")
+}
+
+func recovered(method string, r interface{}) error {
+	log.Printf("grpc %s: panic: %v\n%s", method, r, debug.Stack())
+	return status.Error(codes.Internal, "internal error")
+}
+
+func logCall(method string, c *call, err error, start time.Time) {
+	log.Printf("grpc %s client=%q request_id=%s code=%s duration=%s", method, c.client, c.requestId, status.Code(err), time.Since(start))
+}
+
+func newRequestId() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// serverStream hands the context with the call info to stream handlers.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
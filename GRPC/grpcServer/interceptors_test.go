@@ -0,0 +1,142 @@
+package grpcServer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+const createOrder = "/courier.CourierServer/CreateOrder"
+
+func testInterceptors() *interceptors {
+	return &interceptors{cfg: Config{
+		Tokens: map[string]string{"s3cret": "orders", "other": "restaurants"},
+		Allow:  map[string][]string{createOrder: {"orders"}},
+	}}
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func invoke(i *interceptors, ctx context.Context, method string, handler grpc.UnaryHandler) error {
+	_, err := i.unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return err
+}
+
+func ok(ctx context.Context, req interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func TestInterceptors_Authentication(t *testing.T) {
+	testTable := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		code   codes.Code
+	}{
+		{name: "Allowed client", ctx: withToken("s3cret"), method: createOrder, code: codes.OK},
+		{name: "Method not listed", ctx: withToken("s3cret"), method: "/courier.CourierServer/GetDeliveryServicesList", code: codes.PermissionDenied},
+		{name: "Health check", ctx: context.Background(), method: "/grpc.health.v1.Health/Check", code: codes.OK},
+		{name: "Health check of a client", ctx: withToken("other"), method: "/grpc.health.v1.Health/Watch", code: codes.OK},
+		{name: "Client not allowed", ctx: withToken("other"), method: createOrder, code: codes.PermissionDenied},
+		{name: "Unknown token", ctx: withToken("guess"), method: createOrder, code: codes.Unauthenticated},
+		{name: "No credentials", ctx: context.Background(), method: createOrder, code: codes.Unauthenticated},
+		{
+			name:   "Not a bearer token",
+			ctx:    metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "s3cret")),
+			method: createOrder,
+			code:   codes.Unauthenticated,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := invoke(testInterceptors(), testCase.ctx, testCase.method, ok)
+			if status.Code(err) != testCase.code {
+				t.Fatalf("got %v, want %s", err, testCase.code)
+			}
+		})
+	}
+}
+
+func TestInterceptors_ClientCertificate(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "orders"}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+	var client string
+	err := invoke(testInterceptors(), ctx, createOrder, func(ctx context.Context, req interface{}) (interface{}, error) {
+		client = ClientFromContext(ctx)
+		return nil, nil
+	})
+	if err != nil || client != "orders" {
+		t.Fatalf("got client %q, error %v", client, err)
+	}
+}
+
+func TestInterceptors_Anonymous(t *testing.T) {
+	i := &interceptors{cfg: Config{AllowAnonymous: true, Allow: map[string][]string{createOrder: {"*"}}}}
+	if err := invoke(i, context.Background(), createOrder, ok); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInterceptors_RequestId(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer s3cret", "x-request-id", "abc"))
+	var requestId string
+	invoke(testInterceptors(), ctx, createOrder, func(ctx context.Context, req interface{}) (interface{}, error) {
+		requestId = RequestIdFromContext(ctx)
+		return nil, nil
+	})
+	if requestId != "abc" {
+		t.Fatalf("got request id %q", requestId)
+	}
+
+	invoke(testInterceptors(), withToken("s3cret"), createOrder, func(ctx context.Context, req interface{}) (interface{}, error) {
+		requestId = RequestIdFromContext(ctx)
+		return nil, nil
+	})
+	if len(requestId) != 16 {
+		t.Fatalf("got generated request id %q", requestId)
+	}
+}
+
+func TestInterceptors_Recovery(t *testing.T) {
+	err := invoke(testInterceptors(), withToken("s3cret"), createOrder, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("got %v, want Internal", err)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("GRPC_CLIENT_TOKENS", "orders:s3cret, restaurants:other")
+	t.Setenv("GRPC_ALLOW", "CreateOrder=orders;/grpc.health.v1.Health/Check=*")
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != ":8091" || cfg.Tokens["s3cret"] != "orders" || cfg.Tokens["other"] != "restaurants" {
+		t.Fatalf("got %+v", cfg)
+	}
+	if got := cfg.Allow[createOrder]; len(got) != 1 || got[0] != "orders" {
+		t.Fatalf("got allow %v", cfg.Allow)
+	}
+	if got := cfg.Allow["/grpc.health.v1.Health/Check"]; len(got) != 1 || got[0] != "*" {
+		t.Fatalf("got allow %v", cfg.Allow)
+	}
+
+	t.Setenv("GRPC_CLIENT_TOKENS", "s3cret")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatal("token without a name accepted")
+	}
+}
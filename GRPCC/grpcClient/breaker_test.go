@@ -0,0 +1,52 @@
+package grpcClient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newBreaker(2, time.Second)
+	b.now = func() time.Time { return now }
+
+	b.record(true)
+	if !b.allow() || b.State() != BreakerClosed {
+		t.Fatalf("one failure opened the breaker: %s", b.State())
+	}
+	b.record(true)
+	if b.allow() || b.State() != BreakerOpen {
+		t.Fatalf("two failures didn't open the breaker: %s", b.State())
+	}
+
+	now = now.Add(time.Second)
+	if !b.allow() {
+		t.Fatal("no trial call after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("second call let through while the trial call runs")
+	}
+	b.record(true)
+	if b.allow() || b.State() != BreakerOpen {
+		t.Fatalf("failed trial call didn't open the breaker again: %s", b.State())
+	}
+
+	now = now.Add(time.Second)
+	if !b.allow() {
+		t.Fatal("no trial call after the cooldown")
+	}
+	b.record(false)
+	if !b.allow() || b.State() != BreakerClosed {
+		t.Fatalf("successful trial call didn't close the breaker: %s", b.State())
+	}
+}
+
+func TestBreaker_Disabled(t *testing.T) {
+	b := newBreaker(0, time.Second)
+	for i := 0; i < 10; i++ {
+		b.record(true)
+	}
+	if !b.allow() {
+		t.Fatal("disabled breaker opened")
+	}
+}
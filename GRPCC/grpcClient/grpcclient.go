@@ -2,44 +2,225 @@ package grpcClient
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"github.com/golang/protobuf/ptypes/empty"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
-	"log"
+	"google.golang.org/grpc/status"
+	"os"
 	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"strconv"
+	"time"
 )
 
+// ErrUnavailable is returned when the auth service can't be reached, or
+// isn't even tried because the circuit breaker is open.
+var ErrUnavailable = apperr.New(apperr.Unavailable, "auth service unavailable")
+
+type Config struct {
+	// Target is the address of the auth service, host:port.
+	Target string
+	// TLS enables transport security; CAFile, if set, replaces the system
+	// roots and ServerName overrides the name checked in the certificate.
+	TLS        bool
+	CAFile     string
+	ServerName string
+	// Retries is how many times idempotent calls are repeated after the auth
+	// service was unavailable, waiting Backoff and then twice as long each
+	// time.
+	Retries int
+	Backoff time.Duration
+	// BreakerFailures failed calls in a row open the circuit breaker for
+	// BreakerCooldown. Zero disables the breaker.
+	BreakerFailures int
+	BreakerCooldown time.Duration
+}
+
+// ConfigFromEnv reads AUTH_GRPC_TARGET (HOST:8090 by default),
+// AUTH_GRPC_TLS, AUTH_GRPC_CA_FILE, AUTH_GRPC_SERVER_NAME,
+// AUTH_GRPC_RETRIES (2), AUTH_GRPC_BACKOFF (100ms),
+// AUTH_GRPC_BREAKER_FAILURES (5) and AUTH_GRPC_BREAKER_COOLDOWN (10s).
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Target:          os.Getenv("AUTH_GRPC_TARGET"),
+		TLS:             os.Getenv("AUTH_GRPC_TLS") == "true",
+		CAFile:          os.Getenv("AUTH_GRPC_CA_FILE"),
+		ServerName:      os.Getenv("AUTH_GRPC_SERVER_NAME"),
+		Retries:         2,
+		Backoff:         100 * time.Millisecond,
+		BreakerFailures: 5,
+		BreakerCooldown: 10 * time.Second,
+	}
+	if cfg.Target == "" {
+		cfg.Target = fmt.Sprintf("%s:8090", os.Getenv("HOST"))
+	}
+	if retries, err := strconv.Atoi(os.Getenv("AUTH_GRPC_RETRIES")); err == nil && retries >= 0 {
+		cfg.Retries = retries
+	}
+	if backoff, err := time.ParseDuration(os.Getenv("AUTH_GRPC_BACKOFF")); err == nil && backoff >= 0 {
+		cfg.Backoff = backoff
+	}
+	if failures, err := strconv.Atoi(os.Getenv("AUTH_GRPC_BREAKER_FAILURES")); err == nil && failures >= 0 {
+		cfg.BreakerFailures = failures
+	}
+	if cooldown, err := time.ParseDuration(os.Getenv("AUTH_GRPC_BREAKER_COOLDOWN")); err == nil && cooldown > 0 {
+		cfg.BreakerCooldown = cooldown
+	}
+	return cfg
+}
+
 type GRPCClient struct {
-	cli authProto.AuthClient
+	cli     authProto.AuthClient
+	conn    *grpc.ClientConn
+	cfg     Config
+	breaker *breaker
 }
 
-func NewGRPCClient(host string) *GRPCClient {
-	Target := fmt.Sprintf("%s:8090", host)
-	conn, err := grpc.Dial(Target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// Dial connects to the auth service in the background; it only fails on a
+// bad configuration.
+func Dial(cfg Config) (*GRPCClient, error) {
+	creds := insecure.NewCredentials()
+	if cfg.TLS {
+		if cfg.CAFile != "" {
+			var err error
+			if creds, err = credentials.NewClientTLSFromFile(cfg.CAFile, cfg.ServerName); err != nil {
+				return nil, fmt.Errorf("auth client: %w", err)
+			}
+		} else {
+			creds = credentials.NewTLS(&tls.Config{ServerName: cfg.ServerName})
+		}
+	}
+	conn, err := grpc.Dial(cfg.Target, grpc.WithTransportCredentials(creds))
 	if err != nil {
-		log.Fatalf("NewGRPCClient, Dial:%s", err)
+		return nil, fmt.Errorf("auth client: %w", err)
+	}
+	c := newClient(authProto.NewAuthClient(conn), cfg)
+	c.conn = conn
+	return c, nil
+}
+
+// New wraps an already built auth client, e.g. a fake one in tests.
+func New(cli authProto.AuthClient) *GRPCClient {
+	return newClient(cli, Config{})
+}
+
+func newClient(cli authProto.AuthClient, cfg Config) *GRPCClient {
+	return &GRPCClient{cli: cli, cfg: cfg, breaker: newBreaker(cfg.BreakerFailures, cfg.BreakerCooldown)}
+}
+
+// Health is the state of the connection to the auth service.
+type Health struct {
+	Target     string `json:"target"`
+	Connection string `json:"connection"`
+	Breaker    string `json:"breaker"`
+	Healthy    bool   `json:"healthy"`
+}
+
+// Health reports the client unhealthy while the breaker is open or the
+// connection keeps failing.
+func (c *GRPCClient) Health() Health {
+	h := Health{Target: c.cfg.Target, Breaker: c.breaker.State()}
+	if c.conn != nil {
+		h.Connection = c.conn.GetState().String()
+	}
+	h.Healthy = h.Breaker != BreakerOpen && h.Connection != "TRANSIENT_FAILURE" && h.Connection != "SHUTDOWN"
+	return h
+}
+
+func (c *GRPCClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// call runs the call through the circuit breaker. Idempotent calls are
+// retried with backoff while the auth service is unavailable.
+func (c *GRPCClient) call(ctx context.Context, idempotent bool, do func(ctx context.Context) error) error {
+	attempts := 1
+	if idempotent {
+		attempts += c.cfg.Retries
+	}
+	backoff := c.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		if !c.breaker.allow() {
+			return fmt.Errorf("%w: circuit breaker is open", ErrUnavailable)
+		}
+		err := do(ctx)
+		failed := unavailable(err)
+		c.breaker.record(failed)
+		if !failed {
+			return err
+		}
+		if attempt >= attempts || ctx.Err() != nil {
+			return fmt.Errorf("%w: %s", ErrUnavailable, err)
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %s", ErrUnavailable, err)
+		}
+		backoff *= 2
+	}
+}
+
+// unavailable reports whether the error means the auth service couldn't
+// answer, as opposed to an answer like an invalid token.
+func unavailable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
 	}
-	cli := authProto.NewAuthClient(conn)
-	return &GRPCClient{cli: cli}
+	return false
 }
 
 func (c *GRPCClient) GetUserWithRights(ctx context.Context, in *authProto.AccessToken, opts ...grpc.CallOption) (*authProto.UserRole, error) {
-	return c.cli.GetUserWithRights(ctx, in)
+	var res *authProto.UserRole
+	err := c.call(ctx, true, func(ctx context.Context) (err error) {
+		res, err = c.cli.GetUserWithRights(ctx, in, opts...)
+		return err
+	})
+	return res, err
 }
 
+// BindUserAndRole grants the role to the user in the auth service. A binding
+// the auth service refused comes back as a result of false, not as an error.
 func (c *GRPCClient) BindUserAndRole(ctx context.Context, in *authProto.User, opts ...grpc.CallOption) (*authProto.ResultBinding, error) {
-	return nil, nil
+	var res *authProto.ResultBinding
+	err := c.call(ctx, false, func(ctx context.Context) (err error) {
+		res, err = c.cli.BindUserAndRole(ctx, in, opts...)
+		return err
+	})
+	return res, err
 }
 
 func (c *GRPCClient) TokenGenerationByRefresh(ctx context.Context, in *authProto.RefreshToken, opts ...grpc.CallOption) (*authProto.GeneratedTokens, error) {
-	return nil, nil
+	var res *authProto.GeneratedTokens
+	err := c.call(ctx, false, func(ctx context.Context) (err error) {
+		res, err = c.cli.TokenGenerationByRefresh(ctx, in, opts...)
+		return err
+	})
+	return res, err
 }
 
 func (c *GRPCClient) TokenGenerationByUserId(ctx context.Context, in *authProto.User, opts ...grpc.CallOption) (*authProto.GeneratedTokens, error) {
-	return nil, nil
+	var res *authProto.GeneratedTokens
+	err := c.call(ctx, false, func(ctx context.Context) (err error) {
+		res, err = c.cli.TokenGenerationByUserId(ctx, in, opts...)
+		return err
+	})
+	return res, err
 }
 
 func (c *GRPCClient) GetAllRoles(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*authProto.Roles, error) {
-	return nil, nil
+	var res *authProto.Roles
+	err := c.call(ctx, true, func(ctx context.Context) (err error) {
+		res, err = c.cli.GetAllRoles(ctx, in, opts...)
+		return err
+	})
+	return res, err
 }
@@ -0,0 +1,103 @@
+package grpcClient
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"testing"
+	"time"
+)
+
+type fakeAuth struct {
+	authProto.AuthClient
+	errs  []error
+	calls int
+}
+
+func (f *fakeAuth) next() error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakeAuth) GetUserWithRights(ctx context.Context, in *authProto.AccessToken, opts ...grpc.CallOption) (*authProto.UserRole, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &authProto.UserRole{UserId: 1}, nil
+}
+
+func (f *fakeAuth) BindUserAndRole(ctx context.Context, in *authProto.User, opts ...grpc.CallOption) (*authProto.ResultBinding, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &authProto.ResultBinding{Result: true}, nil
+}
+
+var errDown = status.Error(codes.Unavailable, "connection refused")
+
+func testClient(auth *fakeAuth) *GRPCClient {
+	return newClient(auth, Config{Retries: 2, Backoff: time.Millisecond, BreakerFailures: 3, BreakerCooldown: time.Minute})
+}
+
+func TestGRPCClient_RetriesIdempotentCalls(t *testing.T) {
+	auth := &fakeAuth{errs: []error{errDown, errDown}}
+	user, err := testClient(auth).GetUserWithRights(context.Background(), &authProto.AccessToken{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.GetUserId() != 1 || auth.calls != 3 {
+		t.Fatalf("got user %v after %d calls", user, auth.calls)
+	}
+}
+
+func TestGRPCClient_GivesUp(t *testing.T) {
+	auth := &fakeAuth{errs: []error{errDown, errDown, errDown}}
+	_, err := testClient(auth).GetUserWithRights(context.Background(), &authProto.AccessToken{})
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got %v, want ErrUnavailable", err)
+	}
+	if auth.calls != 3 {
+		t.Fatalf("%d calls, want 3", auth.calls)
+	}
+}
+
+func TestGRPCClient_DoesNotRetryOtherCalls(t *testing.T) {
+	auth := &fakeAuth{errs: []error{errDown}}
+	_, err := testClient(auth).BindUserAndRole(context.Background(), &authProto.User{})
+	if !errors.Is(err, ErrUnavailable) || auth.calls != 1 {
+		t.Fatalf("got %v after %d calls", err, auth.calls)
+	}
+}
+
+func TestGRPCClient_DoesNotRetryRejectedTokens(t *testing.T) {
+	auth := &fakeAuth{errs: []error{status.Error(codes.Unauthenticated, "token is expired")}}
+	_, err := testClient(auth).GetUserWithRights(context.Background(), &authProto.AccessToken{})
+	if err == nil || errors.Is(err, ErrUnavailable) || auth.calls != 1 {
+		t.Fatalf("got %v after %d calls", err, auth.calls)
+	}
+}
+
+func TestGRPCClient_FailsFastWhenOpen(t *testing.T) {
+	auth := &fakeAuth{errs: []error{errDown, errDown, errDown}}
+	c := testClient(auth)
+	c.GetUserWithRights(context.Background(), &authProto.AccessToken{})
+
+	_, err := c.GetUserWithRights(context.Background(), &authProto.AccessToken{})
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got %v, want ErrUnavailable", err)
+	}
+	if auth.calls != 3 {
+		t.Fatalf("open breaker let a call through: %d calls", auth.calls)
+	}
+	if h := c.Health(); h.Healthy || h.Breaker != BreakerOpen {
+		t.Fatalf("got health %+v", h)
+	}
+}
@@ -0,0 +1,346 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/migrations"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"strconv"
+	"text/tabwriter"
+)
+
+const usage = `usage: courierctl <command> [flags]
+
+commands:
+  service create -name NAME -email EMAIL [-phone PHONE] [-description TEXT] [-manager USER_ID]
+  service list
+  courier register -user USER_ID -name NAME -surname SURNAME -phone PHONE [-email EMAIL] [-service ID]
+  courier deactivate -id COURIER_ID
+  courier activate -id COURIER_ID
+  courier approve -id COURIER_ID
+  courier reject -id COURIER_ID -reason TEXT
+  order reassign -id ORDER_ID -courier COURIER_ID
+  order status -id ORDER_ID -status STATUS
+  report services
+  report completed -service ID [-limit N] [-page N]
+  report expiring -service ID [-days N]
+  documents check [-days N]
+  migrate [up | down [steps] | version]
+`
+
+var ErrUsage = errors.New("wrong usage")
+
+// CLI runs administrative commands through the same service layer the HTTP
+// handlers use, so operators get the same validation as API clients.
+type CLI struct {
+	services service.AllProjectApp
+	db       *sql.DB
+	out      io.Writer
+}
+
+func New(services service.AllProjectApp, db *sql.DB, out io.Writer) *CLI {
+	return &CLI{services: services, db: db, out: out}
+}
+
+func (c *CLI) Run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(c.out, usage)
+		return ErrUsage
+	}
+	if args[0] == "migrate" {
+		return Migrate(c.db, args[1:], c.out)
+	}
+	if len(args) < 2 {
+		fmt.Fprint(c.out, usage)
+		return ErrUsage
+	}
+	switch args[0] + " " + args[1] {
+	case "service create":
+		return c.createDeliveryService(args[2:])
+	case "service list", "report services":
+		return c.reportDeliveryServices()
+	case "courier register":
+		return c.registerCourier(args[2:])
+	case "courier deactivate":
+		return c.setCourierDeleted(args[2:], true)
+	case "courier activate":
+		return c.setCourierDeleted(args[2:], false)
+	case "courier approve":
+		return c.reviewCourier(args[2:], true)
+	case "courier reject":
+		return c.reviewCourier(args[2:], false)
+	case "order reassign":
+		return c.reassignOrder(args[2:])
+	case "order status":
+		return c.changeOrderStatus(args[2:])
+	case "report completed":
+		return c.reportCompletedOrders(args[2:])
+	case "report expiring":
+		return c.reportExpiringDocuments(args[2:])
+	case "documents check":
+		return c.checkDocuments(args[2:])
+	}
+	fmt.Fprint(c.out, usage)
+	return ErrUsage
+}
+
+// Migrate handles "migrate [up | down [steps] | version]".
+func Migrate(db *sql.DB, args []string, out io.Writer) error {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	switch command {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("migrate down: expected a positive number of steps, got %q", args[1])
+			}
+		}
+		return migrator.Down(ctx, steps)
+	case "version":
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "schema version: %d\n", version)
+		return nil
+	default:
+		return fmt.Errorf("migrate: unknown command %q, expected up, down or version", command)
+	}
+}
+
+func (c *CLI) flags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	return flags
+}
+
+func (c *CLI) createDeliveryService(args []string) error {
+	var deliveryService dao.DeliveryService
+	flags := c.flags("service create")
+	flags.StringVar(&deliveryService.Name, "name", "", "delivery service name")
+	flags.StringVar(&deliveryService.Email, "email", "", "contact email")
+	flags.StringVar(&deliveryService.PhoneNumber, "phone", "", "contact phone number")
+	flags.StringVar(&deliveryService.Description, "description", "", "description")
+	flags.IntVar(&deliveryService.ManagerId, "manager", 0, "user id of the courier manager")
+	flags.StringVar(&deliveryService.Status, "status", "active", "status")
+	if err := flags.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if deliveryService.Name == "" || deliveryService.Email == "" {
+		return errors.New("service create: -name and -email are required")
+	}
+	id, err := c.services.CreateDeliveryService(context.Background(), deliveryService)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "delivery service %d created\n", id)
+	return nil
+}
+
+func (c *CLI) registerCourier(args []string) error {
+	var courier dao.Courier
+	var userId, serviceId int
+	flags := c.flags("courier register")
+	flags.IntVar(&userId, "user", 0, "user id in the auth service")
+	flags.StringVar(&courier.CourierName, "name", "", "name")
+	flags.StringVar(&courier.Surname, "surname", "", "surname")
+	flags.StringVar(&courier.PhoneNumber, "phone", "", "phone number")
+	flags.StringVar(&courier.Email, "email", "", "email")
+	flags.IntVar(&serviceId, "service", 0, "delivery service id")
+	if err := flags.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if userId <= 0 || courier.CourierName == "" || courier.Surname == "" || courier.PhoneNumber == "" {
+		return errors.New("courier register: -user, -name, -surname and -phone are required")
+	}
+	courier.UserId = userId
+	courier.DeliveryServiceId = uint16(serviceId)
+	if _, err := c.services.SaveCourier(context.Background(), &courier); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "courier %s %s registered\n", courier.CourierName, courier.Surname)
+	return nil
+}
+
+func (c *CLI) setCourierDeleted(args []string, deleted bool) error {
+	var id int
+	flags := c.flags("courier")
+	flags.IntVar(&id, "id", 0, "courier id")
+	if err := flags.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if id <= 0 {
+		return errors.New("courier: -id is required")
+	}
+	courierId, err := c.services.UpdateCourier(context.Background(), uint16(id), deleted)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		fmt.Fprintf(c.out, "courier %d deactivated\n", courierId)
+	} else {
+		fmt.Fprintf(c.out, "courier %d activated\n", courierId)
+	}
+	return nil
+}
+
+func (c *CLI) reviewCourier(args []string, approve bool) error {
+	var id int
+	var reason string
+	flags := c.flags("courier")
+	flags.IntVar(&id, "id", 0, "courier id")
+	flags.StringVar(&reason, "reason", "", "reason of the decision")
+	if err := flags.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if id <= 0 {
+		return errors.New("courier: -id is required")
+	}
+	if err := c.services.ReviewCourierApplication(context.Background(), id, approve, reason); err != nil {
+		return err
+	}
+	if approve {
+		fmt.Fprintf(c.out, "courier %d approved\n", id)
+	} else {
+		fmt.Fprintf(c.out, "courier %d rejected\n", id)
+	}
+	return nil
+}
+
+func (c *CLI) reassignOrder(args []string) error {
+	var order dao.Order
+	flags := c.flags("order reassign")
+	flags.IntVar(&order.Id, "id", 0, "order id")
+	flags.IntVar(&order.IdCourier, "courier", 0, "courier id")
+	if err := flags.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if order.Id <= 0 || order.IdCourier <= 0 {
+		return errors.New("order reassign: -id and -courier are required")
+	}
+	if err := c.services.AssigningOrderToCourier(context.Background(), order); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "order %d assigned to courier %d\n", order.Id, order.IdCourier)
+	return nil
+}
+
+func (c *CLI) changeOrderStatus(args []string) error {
+	var id int
+	var status string
+	flags := c.flags("order status")
+	flags.IntVar(&id, "id", 0, "order id")
+	flags.StringVar(&status, "status", "", "new status")
+	if err := flags.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if id <= 0 || status == "" {
+		return errors.New("order status: -id and -status are required")
+	}
+	if !service.IsOrderStatus(status) {
+		return fmt.Errorf("order status: -status must be ready to delivery or completed, got %q", status)
+	}
+	// Operators change the order whatever its version.
+	orderId, err := c.services.ChangeOrderStatus(context.Background(), status, uint16(id), 0)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "order %d status changed to %q\n", orderId, status)
+	return nil
+}
+
+func (c *CLI) reportDeliveryServices() error {
+	services, err := c.services.GetAllDeliveryServices(context.Background())
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tEMAIL\tMANAGER\tSTATUS\tCOURIERS")
+	for _, s := range services {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%d\n", s.Id, s.Name, s.Email, s.ManagerId, s.Status, s.NumOfCouriers)
+	}
+	return w.Flush()
+}
+
+func (c *CLI) reportCompletedOrders(args []string) error {
+	var idService, limit, page int
+	flags := c.flags("report completed")
+	flags.IntVar(&idService, "service", 0, "delivery service id")
+	flags.IntVar(&limit, "limit", 50, "orders per page")
+	flags.IntVar(&page, "page", 1, "page")
+	if err := flags.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if idService <= 0 || limit <= 0 || page <= 0 {
+		return errors.New("report completed: -service, -limit and -page must be greater than 0")
+	}
+	orders, pagination, err := c.services.GetCompletedOrdersOfCourierService(context.Background(), limit, page, idService)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCOURIER\tORDER DATE\tDELIVERY TIME\tADDRESS")
+	for _, o := range orders {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\n", o.Id, o.IdCourier, o.OrderDate, o.DeliveryTime.Format("2006-01-02 15:04"), o.CustomerAddress)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "page %d of %d, %d completed orders\n", pagination.Page, pagination.TotalPages, pagination.Total)
+	return nil
+}
+
+func (c *CLI) reportExpiringDocuments(args []string) error {
+	var idService, days int
+	flags := c.flags("report expiring")
+	flags.IntVar(&idService, "service", 0, "delivery service id")
+	flags.IntVar(&days, "days", 30, "days ahead")
+	if err := flags.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if idService <= 0 || days < 0 {
+		return errors.New("report expiring: -service must be greater than 0 and -days not negative")
+	}
+	documents, err := c.services.GetExpiringDocuments(context.Background(), idService, days)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COURIER\tNAME\tDOCUMENT\tNUMBER\tEXPIRES")
+	for _, d := range documents {
+		fmt.Fprintf(w, "%d\t%s %s\t%s\t%s\t%s\n", d.CourierId, d.CourierName, d.CourierSurname, d.Type, d.Number, d.ExpiryDate)
+	}
+	return w.Flush()
+}
+
+// checkDocuments runs the scheduled document job once, for deployments that
+// prefer cron to the job built into the service.
+func (c *CLI) checkDocuments(args []string) error {
+	var days int
+	flags := c.flags("documents check")
+	flags.IntVar(&days, "days", 30, "warn about documents expiring within this many days")
+	if err := flags.Parse(args); err != nil {
+		return ErrUsage
+	}
+	if err := c.services.CheckDocumentExpiry(context.Background(), days); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, "documents checked")
+	return nil
+}
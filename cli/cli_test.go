@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+	"time"
+)
+
+func TestCLI_Run(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name           string
+		args           []string
+		mockBehavior   mockBehavior
+		expectedOutput string
+		expectedErr    bool
+	}{
+		{
+			name: "Create delivery service",
+			args: []string{"service", "create", "-name", "Fast", "-email", "fast@mail.com", "-manager", "4"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().CreateDeliveryService(gomock.Any(), dao.DeliveryService{Name: "Fast", Email: "fast@mail.com", ManagerId: 4, Status: "active"}).Return(3, nil)
+			},
+			expectedOutput: "delivery service 3 created\n",
+		},
+		{
+			name:         "Create delivery service without email",
+			args:         []string{"service", "create", "-name", "Fast"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {},
+			expectedErr:  true,
+		},
+		{
+			name: "Register courier",
+			args: []string{"courier", "register", "-user", "9", "-name", "Ivan", "-surname", "Petrov", "-phone", "+375291234567", "-service", "3"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				courier := &dao.Courier{UserId: 9, CourierName: "Ivan", Surname: "Petrov", PhoneNumber: "+375291234567", DeliveryServiceId: 3}
+				s.EXPECT().SaveCourier(gomock.Any(), courier).Return(courier, nil)
+			},
+			expectedOutput: "courier Ivan Petrov registered\n",
+		},
+		{
+			name: "Deactivate courier",
+			args: []string{"courier", "deactivate", "-id", "5"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().UpdateCourier(gomock.Any(), uint16(5), true).Return(uint16(5), nil)
+			},
+			expectedOutput: "courier 5 deactivated\n",
+		},
+		{
+			name: "Reject courier",
+			args: []string{"courier", "reject", "-id", "5", "-reason", "photo of the ID card is unreadable"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ReviewCourierApplication(gomock.Any(), 5, false, "photo of the ID card is unreadable").Return(nil)
+			},
+			expectedOutput: "courier 5 rejected\n",
+		},
+		{
+			name: "Report expiring documents",
+			args: []string{"report", "expiring", "-service", "3", "-days", "14"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().GetExpiringDocuments(gomock.Any(), 3, 14).Return([]dao.ExpiringDocument{{
+					CourierDocument: dao.CourierDocument{CourierId: 5, Type: "driving_licence", Number: "AB123", ExpiryDate: dao.NewDate(2022, 3, 1)},
+					CourierName:     "Ivan", CourierSurname: "Petrov",
+				}}, nil)
+			},
+			expectedOutput: "COURIER  NAME         DOCUMENT         NUMBER  EXPIRES\n5        Ivan Petrov  driving_licence  AB123   2022-03-01\n",
+		},
+		{
+			name: "Check documents",
+			args: []string{"documents", "check", "-days", "7"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().CheckDocumentExpiry(gomock.Any(), 7).Return(nil)
+			},
+			expectedOutput: "documents checked\n",
+		},
+		{
+			name: "Reassign order",
+			args: []string{"order", "reassign", "-id", "12", "-courier", "5"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().AssigningOrderToCourier(gomock.Any(), dao.Order{Id: 12, IdCourier: 5}).Return(nil)
+			},
+			expectedOutput: "order 12 assigned to courier 5\n",
+		},
+		{
+			name: "Force order status",
+			args: []string{"order", "status", "-id", "12", "-status", "completed"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ChangeOrderStatus(gomock.Any(), "completed", uint16(12), 0).Return(uint16(12), errors.New("Error in OrderService: no order"))
+			},
+			expectedErr: true,
+		},
+		{
+			name:         "Unknown order status",
+			args:         []string{"order", "status", "-id", "12", "-status", "lost"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {},
+			expectedErr:  true,
+		},
+		{
+			name: "Report completed orders",
+			args: []string{"report", "completed", "-service", "3", "-limit", "1"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().GetCompletedOrdersOfCourierService(gomock.Any(), 1, 1, 3).Return([]dao.Order{
+					{Id: 12, IdCourier: 5, OrderDate: "2022-02-02", DeliveryTime: time.Date(2022, 2, 2, 13, 30, 0, 0, time.UTC), CustomerAddress: "Main st. 1"},
+				}, dao.NewPagination(1, 1, 2), nil)
+			},
+			expectedOutput: "ID  COURIER  ORDER DATE  DELIVERY TIME     ADDRESS\n" +
+				"12  5        2022-02-02  2022-02-02 13:30  Main st. 1\n" +
+				"page 1 of 2, 2 completed orders\n",
+		},
+		{
+			name:         "Unknown command",
+			args:         []string{"courier", "fire"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {},
+			expectedErr:  true,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			services := mock_service.NewMockAllProjectApp(c)
+			tt.mockBehavior(services)
+			var out bytes.Buffer
+
+			err := New(services, nil, &out).Run(tt.args)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOutput, out.String())
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	_ "github.com/lib/pq"
+	"log"
+	"os"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC/grpcClient"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/cli"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/database"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/storage"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+)
+
+// courierctl is the administrative tool for operators. It reads the same
+// environment variables as the server.
+func main() {
+	databases, err := database.NewPostgresDB(database.PostgresDB{
+		Host:     os.Getenv("HOST"),
+		Port:     os.Getenv("DB_PORT"),
+		User:     os.Getenv("DB_USER"),
+		Password: os.Getenv("DB_PASSWORD"),
+		DBName:   os.Getenv("DB_DATABASE"),
+		SSLMode:  os.Getenv("DB_SSL_MODE")})
+	if err != nil {
+		log.Fatal("failed to initialize dao:", err.Error())
+	}
+	defer databases.Close()
+	grpcCli, err := grpcClient.Dial(grpcClient.ConfigFromEnv())
+	if err != nil {
+		log.Fatal("failed to initialize auth client:", err.Error())
+	}
+	defer grpcCli.Close()
+	store, err := storage.New(storage.ConfigFromEnv())
+	if err != nil {
+		log.Fatal("failed to initialize storage:", err.Error())
+	}
+	repository := dao.NewRepository(databases, dao.ConfigFromEnv())
+	services := service.NewService(repository, grpcCli, store)
+
+	if err := cli.New(services, databases, os.Stdout).Run(os.Args[1:]); err != nil {
+		if !errors.Is(err, cli.ErrUsage) {
+			log.Println(err)
+		}
+		os.Exit(1)
+	}
+}
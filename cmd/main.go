@@ -2,20 +2,32 @@ package main
 
 import (
 	"context"
+	"errors"
+	"expvar"
 	_ "github.com/lib/pq"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/GRPC/grpcServer"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC/grpcClient"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/cli"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/database"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/storage"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/server"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"strconv"
 	"syscall"
+	"time"
 )
 
+// shutdownTimeout bounds how long running requests and calls may finish on
+// SIGTERM.
+const shutdownTimeout = 15 * time.Second
+
 // @title Courier Service
 // @description Courier Service for Food Delivery Application
 // @securityDefinitions.apikey ApiKeyAuth
@@ -33,9 +45,33 @@ func main() {
 	if err != nil {
 		log.Fatal("failed to initialize dao:", err.Error())
 	}
-	grpcCli := grpcClient.NewGRPCClient(os.Getenv("HOST"))
-	repository := dao.NewRepository(databases)
-	services := service.NewService(repository, grpcCli)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := cli.Migrate(databases, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Error occured while migrating database: %s", err.Error())
+		}
+		return
+	}
+	if os.Getenv("DB_AUTO_MIGRATE") != "false" {
+		if err := cli.Migrate(databases, []string{"up"}, os.Stdout); err != nil {
+			log.Fatalf("Error occured while migrating database: %s", err.Error())
+		}
+	}
+	grpcCli, err := grpcClient.Dial(grpcClient.ConfigFromEnv())
+	if err != nil {
+		log.Fatal("failed to initialize auth client:", err.Error())
+	}
+	defer grpcCli.Close()
+	store, err := storage.New(storage.ConfigFromEnv())
+	if err != nil {
+		log.Fatal("failed to initialize storage:", err.Error())
+	}
+	repository := dao.NewRepository(databases, dao.ConfigFromEnv())
+	services := service.NewService(repository, grpcCli, store)
+	services.Policy, err = loadPolicy(services)
+	if err != nil {
+		log.Fatal("failed to load the authorisation policy:", err.Error())
+	}
+	expvar.Publish("token_cache", expvar.Func(func() interface{} { return services.TokenCacheStats() }))
 	handlers := controller.NewHandler(services)
 	port := os.Getenv("API_SERVER_PORT")
 
@@ -43,19 +79,79 @@ func main() {
 
 	go func() {
 		err := serv.Run(port, handlers.InitRoutesGin())
-		if err != nil {
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("Error occured while running http server: %s", err.Error())
 		}
 	}()
+	grpcCfg, err := grpcServer.ConfigFromEnv()
+	if err != nil {
+		log.Fatal("failed to configure grpc server:", err.Error())
+	}
+	grpcSrv, err := grpcServer.NewGRPCServer(services, grpcCfg)
+	if err != nil {
+		log.Fatal("failed to initialize grpc server:", err.Error())
+	}
 	go func() {
-		grpcServer.NewGRPCServer(services)
+		if err := grpcSrv.Run(); err != nil {
+			log.Fatalf("Error occured while running grpc server: %s", err.Error())
+		}
 	}()
+	go runDocumentExpiryJob(services)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 	<-quit
-	if err := serv.Shutdown(context.Background()); err != nil {
-		log.Fatalf("Error occured while shutting down http server: %s", err.Error())
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := serv.Shutdown(ctx); err != nil {
+		log.Printf("Error occured while shutting down http server: %s", err.Error())
 	}
+	if err := grpcSrv.Shutdown(ctx); err != nil {
+		log.Printf("Error occured while shutting down grpc server: %s", err.Error())
+	}
+}
 
+// loadPolicy reads the authorisation policy from POLICY_FILE, if set, and
+// with POLICY_ROLES_FROM_AUTH=true takes the permissions of the roles from
+// the auth service. The built-in policy is used otherwise.
+func loadPolicy(services *service.Service) (*policy.Policy, error) {
+	p := policy.Default()
+	if path := os.Getenv("POLICY_FILE"); path != "" {
+		var err error
+		if p, err = policy.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if os.Getenv("POLICY_ROLES_FROM_AUTH") == "true" {
+		roles, err := services.GetRolePermissions(context.Background())
+		if err != nil {
+			log.Printf("policy: keeping the configured roles: %s", err)
+			return p, nil
+		}
+		p.WithRoles(roles)
+	}
+	return p, nil
+}
+
+// runDocumentExpiryJob stops couriers whose mandatory documents have expired
+// and warns managers about documents expiring within
+// DOCUMENT_EXPIRY_WARNING_DAYS (30 by default). It runs on start and then
+// every DOCUMENT_EXPIRY_CHECK_INTERVAL (24h by default).
+func runDocumentExpiryJob(services *service.Service) {
+	days, err := strconv.Atoi(os.Getenv("DOCUMENT_EXPIRY_WARNING_DAYS"))
+	if err != nil || days < 0 {
+		days = 30
+	}
+	interval, err := time.ParseDuration(os.Getenv("DOCUMENT_EXPIRY_CHECK_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := services.CheckDocumentExpiry(context.Background(), days); err != nil {
+			log.Println(err)
+		}
+		<-ticker.C
+	}
 }
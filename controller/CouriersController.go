@@ -1,12 +1,13 @@
 package controller
 
 import (
-	"fmt"
 	"github.com/gin-gonic/gin"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
 	"strconv"
 )
 
@@ -14,26 +15,80 @@ type delete struct {
 	Status bool `json:"deleted"`
 }
 
+// courierRequest is the body of POST /courier. The user id is taken from the
+// token when couriers register themselves.
+type courierRequest struct {
+	UserId            int    `json:"user_id" binding:"omitempty,min=1"`
+	CourierName       string `json:"courier_name" binding:"required"`
+	Surname           string `json:"surname"`
+	PhoneNumber       string `json:"phone_number" binding:"omitempty,phone"`
+	Email             string `json:"email" binding:"omitempty,email"`
+	DeliveryServiceId uint16 `json:"delivery_service_id"`
+}
+
+func (r courierRequest) toDao() *dao.Courier {
+	return &dao.Courier{UserId: r.UserId, CourierName: r.CourierName, Surname: r.Surname,
+		PhoneNumber: r.PhoneNumber, Email: r.Email, DeliveryServiceId: r.DeliveryServiceId}
+}
+
+// courierUpdateRequest is the body of PUT /courier/{id}; fields left out keep
+// their value.
+type courierUpdateRequest struct {
+	CourierName       string `json:"courier_name"`
+	Surname           string `json:"surname"`
+	PhoneNumber       string `json:"phone_number" binding:"omitempty,phone"`
+	Email             string `json:"email" binding:"omitempty,email"`
+	DeliveryServiceId uint16 `json:"delivery_service_id"`
+}
+
+func (r courierUpdateRequest) toDao(id int) dao.Courier {
+	return dao.Courier{Id: uint16(id), CourierName: r.CourierName, Surname: r.Surname,
+		PhoneNumber: r.PhoneNumber, Email: r.Email, DeliveryServiceId: r.DeliveryServiceId}
+}
+
+// courierPatch is the body of PATCH /courier/{id}, a JSON merge patch:
+// fields left out keep their value and null clears them.
+type courierPatch struct {
+	CourierName       dao.PatchString `json:"courier_name"`
+	Surname           dao.PatchString `json:"surname"`
+	PhoneNumber       dao.PatchString `json:"phone_number" binding:"omitempty,phone"`
+	Email             dao.PatchString `json:"email" binding:"omitempty,email"`
+	DeliveryServiceId dao.PatchInt    `json:"delivery_service_id"`
+	Deleted           dao.PatchBool   `json:"deleted"`
+}
+
+func (p courierPatch) toDao() dao.CourierPatch {
+	return dao.CourierPatch{CourierName: p.CourierName, Surname: p.Surname, PhoneNumber: p.PhoneNumber,
+		Email: p.Email, DeliveryServiceId: p.DeliveryServiceId, Deleted: p.Deleted}
+}
+
 // GetCouriers godoc
 // @Summary GetCouriers
-// @Description get all couriers
+// @Description get the couriers of the caller's delivery service, or of all delivery services for Superadmin
 // @Tags Couriers
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} dao.SmallInfo
 // @Failure 400 {object} string
+// @Failure 403 {object} string
 // @Failure 500 {object} string
 // @Router /couriers [get]
 func (h *Handler) GetCouriers(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetCouriers:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.ListCouriers) {
+		return
+	}
+	caller, ok := h.caller(ctx)
+	if !ok {
 		return
 	}
-	Couriers, err := h.services.GetCouriers()
+	idService := caller.DeliveryServiceId
+	if !caller.IsSuperadmin() && idService == 0 {
+		fail(ctx, service.ErrNoTenant)
+		return
+	}
+	Couriers, err := h.services.GetCouriers(ctx.Request.Context(), idService)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": err})
+		fail(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, Couriers)
@@ -48,57 +103,82 @@ func (h *Handler) GetCouriers(ctx *gin.Context) {
 // @Produce  json
 // @Param id path int true "Courier ID"
 // @Success 200 {object} dao.Courier
+// @Header 200 {string} ETag "version of the courier, for If-Match"
 // @Failure 400 {object} string
+// @Failure 403 {object} string
 // @Failure 500 {object} string
 // @Router /courier/{id} [get]
 func (h *Handler) GetCourier(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetCourier:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.GetCourier) {
 		return
 	}
 	idQuery := ctx.Param("id")
 	id, err := strconv.Atoi(idQuery)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": err})
+		badRequest(ctx, err.Error())
 		return
 	}
-	Courier, err := h.services.GetCourier(id)
+	Courier, err := h.services.GetCourier(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"No such courier": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
+		return
+	}
+	caller, ok := h.caller(ctx)
+	if !ok {
 		return
 	}
+	if !caller.CanAccessCourier(int(Courier.Id), int(Courier.DeliveryServiceId)) {
+		fail(ctx, apperr.Errorf("courier %w", service.ErrOtherTenant))
+		return
+	}
+	setETag(ctx, Courier.Version)
 	ctx.JSON(http.StatusOK, Courier)
 }
 
 // SaveCourier godoc
 // @Summary SaveCourier
 // @Security ApiKeyAuth
-// @Description post new courier
+// @Description register a courier applicant and grant the user the Courier role in the auth service. The courier starts in the "applied" onboarding status and takes orders once the application is approved; couriers register themselves without a delivery service, managers register couriers of their own delivery service. A user has one courier at most, and a manager of a delivery service can't be made a courier
 // @Tags Courier
 // @Accept  json
 // @Produce  json
-// @Param input body dao.Courier true "Courier"
-// @Success 200 {object} dao.Courier
+// @Param input body courierRequest true "Courier"
+// @Success 201 {object} dao.Courier
 // @Failure 400 {object} string
-// @Failure 500 {object} string
+// @Failure 403 {object} string
+// @Failure 409 {object} string
+// @Failure 502 {object} string
 // @Router /courier [post]
 func (h *Handler) SaveCourier(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler SaveCourier:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.SaveCourier) {
 		return
 	}
-	var Courier *dao.Courier
-	if err := ctx.ShouldBindJSON(&Courier); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
+	var input courierRequest
+	if !bindJSON(ctx, &input) {
 		return
 	}
-	Courier, err := h.services.SaveCourier(Courier)
+	Courier := input.toDao()
+	if ctx.GetString("role") == "Courier" {
+		// Applicants register themselves and join a delivery service when
+		// Superadmin assigns them to one.
+		Courier.UserId = getUserId(ctx)
+		Courier.DeliveryServiceId = 0
+	}
+	caller, ok := h.caller(ctx)
+	if !ok {
+		return
+	}
+	if caller.Role == "Courier manager" {
+		if caller.DeliveryServiceId == 0 {
+			fail(ctx, service.ErrNoTenant)
+			return
+		}
+		Courier.DeliveryServiceId = uint16(caller.DeliveryServiceId)
+	}
+	Courier, err := h.services.SaveCourier(ctx.Request.Context(), Courier)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err})
+		log.Println(err)
+		fail(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, Courier)
@@ -115,13 +195,11 @@ func (h *Handler) SaveCourier(ctx *gin.Context) {
 // @Param input body bool true "deleted"
 // @Success 200 {object} dao.Courier
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /courier/{id} [put]
 func (h *Handler) UpdateCourier(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler UpdateCourier:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.DeleteCourier) {
 		return
 	}
 	idQuery := ctx.Param("id")
@@ -129,8 +207,7 @@ func (h *Handler) UpdateCourier(ctx *gin.Context) {
 	var txt delete
 	var status bool
 
-	if err := ctx.ShouldBindJSON(&txt); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
+	if !bindJSON(ctx, &txt) {
 		return
 	}
 
@@ -138,12 +215,15 @@ func (h *Handler) UpdateCourier(ctx *gin.Context) {
 
 	id, err := strconv.Atoi(idQuery)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"Error with query parameter": err})
+		badRequest(ctx, err.Error())
 		return
 	}
-	courierId, err := h.services.UpdateCourier(uint16(id), status)
+	if !h.checkCourier(ctx, id) {
+		return
+	}
+	courierId, err := h.services.UpdateCourier(ctx.Request.Context(), uint16(id), status)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"No such courier": err})
+		fail(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, map[string]interface{}{
@@ -153,38 +233,37 @@ func (h *Handler) UpdateCourier(ctx *gin.Context) {
 // SaveCourierPhoto godoc
 // @Summary SaveCourierPhoto
 // @Security ApiKeyAuth
-// @Description set photo to DO Spaces, and it's way to DB
+// @Description set photo to DO Spaces, and it's way to DB. JPEG, PNG and GIF up to 10 MB are accepted; metadata is stripped and medium and thumbnail copies are stored too
 // @Tags Couriers
-// @Accept  image/jpeg
+// @Accept  image/jpeg,image/png,image/gif
 // @Produce  json
 // @Param id query int true "id courier"
 // @Param logo  formData  file  true  "logo image"
 // @Success 204
 // @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 413 {string} string
+// @Failure 415 {string} string
 // @Router /couriers/photo [post]
 func (h *Handler) SaveCourierPhoto(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler SaveCourierPhoto:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.SaveCourierPhoto) {
 		return
 	}
 	id, er := strconv.Atoi(ctx.Query("id"))
 	if er != nil || id <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 0"})
+		badRequest(ctx, "expect an integer greater than 0")
 		return
 	}
-	if ctx.Request.Body == nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "empty"})
+	if !h.checkCourier(ctx, id) {
+		return
 	}
-	defer ctx.Request.Body.Close()
-	cover, err := ioutil.ReadAll(ctx.Request.Body)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
+	cover, ok := readImageUpload(ctx)
+	if !ok {
+		return
 	}
-	if err := h.services.SaveCourierPhoto(cover, id); err != nil {
+	if err := h.services.SaveCourierPhoto(ctx.Request.Context(), cover, id); err != nil {
 		log.Println(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
 	ctx.Status(http.StatusNoContent)
@@ -192,6 +271,7 @@ func (h *Handler) SaveCourierPhoto(ctx *gin.Context) {
 
 type listCouriers struct {
 	Data []dao.Courier `json:"data"`
+	dao.Pagination
 }
 
 // GetCouriersOfCourierService godoc
@@ -201,80 +281,141 @@ type listCouriers struct {
 // @Tags Couriers
 // @Produce  json
 // @Param page query int true "page"
-// @Param limit query int true "limit"
-// @Param iddeliveryservice query int true "iddeliveryservice"
+// @Param limit query int true "limit, up to 100"
+// @Param iddeliveryservice query int false "delivery service, required for Superadmin"
 // @Success 200 {object} listCouriers
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /couriers/service [get]
 func (h *Handler) GetCouriersOfCourierService(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetCouriersOfCourierService:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
-		return
-	}
-	page, er := strconv.Atoi(ctx.Query("page"))
-	if er != nil || page == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "page query param is wrong. Expected an integer greater than 0"})
+	if !h.authorize(ctx, policy.ListCouriers) {
 		return
 	}
-	limit, er1 := strconv.Atoi(ctx.Query("limit"))
-	if er1 != nil || limit == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "limit query param is wrong. Expected an integer greater than 0"})
+	var query pageQuery
+	if !bindQuery(ctx, &query) {
 		return
 	}
-	idService, er := strconv.Atoi(ctx.Query("iddeliveryservice"))
-	if er != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 0"})
+	idService, ok := h.scopedDeliveryService(ctx)
+	if !ok {
 		return
 	}
 
-	Couriers, err := h.services.GetCouriersOfCourierService(limit, page, idService)
+	Couriers, pagination, err := h.services.GetCouriersOfCourierService(ctx.Request.Context(), query.Limit, query.Page, idService)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, listCouriers{Data: Couriers})
+	ctx.JSON(http.StatusOK, listCouriers{Data: Couriers, Pagination: pagination})
 
 }
 
 // NewUpdateCourier godoc
 // @Summary NewUpdateCourier
 // @Security ApiKeyAuth
-// @Description put courier status by courier ID
+// @Description update the courier's name, surname, phone number and email; Superadmin can also move the courier to another delivery service
 // @Tags Courier
 // @Accept  json
 // @Produce  json
 // @Param id path int true "ID"
-// @Param input body bool true "deleted"
-// @Success 200 {object} dao.Courier
+// @Param If-Match header string true "ETag of the courier"
+// @Param input body courierUpdateRequest true "Courier"
+// @Success 204
 // @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 412 {string} string
+// @Failure 428 {string} string
 // @Failure 500 {string} string
 // @Router /courier/{id} [put]
 func (h *Handler) NewUpdateCourier(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier manager", "Courier"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetCouriers:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.UpdateCourier) {
 		return
 	}
-	var courier dao.Courier
+	var input courierUpdateRequest
 	idQuery := ctx.Param("id")
-	if err := ctx.ShouldBindJSON(&courier); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
+	if !bindJSON(ctx, &input) {
 		return
 	}
 	id, err := strconv.Atoi(idQuery)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"Error with query parameter": err})
+		badRequest(ctx, err.Error())
+		return
+	}
+	if !h.checkCourier(ctx, id) {
 		return
 	}
-	courier.Id = uint16(id)
-	if err := h.services.NewUpdateCourier(courier); err != nil {
+	version, ok := ifMatch(ctx, "courier")
+	if !ok {
+		return
+	}
+	courier := input.toDao(id)
+	courier.Version = version
+	if ctx.GetString("role") != "Superadmin" {
+		// Only Superadmin moves couriers between delivery services.
+		courier.DeliveryServiceId = 0
+	}
+	if err := h.services.NewUpdateCourier(ctx.Request.Context(), courier); err != nil {
 		log.Println(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
 	ctx.Status(http.StatusNoContent)
 }
+
+// PatchCourier godoc
+// @Summary PatchCourier
+// @Security ApiKeyAuth
+// @Description change some fields of the courier with a JSON merge patch: fields left out keep their value, and null clears surname, phone_number or email. Setting deleted needs the rights to delete couriers, and only Superadmin moves couriers to another delivery service
+// @Tags Courier
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID"
+// @Param If-Match header string true "ETag of the courier"
+// @Param input body courierPatch true "merge patch"
+// @Success 200 {object} dao.Courier
+// @Header 200 {string} ETag "new version of the courier"
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 412 {string} string
+// @Failure 428 {string} string
+// @Router /courier/{id} [patch]
+func (h *Handler) PatchCourier(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.UpdateCourier) {
+		return
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkCourier(ctx, id) {
+		return
+	}
+	version, ok := ifMatch(ctx, "courier")
+	if !ok {
+		return
+	}
+	var input courierPatch
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	if input.Deleted.Set && !h.authorize(ctx, policy.DeleteCourier) {
+		return
+	}
+	if input.DeliveryServiceId.Set && ctx.GetString("role") != "Superadmin" {
+		fail(ctx, apperr.New(apperr.Forbidden, "only Superadmin moves couriers between delivery services"))
+		return
+	}
+	patch := input.toDao()
+	patch.Version = version
+	courier, err := h.services.PatchCourier(ctx.Request.Context(), id, patch)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	setETag(ctx, courier.Version)
+	ctx.JSON(http.StatusOK, courier)
+}
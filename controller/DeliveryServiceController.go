@@ -1,49 +1,91 @@
 package controller
 
 import (
-	"errors"
-	"fmt"
 	"github.com/gin-gonic/gin"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
 	"strconv"
 )
 
+// deliveryServiceRequest is the body of POST /deliveryservice. The logo is
+// set by upload.
+type deliveryServiceRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Email       string  `json:"email" binding:"required,email"`
+	Description string  `json:"description"`
+	PhoneNumber string  `json:"phone_number" binding:"omitempty,phone"`
+	ManagerId   int     `json:"manager_id" binding:"omitempty,min=1"`
+	Status      string  `json:"status" binding:"omitempty,oneof=active inactive"`
+	CourierRate float64 `json:"courier_rate" binding:"min=0"`
+}
+
+func (r deliveryServiceRequest) toDao() dao.DeliveryService {
+	return dao.DeliveryService{Name: r.Name, Email: r.Email, Description: r.Description, PhoneNumber: r.PhoneNumber,
+		ManagerId: r.ManagerId, Status: r.Status, CourierRate: r.CourierRate}
+}
+
+// deliveryServiceUpdateRequest is the body of PUT /deliveryservice/{id};
+// fields left out keep their value.
+type deliveryServiceUpdateRequest struct {
+	Name        string  `json:"name"`
+	Email       string  `json:"email" binding:"omitempty,email"`
+	Description string  `json:"description"`
+	PhoneNumber string  `json:"phone_number" binding:"omitempty,phone"`
+	Status      string  `json:"status" binding:"omitempty,oneof=active inactive"`
+	CourierRate float64 `json:"courier_rate" binding:"min=0"`
+}
+
+func (r deliveryServiceUpdateRequest) toDao(id int) dao.DeliveryService {
+	return dao.DeliveryService{Id: id, Name: r.Name, Email: r.Email, Description: r.Description,
+		PhoneNumber: r.PhoneNumber, Status: r.Status, CourierRate: r.CourierRate}
+}
+
+// deliveryServicePatch is the body of PATCH /deliveryservice/{id}, a JSON
+// merge patch: fields left out keep their value and null clears them.
+type deliveryServicePatch struct {
+	Name        dao.PatchString `json:"name"`
+	Email       dao.PatchString `json:"email" binding:"omitempty,email"`
+	Description dao.PatchString `json:"description"`
+	PhoneNumber dao.PatchString `json:"phone_number" binding:"omitempty,phone"`
+	Status      dao.PatchString `json:"status" binding:"omitempty,oneof=active inactive"`
+	CourierRate dao.PatchFloat  `json:"courier_rate" binding:"min=0"`
+}
+
+func (p deliveryServicePatch) toDao() dao.DeliveryServicePatch {
+	return dao.DeliveryServicePatch{Name: p.Name, Email: p.Email, Description: p.Description,
+		PhoneNumber: p.PhoneNumber, Status: p.Status, CourierRate: p.CourierRate}
+}
+
 // CreateDeliveryService godoc
 // @Summary CreateDeliveryService
 // @Security ApiKeyAuth
-// @Description create a Delivery Service
+// @Description create a Delivery Service; its manager is granted the Courier manager role in the auth service. A Courier manager always creates the service for themselves
 // @Tags DeliveryService
 // @Accept  json
 // @Produce  json
-// @Param input body dao.DeliveryService true "Delivery Service"
+// @Param input body deliveryServiceRequest true "Delivery Service"
 // @Success 200 {object} dao.DeliveryService
 // @Failure 400 {object} map[string]interface{}
 // @Failure 500 {string} string
+// @Failure 502 {string} string
 // @Router /deliveryservice [post]
 func (h *Handler) CreateDeliveryService(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler CreateDeliveryService:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.CreateDeliveryService) {
 		return
 	}
-	var service dao.DeliveryService
-	if err := ctx.ShouldBindJSON(&service); err != nil {
-		log.Println(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
+	var input deliveryServiceRequest
+	if !bindJSON(ctx, &input) {
 		return
 	}
-	if service.Email == "" || service.Name == "" {
-		log.Println(errors.New("empty fields"))
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "empty fields"})
-		return
+	service := input.toDao()
+	if ctx.GetString("role") == "Courier manager" {
+		service.ManagerId = getUserId(ctx)
 	}
-	idService, err := h.services.CreateDeliveryService(service)
+	idService, err := h.services.CreateDeliveryService(ctx.Request.Context(), service)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, map[string]interface{}{"id": idService})
@@ -57,27 +99,30 @@ func (h *Handler) CreateDeliveryService(ctx *gin.Context) {
 // @Produce  json
 // @Param id path int true "id"
 // @Success 200 {object} dao.DeliveryService
+// @Header 200 {string} ETag "version of the delivery service, for If-Match"
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /deliveryservice/{id} [get]
 func (h *Handler) GetDeliveryServiceById(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetDeliveryServiceById:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.GetDeliveryService) {
 		return
 	}
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 0"})
+		badRequest(ctx, "expect an integer greater than 0")
 		return
 	}
 
-	service, err := h.services.GetDeliveryServiceById(id)
+	service, err := h.services.GetDeliveryServiceById(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
+		return
+	}
+	if !h.checkDeliveryService(ctx, service.Id) {
 		return
 	}
+	setETag(ctx, service.Version)
 	ctx.JSON(http.StatusOK, service)
 }
 
@@ -96,15 +141,12 @@ type listDeliveryServices struct {
 // @Failure 500 {string} string
 // @Router /deliveryservice [get]
 func (h *Handler) GetAllDeliveryServices(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetAllDeliveryServices:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.ListDeliveryServices) {
 		return
 	}
-	services, err := h.services.GetAllDeliveryServices()
+	services, err := h.services.GetAllDeliveryServices(ctx.Request.Context())
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, listDeliveryServices{Data: services})
@@ -118,73 +160,130 @@ func (h *Handler) GetAllDeliveryServices(ctx *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "order_id"
-// @Param input body dao.DeliveryService true "delivery service"
+// @Param If-Match header string true "ETag of the delivery service"
+// @Param input body deliveryServiceUpdateRequest true "delivery service"
 // @Success 204
 // @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 412 {string} string
+// @Failure 428 {string} string
 // @Router /deliveryservice/{id} [put]
 func (h *Handler) UpdateDeliveryService(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler UpdateDeliveryService:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.UpdateDeliveryService) {
 		return
 	}
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
 		log.Println(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		badRequest(ctx, err.Error())
 		return
 	}
-	var service dao.DeliveryService
-	if err := ctx.ShouldBindJSON(&service); err != nil {
-		log.Println(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
+	if !h.checkDeliveryService(ctx, id) {
+		return
+	}
+	version, ok := ifMatch(ctx, "delivery service")
+	if !ok {
 		return
 	}
-	service.Id = id
-	if err := h.services.UpdateDeliveryService(service); err != nil {
+	var input deliveryServiceUpdateRequest
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	deliveryService := input.toDao(id)
+	deliveryService.Version = version
+	if err := h.services.UpdateDeliveryService(ctx.Request.Context(), deliveryService); err != nil {
 		log.Println(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
 	ctx.Status(http.StatusNoContent)
 }
 
+// PatchDeliveryService godoc
+// @Summary PatchDeliveryService
+// @Security ApiKeyAuth
+// @Description change some fields of the delivery service with a JSON merge patch: fields left out keep their value, and null clears description, phone_number or courier_rate
+// @Tags DeliveryService
+// @Accept  json
+// @Produce  json
+// @Param id path int true "id"
+// @Param If-Match header string true "ETag of the delivery service"
+// @Param input body deliveryServicePatch true "merge patch"
+// @Success 200 {object} dao.DeliveryService
+// @Header 200 {string} ETag "new version of the delivery service"
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 412 {string} string
+// @Failure 428 {string} string
+// @Router /deliveryservice/{id} [patch]
+func (h *Handler) PatchDeliveryService(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.UpdateDeliveryService) {
+		return
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkDeliveryService(ctx, id) {
+		return
+	}
+	version, ok := ifMatch(ctx, "delivery service")
+	if !ok {
+		return
+	}
+	var input deliveryServicePatch
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	patch := input.toDao()
+	patch.Version = version
+	service, err := h.services.PatchDeliveryService(ctx.Request.Context(), id, patch)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	setETag(ctx, service.Version)
+	ctx.JSON(http.StatusOK, service)
+}
+
 // SaveLogoController godoc
 // @Summary SaveLogoController
 // @Security ApiKeyAuth
-// @Description set logo to DO Spaces, and it's way to DB
+// @Description set logo to DO Spaces, and it's way to DB. JPEG, PNG and GIF up to 10 MB are accepted; metadata is stripped and medium and thumbnail copies are stored too
 // @Tags DeliveryService
-// @Accept  image/jpeg
+// @Accept  image/jpeg,image/png,image/gif
 // @Produce  json
 // @Param id query int true "id delivery service"
 // @Param logo  formData  file  true  "logo image"
 // @Success 204
 // @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 413 {string} string
+// @Failure 415 {string} string
 // @Router /deliveryservice/logo [post]
 func (h *Handler) SaveLogoController(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler SaveLogoController:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.SaveLogo) {
 		return
 	}
 	id, er := strconv.Atoi(ctx.Query("id"))
 	if er != nil || id <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 0"})
+		badRequest(ctx, "expect an integer greater than 0")
 		return
 	}
-	if ctx.Request.Body == nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "empty"})
+	if !h.checkDeliveryService(ctx, id) {
+		return
 	}
-	defer ctx.Request.Body.Close()
-	cover, err := ioutil.ReadAll(ctx.Request.Body)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
+	cover, ok := readImageUpload(ctx)
+	if !ok {
+		return
 	}
-	if err := h.services.SaveLogoFile(cover, id); err != nil {
+	if err := h.services.SaveLogoFile(ctx.Request.Context(), cover, id); err != nil {
 		log.Println(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
 	ctx.Status(http.StatusNoContent)
@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"strconv"
+)
+
+const defaultExpiryWarningDays = 30
+
+type expiringQuery struct {
+	Days int `form:"days" binding:"min=0,max=365"`
+}
+
+// documentRequest is the body of PUT /courier/{id}/documents/{type}.
+type documentRequest struct {
+	Number     string `json:"number" binding:"required"`
+	IssueDate  string `json:"issue_date" binding:"required,datetime=2006-01-02"`
+	ExpiryDate string `json:"expiry_date" binding:"required,datetime=2006-01-02"`
+}
+
+func (r documentRequest) toDao(courierId int, documentType string) dao.CourierDocument {
+	// The dates are validated already.
+	issueDate, _ := dao.ParseDate(r.IssueDate)
+	expiryDate, _ := dao.ParseDate(r.ExpiryDate)
+	return dao.CourierDocument{CourierId: courierId, Type: documentType, Number: r.Number,
+		IssueDate: issueDate, ExpiryDate: expiryDate}
+}
+
+// SaveCourierDocument godoc
+// @Summary SaveCourierDocument
+// @Security ApiKeyAuth
+// @Description create or replace a courier document: driving_licence, id_card, vehicle_insurance or medical_certificate. Attach the scan with an upload of kind courier_document
+// @Tags Documents
+// @Accept  json
+// @Produce  json
+// @Param id path int true "id courier"
+// @Param type path string true "document type"
+// @Param input body documentRequest true "number, issue_date and expiry_date (2006-01-02)"
+// @Success 200 {object} map[string]int
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Router /courier/{id}/documents/{type} [put]
+func (h *Handler) SaveCourierDocument(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.SaveCourierDocument) {
+		return
+	}
+	courierId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || courierId <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkCourier(ctx, courierId) {
+		return
+	}
+	var input documentRequest
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	id, err := h.services.SaveCourierDocument(ctx.Request.Context(), input.toDao(courierId, ctx.Param("type")))
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"id": id})
+}
+
+// GetCourierDocuments godoc
+// @Summary GetCourierDocuments
+// @Security ApiKeyAuth
+// @Description get documents of the courier
+// @Tags Documents
+// @Produce  json
+// @Param id path int true "id courier"
+// @Success 200 {array} dao.CourierDocument
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Router /courier/{id}/documents [get]
+func (h *Handler) GetCourierDocuments(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.GetCourierDocuments) {
+		return
+	}
+	courierId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || courierId <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkCourier(ctx, courierId) {
+		return
+	}
+	documents, err := h.services.GetCourierDocuments(ctx.Request.Context(), courierId)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, documents)
+}
+
+type readyToGo struct {
+	ReadyToGo *bool `json:"ready_to_go" binding:"required"`
+}
+
+// SetCourierReadyToGo godoc
+// @Summary SetCourierReadyToGo
+// @Security ApiKeyAuth
+// @Description mark the courier ready or not ready to take orders. A courier with expired mandatory documents can't be made ready
+// @Tags Couriers
+// @Accept  json
+// @Param id path int true "id courier"
+// @Param input body readyToGo true "ready_to_go"
+// @Success 204
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 409 {string} string
+// @Router /courier/{id}/ready [put]
+func (h *Handler) SetCourierReadyToGo(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.SetCourierReadyToGo) {
+		return
+	}
+	courierId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || courierId <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkCourier(ctx, courierId) {
+		return
+	}
+	var input readyToGo
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	if err := h.services.SetCourierReadyToGo(ctx.Request.Context(), courierId, *input.ReadyToGo); err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetExpiringDocuments godoc
+// @Summary GetExpiringDocuments
+// @Security ApiKeyAuth
+// @Description get documents of the delivery service's couriers that have expired or expire within the given number of days
+// @Tags Documents
+// @Produce  json
+// @Param days query int false "days ahead, 30 by default"
+// @Param iddeliveryservice query int false "delivery service, required for Superadmin"
+// @Success 200 {array} dao.ExpiringDocument
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Router /documents/expiring [get]
+func (h *Handler) GetExpiringDocuments(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.GetExpiringDocuments) {
+		return
+	}
+	query := expiringQuery{Days: defaultExpiryWarningDays}
+	if !bindQuery(ctx, &query) {
+		return
+	}
+	idService, ok := h.scopedDeliveryService(ctx)
+	if !ok {
+		return
+	}
+	documents, err := h.services.GetExpiringDocuments(ctx.Request.Context(), idService, query.Days)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, documents)
+}
+
+// GetDocumentWarnings godoc
+// @Summary GetDocumentWarnings
+// @Security ApiKeyAuth
+// @Description get the warnings of the daily document job about documents of the delivery service's couriers expiring soon that no manager has acknowledged yet
+// @Tags Documents
+// @Produce  json
+// @Param iddeliveryservice query int false "delivery service, required for Superadmin"
+// @Success 200 {array} dao.DocumentWarning
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Router /documents/warnings [get]
+func (h *Handler) GetDocumentWarnings(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.GetDocumentWarnings) {
+		return
+	}
+	idService, ok := h.scopedDeliveryService(ctx)
+	if !ok {
+		return
+	}
+	warnings, err := h.services.GetDocumentWarnings(ctx.Request.Context(), idService)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, warnings)
+}
+
+// AcknowledgeDocumentWarning godoc
+// @Summary AcknowledgeDocumentWarning
+// @Security ApiKeyAuth
+// @Description mark a document warning of the delivery service read, so it is no longer listed
+// @Tags Documents
+// @Param id path int true "id warning"
+// @Param iddeliveryservice query int false "delivery service, required for Superadmin"
+// @Success 204
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Router /documents/warnings/{id}/ack [post]
+func (h *Handler) AcknowledgeDocumentWarning(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.AcknowledgeDocumentWarning) {
+		return
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	idService, ok := h.scopedDeliveryService(ctx)
+	if !ok {
+		return
+	}
+	if err := h.services.AcknowledgeDocumentWarning(ctx.Request.Context(), idService, id); err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/storage"
+	"strconv"
+)
+
+// GetFile godoc
+// @Summary GetFile
+// @Description get a public file (courier photo, logo) when the service stores files itself. Proofs of delivery and document scans are served by /order/{id}/proof_of_delivery and /courier/{id}/documents/{type}/file
+// @Tags Files
+// @Produce  image/jpeg
+// @Param key path string true "file key, e.g. courier_photo/1"
+// @Success 200 {file} file
+// @Failure 404 {string} string
+// @Router /files/{key} [get]
+func (h *Handler) GetFile(ctx *gin.Context) {
+	data, contentType, err := h.services.GetFile(ctx.Request.Context(), ctx.Param("key"))
+	if errors.Is(err, storage.ErrNotFound) {
+		fail(ctx, err)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	sendFile(ctx, data, contentType, "public, max-age=86400")
+}
+
+// GetProofOfDelivery godoc
+// @Summary GetProofOfDelivery
+// @Security ApiKeyAuth
+// @Description get the proof of delivery of the order
+// @Tags order
+// @Produce  image/jpeg
+// @Param id path int true "id order"
+// @Success 200 {file} file
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Router /order/{id}/proof_of_delivery [get]
+func (h *Handler) GetProofOfDelivery(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.GetOrder) {
+		return
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkOrder(ctx, id) {
+		return
+	}
+	data, contentType, err := h.services.GetProofOfDelivery(ctx.Request.Context(), id)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	sendFile(ctx, data, contentType, "private, no-store")
+}
+
+// GetDocumentFile godoc
+// @Summary GetDocumentFile
+// @Security ApiKeyAuth
+// @Description get the scan of the courier's document
+// @Tags Documents
+// @Produce  image/jpeg
+// @Param id path int true "id courier"
+// @Param type path string true "document type: driving_licence, id_card, vehicle_insurance or medical_certificate"
+// @Success 200 {file} file
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Router /courier/{id}/documents/{type}/file [get]
+func (h *Handler) GetDocumentFile(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.GetCourierDocuments) {
+		return
+	}
+	courierId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || courierId <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkCourier(ctx, courierId) {
+		return
+	}
+	data, contentType, err := h.services.GetDocumentFile(ctx.Request.Context(), courierId, ctx.Param("type"))
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	sendFile(ctx, data, contentType, "private, no-store")
+}
+
+func sendFile(ctx *gin.Context, data []byte, contentType, cacheControl string) {
+	// CorsMiddleware has already set a JSON content type.
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Cache-Control", cacheControl)
+	ctx.Data(http.StatusOK, contentType, data)
+}
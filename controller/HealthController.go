@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"expvar"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+)
+
+// Health godoc
+// @Summary Health
+// @Description report whether the service can reach the auth service
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]grpcClient.Health
+// @Failure 503 {object} map[string]grpcClient.Health
+// @Router /health [get]
+func (h *Handler) Health(ctx *gin.Context) {
+	health := h.services.AuthHealth()
+	status := http.StatusOK
+	if !health.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, gin.H{"auth": health})
+}
+
+// DebugVars godoc
+// @Summary DebugVars
+// @Security ApiKeyAuth
+// @Description get the metrics of the service published with expvar, token_cache among them
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {string} string
+// @Failure 403 {string} string
+// @Router /debug/vars [get]
+func (h *Handler) DebugVars(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.ReadMetrics) {
+		return
+	}
+	expvar.Handler().ServeHTTP(ctx.Writer, ctx.Request)
+}
@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"time"
+)
+
+// me resolves the courier record of the user and answers 404 when the user
+// isn't a courier.
+func (h *Handler) me(ctx *gin.Context) (dao.Caller, bool) {
+	caller, ok := h.caller(ctx)
+	if !ok {
+		return dao.Caller{}, false
+	}
+	if caller.CourierId == 0 {
+		fail(ctx, service.ErrNoCourier)
+		return dao.Caller{}, false
+	}
+	return caller, true
+}
+
+// GetMyProfile godoc
+// @Summary GetMyProfile
+// @Security ApiKeyAuth
+// @Description get the courier profile of the user
+// @Tags Me
+// @Produce  json
+// @Success 200 {object} dao.Courier
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Router /me/profile [get]
+func (h *Handler) GetMyProfile(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.GetCourier) {
+		return
+	}
+	caller, ok := h.me(ctx)
+	if !ok {
+		return
+	}
+	courier, err := h.services.GetCourier(ctx.Request.Context(), caller.UserId)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	setETag(ctx, courier.Version)
+	ctx.JSON(http.StatusOK, courier)
+}
+
+// GetMyActiveOrders godoc
+// @Summary GetMyActiveOrders
+// @Security ApiKeyAuth
+// @Description get the orders the user has to deliver
+// @Tags Me
+// @Produce  json
+// @Success 200 {array} dao.Order
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Router /me/orders/active [get]
+func (h *Handler) GetMyActiveOrders(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.ListOrders) {
+		return
+	}
+	caller, ok := h.me(ctx)
+	if !ok {
+		return
+	}
+	orders, err := h.services.GetOrders(ctx.Request.Context(), caller.CourierId)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, orders)
+}
+
+// GetMyCompletedOrders godoc
+// @Summary GetMyCompletedOrders
+// @Security ApiKeyAuth
+// @Description get list of the orders the user has completed
+// @Tags Me
+// @Produce json
+// @Param page query int false "page, required unless after is given"
+// @Param after query string false "cursor of the next page, enables keyset pagination"
+// @Param limit query int true "limit"
+// @Success 200 {object} listOrders
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Router /me/orders/completed [get]
+func (h *Handler) GetMyCompletedOrders(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.ListOrders) {
+		return
+	}
+	caller, ok := h.me(ctx)
+	if !ok {
+		return
+	}
+	h.completedOrdersOfCourier(ctx, caller.CourierId)
+}
+
+// earningsQuery is monthQuery with the current month by default.
+type earningsQuery struct {
+	Month int `form:"month" binding:"min=1,max=12"`
+	Year  int `form:"year" binding:"min=2022"`
+}
+
+// GetMyEarnings godoc
+// @Summary GetMyEarnings
+// @Security ApiKeyAuth
+// @Description get what the user earned for the deliveries completed in a month, at the courier rate of their delivery service
+// @Tags Me
+// @Produce json
+// @Param month query int false "month, the current one by default"
+// @Param year query int false "year, the current one by default"
+// @Success 200 {object} dao.Earnings
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Router /me/earnings [get]
+func (h *Handler) GetMyEarnings(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.GetEarnings) {
+		return
+	}
+	now := time.Now()
+	query := earningsQuery{Month: int(now.Month()), Year: now.Year()}
+	if !bindQuery(ctx, &query) {
+		return
+	}
+	caller, ok := h.me(ctx)
+	if !ok {
+		return
+	}
+	earnings, err := h.services.GetCourierEarnings(ctx.Request.Context(), caller.CourierId, query.Month, query.Year)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, earnings)
+}
+
+// SetMyAvailability godoc
+// @Summary SetMyAvailability
+// @Security ApiKeyAuth
+// @Description mark the user ready or not ready to take orders
+// @Tags Me
+// @Accept  json
+// @Param input body readyToGo true "ready_to_go"
+// @Success 204
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Failure 409 {string} string
+// @Router /me/availability [put]
+func (h *Handler) SetMyAvailability(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.SetCourierReadyToGo) {
+		return
+	}
+	var input readyToGo
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	caller, ok := h.me(ctx)
+	if !ok {
+		return
+	}
+	if err := h.services.SetCourierReadyToGo(ctx.Request.Context(), caller.CourierId, *input.ReadyToGo); err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetMyService godoc
+// @Summary GetMyService
+// @Security ApiKeyAuth
+// @Description get the delivery service the user manages
+// @Tags Me
+// @Produce  json
+// @Success 200 {object} dao.DeliveryService
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Router /me/service [get]
+func (h *Handler) GetMyService(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.GetDeliveryService) {
+		return
+	}
+	caller, ok := h.caller(ctx)
+	if !ok {
+		return
+	}
+	if caller.Role != service.RoleCourierManager || caller.DeliveryServiceId == 0 {
+		fail(ctx, apperr.New(apperr.NotFound, service.ErrNoTenant.Error()))
+		return
+	}
+	deliveryService, err := h.services.GetDeliveryServiceById(ctx.Request.Context(), caller.UserId)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	setETag(ctx, deliveryService.Version)
+	ctx.JSON(http.StatusOK, deliveryService)
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"strconv"
+)
+
+type review struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"`
+	Reason   string `json:"reason"`
+}
+
+// SubmitCourierApplication godoc
+// @Summary SubmitCourierApplication
+// @Security ApiKeyAuth
+// @Description send the courier's application to review. The ID card and the medical certificate are required, plus the driving licence and the vehicle insurance for scooters and cars; none of them may be expired
+// @Tags Onboarding
+// @Param id path int true "id courier"
+// @Success 204
+// @Failure 404 {string} string
+// @Failure 409 {string} string
+// @Failure 422 {string} string
+// @Failure 403 {string} string
+// @Router /courier/{id}/submit [post]
+func (h *Handler) SubmitCourierApplication(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.SubmitCourierApplication) {
+		return
+	}
+	courierId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || courierId <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkCourier(ctx, courierId) {
+		return
+	}
+	if err := h.services.SubmitCourierApplication(ctx.Request.Context(), courierId); err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ReviewCourierApplication godoc
+// @Summary ReviewCourierApplication
+// @Security ApiKeyAuth
+// @Description approve or reject a courier pending review; a rejection needs a reason
+// @Tags Onboarding
+// @Accept  json
+// @Param id path int true "id courier"
+// @Param input body review true "decision (approve or reject) and reason"
+// @Success 204
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 409 {string} string
+// @Router /courier/{id}/review [post]
+func (h *Handler) ReviewCourierApplication(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.ReviewCourierApplication) {
+		return
+	}
+	courierId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || courierId <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkCourier(ctx, courierId) {
+		return
+	}
+	var input review
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	if err := h.services.ReviewCourierApplication(ctx.Request.Context(), courierId, input.Decision == "approve", input.Reason); err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetCourierApplications godoc
+// @Summary GetCourierApplications
+// @Security ApiKeyAuth
+// @Description get the couriers of the delivery service waiting for review
+// @Tags Onboarding
+// @Produce  json
+// @Param iddeliveryservice query int false "delivery service, required for Superadmin"
+// @Success 200 {array} dao.Courier
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Router /couriers/applications [get]
+func (h *Handler) GetCourierApplications(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.ListCourierApplications) {
+		return
+	}
+	idService, ok := h.scopedDeliveryService(ctx)
+	if !ok {
+		return
+	}
+	couriers, err := h.services.GetCourierApplications(ctx.Request.Context(), idService)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, couriers)
+}
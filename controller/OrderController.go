@@ -1,20 +1,26 @@
 package controller
 
 import (
-	"fmt"
 	"github.com/gin-gonic/gin"
 	"log"
 	"net/http"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
 	"strconv"
 )
 
 type listOrders struct {
 	Data []dao.DetailedOrder `json:"data"`
+	dao.Pagination
 }
 
 type text struct {
-	Status string `json:"status"`
+	Status string `json:"status" binding:"required,order_status"`
+}
+
+// assignOrder is the body of PUT /orders/{id}.
+type assignOrder struct {
+	CourierId int `json:"courier_id" binding:"required,min=1"`
 }
 
 // GetOrders godoc
@@ -27,25 +33,26 @@ type text struct {
 // @Param id path int true "Courier ID"
 // @Success 200 {object} dao.Order
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} err
 // @Router /orders/{id} [get]
 func (h *Handler) GetOrders(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetOrders:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.ListOrders) {
 		return
 	}
 	var Orders []dao.Order
 	idQuery := ctx.Param("id")
 	id, err := strconv.Atoi(idQuery)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": err})
+		badRequest(ctx, err.Error())
+		return
+	}
+	if !h.checkCourier(ctx, id) {
 		return
 	}
-	Orders, err = h.services.GetOrders(id)
+	Orders, err = h.services.GetOrders(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"No such orders": err})
+		fail(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, Orders)
@@ -59,28 +66,31 @@ func (h *Handler) GetOrders(ctx *gin.Context) {
 // @Produce  json
 // @Param id path int true "ID"
 // @Success 200 {object} dao.Order
+// @Header 200 {string} ETag "version of the order, for If-Match"
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} err
 // @Router /order/{id} [get]
 func (h *Handler) GetOrder(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetOrder:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.GetOrder) {
 		return
 	}
 	var Order dao.Order
 	idQuery := ctx.Param("id")
 	id, err := strconv.Atoi(idQuery)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"Error with query parameter": err})
+		badRequest(ctx, err.Error())
 		return
 	}
-	Order, err = h.services.GetOrder(id)
+	if !h.checkOrder(ctx, id) {
+		return
+	}
+	Order, err = h.services.GetOrder(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"No such order": err})
+		fail(ctx, err)
 		return
 	}
+	setETag(ctx, Order.Version)
 	ctx.JSON(http.StatusOK, Order)
 }
 
@@ -92,24 +102,24 @@ func (h *Handler) GetOrder(ctx *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "ID"
-// @Param input body string true "status"
+// @Param If-Match header string true "ETag of the order"
+// @Param input body text true "status: ready to delivery or completed"
 // @Success 200 {object} dao.Order
 // @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 412 {string} string
+// @Failure 428 {string} string
 // @Failure 500 {string} err
 // @Router /order/status_change/{id} [put]
 func (h *Handler) ChangeOrderStatus(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler ChangeOrderStatus:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.ChangeOrderStatus) {
 		return
 	}
 	idQuery := ctx.Param("id")
 	var txt text
 	var status string
 
-	if err := ctx.ShouldBindJSON(&txt); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
+	if !bindJSON(ctx, &txt) {
 		return
 	}
 
@@ -117,12 +127,19 @@ func (h *Handler) ChangeOrderStatus(ctx *gin.Context) {
 
 	id, err := strconv.Atoi(idQuery)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"Error with query parameter": err})
+		badRequest(ctx, err.Error())
+		return
+	}
+	if !h.checkOrder(ctx, id) {
 		return
 	}
-	orderId, err := h.services.ChangeOrderStatus(status, uint16(id))
+	version, ok := ifMatch(ctx, "order")
+	if !ok {
+		return
+	}
+	orderId, err := h.services.ChangeOrderStatus(ctx.Request.Context(), status, uint16(id), version)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"No such order": err})
+		fail(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, map[string]interface{}{
@@ -135,50 +152,72 @@ func (h *Handler) ChangeOrderStatus(ctx *gin.Context) {
 // @Description get list of completed orders by courier id
 // @Tags Orders
 // @Produce json
-// @Param page query int true "page"
-// @Param limit query int true "limit"
+// @Param page query int false "page, required unless after is given"
+// @Param after query string false "cursor of the next page, enables keyset pagination"
+// @Param limit query int true "limit, up to 100"
 // @Param idcourier query int true "idcourier"
 // @Success 200 {object} listOrders
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /orders/completed [get]
 func (h *Handler) GetCourierCompletedOrders(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetCourierCompletedOrders:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.ListOrders) {
 		return
 	}
-	page, er := strconv.Atoi(ctx.Query("page"))
-	if er != nil || page == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "page query param is wrong. Expected an integer greater than 0"})
+	idCourier, er := strconv.Atoi(ctx.Query("idcourier"))
+	if er != nil {
+		badRequest(ctx, "expect an integer greater than 0")
 		return
 	}
-	limit, er1 := strconv.Atoi(ctx.Query("limit"))
-	if er1 != nil || limit == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "limit query param is wrong. Expected an integer greater than 0"})
+	if !h.checkCourier(ctx, idCourier) {
 		return
 	}
-	idCourier, er := strconv.Atoi(ctx.Query("idcourier"))
-	if er != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 0"})
+	h.completedOrdersOfCourier(ctx, idCourier)
+}
+
+// completedOrdersOfCourier answers with a page of the courier's completed
+// orders, by page number or by cursor.
+func (h *Handler) completedOrdersOfCourier(ctx *gin.Context, idCourier int) {
+	var query cursorPageQuery
+	if !bindQuery(ctx, &query) {
 		return
 	}
 
-	DetOrders, err := h.services.GetCourierCompletedOrders(limit, page, idCourier)
+	if query.After != nil {
+		Orders, pagination, err := h.services.GetCourierCompletedOrdersAfterCursor(ctx.Request.Context(), query.Limit, *query.After, idCourier)
+		if err != nil {
+			fail(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, cursorListDetailedOrders{Data: Orders, CursorPagination: pagination})
+		return
+	}
+	DetOrders, pagination, err := h.services.GetCourierCompletedOrders(ctx.Request.Context(), query.Limit, query.Page, idCourier)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, listOrders{Data: DetOrders})
+	ctx.JSON(http.StatusOK, listOrders{Data: DetOrders, Pagination: pagination})
 
 }
 
 type listShortOrders struct {
 	Data []dao.Order `json:"data"`
+	dao.Pagination
 }
 type listDetailedOrders struct {
 	Data []dao.DetailedOrder `json:"data"`
+	dao.Pagination
+}
+
+type cursorListShortOrders struct {
+	Data []dao.Order `json:"data"`
+	dao.CursorPagination
+}
+type cursorListDetailedOrders struct {
+	Data []dao.DetailedOrder `json:"data"`
+	dao.CursorPagination
 }
 
 // GetAllOrdersOfCourierService godoc
@@ -187,42 +226,43 @@ type listDetailedOrders struct {
 // @Description get list of all orders by courier service id
 // @Tags Orders
 // @Produce json
-// @Param page query int true "page"
-// @Param limit query int true "limit"
-// @Param iddeliveryservice query int true "iddeliveryservice"
+// @Param page query int false "page, required unless after is given"
+// @Param after query string false "cursor of the next page, enables keyset pagination"
+// @Param limit query int true "limit, up to 100"
+// @Param iddeliveryservice query int false "delivery service, required for Superadmin"
 // @Success 200 {object} listDetailedOrders
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /orders [get]
 func (h *Handler) GetAllOrdersOfCourierService(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetAllOrdersOfCourierService:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.ListOrders) {
 		return
 	}
-	page, er := strconv.Atoi(ctx.Query("page"))
-	if er != nil || page == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "page query param is wrong. Expected an integer greater than 0"})
+	var query cursorPageQuery
+	if !bindQuery(ctx, &query) {
 		return
 	}
-	limit, er1 := strconv.Atoi(ctx.Query("limit"))
-	if er1 != nil || limit == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "limit query param is wrong. Expected an integer greater than 0"})
+	idService, ok := h.scopedDeliveryService(ctx)
+	if !ok {
 		return
 	}
-	idService, er := strconv.Atoi(ctx.Query("iddeliveryservice"))
-	if er != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 0"})
+
+	if query.After != nil {
+		Orders, pagination, err := h.services.GetAllOrdersOfCourierServiceAfterCursor(ctx.Request.Context(), query.Limit, *query.After, idService)
+		if err != nil {
+			fail(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, cursorListDetailedOrders{Data: Orders, CursorPagination: pagination})
 		return
 	}
-
-	Orders, err := h.services.GetAllOrdersOfCourierService(limit, page, idService)
+	Orders, pagination, err := h.services.GetAllOrdersOfCourierService(ctx.Request.Context(), query.Limit, query.Page, idService)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, listDetailedOrders{Data: Orders})
+	ctx.JSON(http.StatusOK, listDetailedOrders{Data: Orders, Pagination: pagination})
 
 }
 
@@ -232,53 +272,52 @@ func (h *Handler) GetAllOrdersOfCourierService(ctx *gin.Context) {
 // @Description get list of completed orders by courier id sorted by month
 // @Tags Orders
 // @Produce json
-// @Param page query int true "page"
-// @Param limit query int true "limit"
+// @Param page query int false "page, required unless after is given"
+// @Param after query string false "cursor of the next page, enables keyset pagination"
+// @Param limit query int true "limit, up to 100"
 // @Param idcourier query int true "idcourier"
-// @Param month query int true "month"
-// @Param year query int true "year"
+// @Param month query int true "month, 1 to 12"
+// @Param year query int true "year, 2022 or later"
 // @Success 200 {object} listShortOrders
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /orders/bymonth [get]
 func (h *Handler) GetCourierCompletedOrdersByMonth(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetCourierCompletedOrdersByMonth:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
-		return
-	}
-	page, er := strconv.Atoi(ctx.Query("page"))
-	if er != nil || page == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "page query param is wrong. Expected an integer greater than 0"})
+	if !h.authorize(ctx, policy.ListOrders) {
 		return
 	}
-	limit, er1 := strconv.Atoi(ctx.Query("limit"))
-	if er1 != nil || limit == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": " limit query param is wrong. Expected an integer greater than 0"})
+	var query cursorPageQuery
+	if !bindQuery(ctx, &query) {
 		return
 	}
 	idCourier, er := strconv.Atoi(ctx.Query("idcourier"))
 	if er != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 0"})
+		badRequest(ctx, "expect an integer greater than 0")
 		return
 	}
-	Month, er := strconv.Atoi(ctx.Query("month"))
-	if er != nil || Month == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": " expect an integer from 1 to 12"})
+	if !h.checkCourier(ctx, idCourier) {
 		return
 	}
-	Year, er := strconv.Atoi(ctx.Query("year"))
-	if er != nil || Month == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 2021"})
+	var month monthQuery
+	if !bindQuery(ctx, &month) {
 		return
 	}
-	Orders, err := h.services.GetCourierCompletedOrdersByMonth(limit, page, idCourier, Month, Year)
+	if query.After != nil {
+		Orders, pagination, err := h.services.GetCourierCompletedOrdersByMonthAfterCursor(ctx.Request.Context(), query.Limit, *query.After, idCourier, month.Month, month.Year)
+		if err != nil {
+			fail(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, cursorListShortOrders{Data: Orders, CursorPagination: pagination})
+		return
+	}
+	Orders, pagination, err := h.services.GetCourierCompletedOrdersByMonth(ctx.Request.Context(), query.Limit, query.Page, idCourier, month.Month, month.Year)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, listShortOrders{Data: Orders})
+	ctx.JSON(http.StatusOK, listShortOrders{Data: Orders, Pagination: pagination})
 
 }
 
@@ -286,38 +325,45 @@ func (h *Handler) GetCourierCompletedOrdersByMonth(ctx *gin.Context) {
 // @Summary UpdateOrder
 // @Security ApiKeyAuth
 // @Tags Orders
-// @Description assign order to courier
+// @Description assign order to a courier of its delivery service
 // @ID UpdateOrder
 // @Accept  json
 // @Produce json
 // @Param id path int true "order_id"
-// @Param input body dao.Order true "id courier"
+// @Param If-Match header string true "ETag of the order"
+// @Param input body assignOrder true "id courier"
 // @Success 204
 // @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 409 {string} string
+// @Failure 412 {string} string
+// @Failure 428 {string} string
 // @Router /orders/{id} [put]
 func (h *Handler) UpdateOrder(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler UpdateOrder:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.AssignOrder) {
 		return
 	}
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
 		log.Println(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		badRequest(ctx, err.Error())
 		return
 	}
-	var order dao.Order
-	if err := ctx.ShouldBindJSON(&order); err != nil {
-		log.Println(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request"})
+	var input assignOrder
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	if !h.checkOrder(ctx, id) || !h.checkCourier(ctx, input.CourierId) {
 		return
 	}
-	order.Id = id
-	if err := h.services.AssigningOrderToCourier(order); err != nil {
+	version, ok := ifMatch(ctx, "order")
+	if !ok {
+		return
+	}
+	if err := h.services.AssigningOrderToCourier(ctx.Request.Context(), dao.Order{Id: id, IdCourier: input.CourierId, Version: version}); err != nil {
 		log.Println(err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
 	ctx.Status(http.StatusNoContent)
@@ -331,27 +377,30 @@ func (h *Handler) UpdateOrder(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "id"
 // @Success 200 {object} dao.AllInfoAboutOrder
+// @Header 200 {string} ETag "version of the order, for If-Match"
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /order/detailed/{id} [get]
 func (h *Handler) GetDetailedOrderById(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetDetailedOrderById:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.GetOrder) {
 		return
 	}
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil || id <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 0"})
+		badRequest(ctx, "expect an integer greater than 0")
 		return
 	}
 
-	DetOrder, err := h.services.GetDetailedOrderById(id)
+	if !h.checkOrder(ctx, id) {
+		return
+	}
+	DetOrder, err := h.services.GetDetailedOrderById(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
+	setETag(ctx, DetOrder.Version)
 	ctx.JSON(http.StatusOK, DetOrder)
 }
 
@@ -361,59 +410,66 @@ func (h *Handler) GetDetailedOrderById(ctx *gin.Context) {
 // @Description get list of completed orders by courier service id
 // @Tags order
 // @Produce json
-// @Param limit query int true "limit"
-// @Param page query int true "page"
-// @Param iddeliveryservice query int true "iddeliveryservice"
+// @Param limit query int true "limit, up to 100"
+// @Param page query int false "page, required unless after is given"
+// @Param after query string false "cursor of the next page, enables keyset pagination"
+// @Param iddeliveryservice query int false "delivery service, required for Superadmin"
 // @Param sort query string false "sort"
 // @Success 200 {object} listShortOrders
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /orders/service/completed [get]
 func (h *Handler) GetCompletedOrdersOfCourierService(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetCompletedOrdersOfCourierService:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
-		return
-	}
-	page, er := strconv.Atoi(ctx.Query("page"))
-	if er != nil || page <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "page query param is wrong. Expected an integer greater than 0"})
+	if !h.authorize(ctx, policy.ListOrders) {
 		return
 	}
-	limit, er1 := strconv.Atoi(ctx.Query("limit"))
-	if er1 != nil || limit <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "limit query param is wrong. Expected an integer greater than 0"})
+	var query cursorPageQuery
+	if !bindQuery(ctx, &query) {
 		return
 	}
-	idService, er := strconv.Atoi(ctx.Query("iddeliveryservice"))
-	if er != nil || idService <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 0"})
+	idService, ok := h.scopedDeliveryService(ctx)
+	if !ok {
 		return
 	}
 	Sort := ctx.Query("sort")
-	if Sort == "date" {
-		Orders, err := h.services.GetCompletedOrdersOfCourierServiceByDate(limit, page, idService)
+	if query.After != nil {
+		var Orders []dao.Order
+		var pagination dao.CursorPagination
+		var err error
+		if Sort == "date" {
+			Orders, pagination, err = h.services.GetCompletedOrdersOfCourierServiceByDateAfterCursor(ctx.Request.Context(), query.Limit, *query.After, idService)
+		} else if Sort == "courier" {
+			Orders, pagination, err = h.services.GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor(ctx.Request.Context(), query.Limit, *query.After, idService)
+		} else {
+			Orders, pagination, err = h.services.GetCompletedOrdersOfCourierServiceAfterCursor(ctx.Request.Context(), query.Limit, *query.After, idService)
+		}
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+			fail(ctx, err)
 			return
 		}
-		ctx.JSON(http.StatusOK, listShortOrders{Data: Orders})
-	}
-	if Sort == "courier" {
-		Orders, err := h.services.GetCompletedOrdersOfCourierServiceByCourierId(limit, page, idService)
+		ctx.JSON(http.StatusOK, cursorListShortOrders{Data: Orders, CursorPagination: pagination})
+	} else if Sort == "date" {
+		Orders, pagination, err := h.services.GetCompletedOrdersOfCourierServiceByDate(ctx.Request.Context(), query.Limit, query.Page, idService)
+		if err != nil {
+			fail(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, listShortOrders{Data: Orders, Pagination: pagination})
+	} else if Sort == "courier" {
+		Orders, pagination, err := h.services.GetCompletedOrdersOfCourierServiceByCourierId(ctx.Request.Context(), query.Limit, query.Page, idService)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+			fail(ctx, err)
 			return
 		}
-		ctx.JSON(http.StatusOK, listShortOrders{Data: Orders})
+		ctx.JSON(http.StatusOK, listShortOrders{Data: Orders, Pagination: pagination})
 	} else {
-		Orders, err := h.services.GetCompletedOrdersOfCourierService(limit, page, idService)
+		Orders, pagination, err := h.services.GetCompletedOrdersOfCourierService(ctx.Request.Context(), query.Limit, query.Page, idService)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+			fail(ctx, err)
 			return
 		}
-		ctx.JSON(http.StatusOK, listShortOrders{Data: Orders})
+		ctx.JSON(http.StatusOK, listShortOrders{Data: Orders, Pagination: pagination})
 	}
 }
 
@@ -423,41 +479,42 @@ func (h *Handler) GetCompletedOrdersOfCourierService(ctx *gin.Context) {
 // @Description get list of all orders by courier service id with custom status
 // @Tags order
 // @Produce json
-// @Param page query int true "page"
-// @Param limit query int true "limit"
-// @Param iddeliveryservice query int true "iddeliveryservice"
+// @Param page query int false "page, required unless after is given"
+// @Param after query string false "cursor of the next page, enables keyset pagination"
+// @Param limit query int true "limit, up to 100"
+// @Param iddeliveryservice query int false "delivery service, required for Superadmin"
 // @Success 200 {object} listDetailedOrders
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /orders/manager [get]
 func (h *Handler) GetOrdersOfCourierServiceForManager(ctx *gin.Context) {
-	necessaryRole := []string{"Superadmin", "Courier manager"}
-	if err := h.services.CheckRole(necessaryRole, ctx.GetString("role")); err != nil {
-		log.Println("Handler GetOrdersOfCourierServiceForManager:not enough rights")
-		ctx.JSON(http.StatusUnauthorized, gin.H{"message": "not enough rights"})
+	if !h.authorize(ctx, policy.GetOrdersOfServiceForManager) {
 		return
 	}
-	page, er := strconv.Atoi(ctx.Query("page"))
-	if er != nil || page == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "page query param is wrong. Expected an integer greater than 0"})
+	var query cursorPageQuery
+	if !bindQuery(ctx, &query) {
 		return
 	}
-	limit, er1 := strconv.Atoi(ctx.Query("limit"))
-	if er1 != nil || limit == 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "limit query param is wrong. Expected an integer greater than 0"})
+	idService, ok := h.scopedDeliveryService(ctx)
+	if !ok {
 		return
 	}
-	idService, er := strconv.Atoi(ctx.Query("iddeliveryservice"))
-	if er != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": "expect an integer greater than 0"})
+
+	if query.After != nil {
+		Orders, pagination, err := h.services.GetOrdersOfCourierServiceForManagerAfterCursor(ctx.Request.Context(), query.Limit, *query.After, idService)
+		if err != nil {
+			fail(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, cursorListDetailedOrders{Data: Orders, CursorPagination: pagination})
 		return
 	}
-
-	Orders, err := h.services.GetOrdersOfCourierServiceForManager(limit, page, idService)
+	Orders, pagination, err := h.services.GetOrdersOfCourierServiceForManager(ctx.Request.Context(), query.Limit, query.Page, idService)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Error: %s", err)})
+		fail(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, listDetailedOrders{Data: Orders})
+	ctx.JSON(http.StatusOK, listDetailedOrders{Data: Orders, Pagination: pagination})
 
 }
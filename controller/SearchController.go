@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"strings"
+)
+
+const defaultSearchLimit = 20
+
+type searchQuery struct {
+	Q     string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"min=1,max=100"`
+}
+
+// Search godoc
+// @Summary Search
+// @Security ApiKeyAuth
+// @Description search couriers and orders of the caller's delivery service
+// @Tags Search
+// @Produce json
+// @Param q query string true "search text: name, surname, phone, email, address or restaurant order id"
+// @Param limit query int false "max results of each kind, 20 by default and up to 100"
+// @Param iddeliveryservice query int false "delivery service, required for Superadmin"
+// @Success 200 {object} dao.SearchResult
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Router /search [get]
+func (h *Handler) Search(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.Search) {
+		return
+	}
+	query := searchQuery{Limit: defaultSearchLimit}
+	if !bindQuery(ctx, &query) {
+		return
+	}
+	idService, ok := h.scopedDeliveryService(ctx)
+	if !ok {
+		return
+	}
+	result, err := h.services.Search(ctx.Request.Context(), idService, strings.TrimSpace(query.Q), query.Limit)
+	if err != nil {
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+)
+
+// uploadActions are the actions each kind of upload is authorised as: those
+// of the endpoints that take the same file through the service.
+var uploadActions = map[string]string{
+	service.UploadCourierPhoto:    policy.SaveCourierPhoto,
+	service.UploadLogo:            policy.SaveLogo,
+	service.UploadProofOfDelivery: policy.ChangeOrderStatus,
+	service.UploadCourierDocument: policy.SaveCourierDocument,
+}
+
+// uploadRequest is the body of POST /uploads.
+type uploadRequest struct {
+	Kind        string `json:"kind" binding:"required,oneof=courier_photo logo proof_of_delivery courier_document"`
+	TargetId    int    `json:"target_id" binding:"required,min=1"`
+	ContentType string `json:"content_type" binding:"required"`
+}
+
+// CreateUpload godoc
+// @Summary CreateUpload
+// @Security ApiKeyAuth
+// @Description get a presigned URL to upload a file straight to storage. PUT the file there with the declared Content-Type, then call /uploads/{id}/complete
+// @Tags Uploads
+// @Accept  json
+// @Produce  json
+// @Param input body uploadRequest true "kind (courier_photo, logo, proof_of_delivery or courier_document), target_id (courier, delivery service, order or document id) and content_type"
+// @Success 201 {object} dao.PresignedUpload
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 415 {string} string
+// @Failure 501 {string} string
+// @Router /uploads [post]
+func (h *Handler) CreateUpload(ctx *gin.Context) {
+	var input uploadRequest
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	upload := dao.Upload{Kind: input.Kind, TargetId: input.TargetId, ContentType: input.ContentType}
+	if !h.authorize(ctx, uploadActions[upload.Kind]) {
+		return
+	}
+	if !h.checkUploadTarget(ctx, upload) {
+		return
+	}
+	presigned, err := h.services.CreateUpload(ctx.Request.Context(), upload, getUserId(ctx))
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, presigned)
+}
+
+// CompleteUpload godoc
+// @Summary CompleteUpload
+// @Security ApiKeyAuth
+// @Description check that the file of an upload has arrived and attach it to its courier, delivery service or order
+// @Tags Uploads
+// @Produce  json
+// @Param id path string true "upload id"
+// @Success 200 {object} map[string]string
+// @Failure 404 {string} string
+// @Failure 409 {string} string
+// @Failure 410 {string} string
+// @Failure 422 {string} string
+// @Router /uploads/{id}/complete [post]
+func (h *Handler) CompleteUpload(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.CompleteUpload) {
+		return
+	}
+	id := ctx.Param("id")
+	url, err := h.services.CompleteUpload(ctx.Request.Context(), id, getUserId(ctx), ctx.GetString("role"))
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"upload_id": id, "status": "completed", "url": url})
+}
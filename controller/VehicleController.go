@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"strconv"
+)
+
+// vehicleRequest is the body of PUT /courier/{id}/vehicle.
+type vehicleRequest struct {
+	Type       string  `json:"type" binding:"required,oneof=foot bike scooter car"`
+	CapacityKg float64 `json:"capacity_kg" binding:"min=0"`
+	Plate      string  `json:"plate"`
+}
+
+func (r vehicleRequest) toDao(courierId int) dao.Vehicle {
+	return dao.Vehicle{CourierId: courierId, Type: r.Type, CapacityKg: r.CapacityKg, Plate: r.Plate}
+}
+
+// SaveVehicle godoc
+// @Summary SaveVehicle
+// @Security ApiKeyAuth
+// @Description register the courier's vehicle, replacing the previous one. Type is foot, bike, scooter or car; scooters and cars need a plate. Without capacity_kg the usual capacity of the type is assumed
+// @Tags Vehicles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "id courier"
+// @Param input body vehicleRequest true "type, capacity_kg and plate"
+// @Success 200 {object} map[string]int
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Router /courier/{id}/vehicle [put]
+func (h *Handler) SaveVehicle(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.SaveVehicle) {
+		return
+	}
+	courierId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || courierId <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkCourier(ctx, courierId) {
+		return
+	}
+	var input vehicleRequest
+	if !bindJSON(ctx, &input) {
+		return
+	}
+	id, err := h.services.SaveVehicle(ctx.Request.Context(), input.toDao(courierId))
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"id": id})
+}
+
+// GetVehicle godoc
+// @Summary GetVehicle
+// @Security ApiKeyAuth
+// @Description get the courier's vehicle
+// @Tags Vehicles
+// @Produce  json
+// @Param id path int true "id courier"
+// @Success 200 {object} dao.Vehicle
+// @Failure 404 {string} string
+// @Failure 403 {string} string
+// @Router /courier/{id}/vehicle [get]
+func (h *Handler) GetVehicle(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.GetVehicle) {
+		return
+	}
+	courierId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || courierId <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkCourier(ctx, courierId) {
+		return
+	}
+	vehicle, err := h.services.GetVehicleOfCourier(ctx.Request.Context(), courierId)
+	if err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	if vehicle == nil {
+		fail(ctx, apperr.New(apperr.NotFound, "courier has no vehicle"))
+		return
+	}
+	ctx.JSON(http.StatusOK, vehicle)
+}
+
+// DeleteVehicle godoc
+// @Summary DeleteVehicle
+// @Security ApiKeyAuth
+// @Description remove the courier's vehicle; the courier is then dispatched as one on foot
+// @Tags Vehicles
+// @Param id path int true "id courier"
+// @Success 204
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Router /courier/{id}/vehicle [delete]
+func (h *Handler) DeleteVehicle(ctx *gin.Context) {
+	if !h.authorize(ctx, policy.DeleteVehicle) {
+		return
+	}
+	courierId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || courierId <= 0 {
+		badRequest(ctx, "expect an integer greater than 0")
+		return
+	}
+	if !h.checkCourier(ctx, courierId) {
+		return
+	}
+	if err := h.services.DeleteVehicleOfCourier(ctx.Request.Context(), courierId); err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
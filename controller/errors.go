@@ -0,0 +1,18 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+)
+
+// fail answers with the problem response of err: its kind decides the
+// status, and errors of ours don't show their details.
+func fail(ctx *gin.Context, err error) {
+	problem := apperr.ProblemOf(err)
+	ctx.JSON(problem.Status, problem)
+}
+
+// badRequest answers 400 for a request that can't be read at all.
+func badRequest(ctx *gin.Context, message string) {
+	fail(ctx, apperr.New(apperr.Validation, message))
+}
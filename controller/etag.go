@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"strconv"
+	"strings"
+)
+
+// setETag sends the version of the resource as its ETag.
+func setETag(ctx *gin.Context, version int) {
+	ctx.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatch reads the version of the resource a write is made against from
+// the If-Match header. Writes without one are answered with 428, and ones
+// with an ETag that isn't a version of ours with 412. "*" gives 0, which
+// writes whatever the version.
+func ifMatch(ctx *gin.Context, resource string) (int, bool) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" {
+		fail(ctx, apperr.New(apperr.PreconditionRequired, "If-Match with the ETag of the "+resource+" is required"))
+		return 0, false
+	}
+	if header == "*" {
+		return 0, true
+	}
+	tag, err := strconv.Unquote(header)
+	if err != nil {
+		tag = ""
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		fail(ctx, apperr.New(apperr.PreconditionFailed, resource+" was changed since it was read"))
+		return 0, false
+	}
+	return version, true
+}
@@ -1,9 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"log"
 	"net/http"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
 	"strings"
 )
 
@@ -11,27 +15,72 @@ func (h *Handler) userIdentity(ctx *gin.Context) {
 	header := ctx.GetHeader("Authorization")
 	if header == "" {
 		log.Println("User:empty auth header")
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "empty auth header"})
+		unauthenticated(ctx, apperr.New(apperr.Unauthenticated, "empty auth header"))
 		return
 	}
 	headerParts := strings.Split(header, " ")
 	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
 		log.Println("User:invalid auth header")
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "invalid auth header"})
+		unauthenticated(ctx, apperr.New(apperr.Unauthenticated, "invalid auth header"))
 		return
 	}
 	if len(headerParts[1]) == 0 {
 		log.Println("User:token is empty")
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "token is empty"})
+		unauthenticated(ctx, apperr.New(apperr.Unauthenticated, "token is empty"))
 		return
 	}
-	user, err := h.services.AllProjectApp.ParseToken(headerParts[1])
+	user, err := h.services.AllProjectApp.ParseToken(ctx.Request.Context(), headerParts[1])
 	if err != nil {
 		log.Printf("userIdentity:%s", err)
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		if errors.Is(err, service.ErrAuthUnavailable) {
+			unauthenticated(ctx, service.ErrAuthUnavailable)
+			return
+		}
+		unauthenticated(ctx, apperr.Wrap(apperr.Unauthenticated, "invalid token", err))
 		return
 	}
+	ctx.Set("token", headerParts[1])
 	ctx.Set("perms", user.Permissions)
 	ctx.Set("role", user.Role)
 	ctx.Set("userId", user.UserId)
 }
+
+// Logout godoc
+// @Summary Logout
+// @Security ApiKeyAuth
+// @Description forget the cached validation of the token, so that a token revoked in the auth service stops working here at once
+// @Tags Auth
+// @Success 204
+// @Failure 401 {string} string
+// @Router /auth/logout [post]
+func (h *Handler) Logout(ctx *gin.Context) {
+	h.services.InvalidateToken(ctx.GetString("token"))
+	ctx.Status(http.StatusNoContent)
+}
+
+// unauthenticated stops the request before the handler.
+func unauthenticated(ctx *gin.Context, err error) {
+	fail(ctx, err)
+	ctx.Abort()
+}
+
+// authorize checks the policy for the action and answers 403 when the user
+// may not perform it.
+func (h *Handler) authorize(ctx *gin.Context, action string) bool {
+	if err := h.policy.Allow(action, ctx.GetString("role"), ctx.GetString("perms")); err != nil {
+		log.Printf("Handler %s: %s", action, err)
+		if errors.Is(err, policy.ErrForbidden) {
+			// Which permission is missing is for the log only.
+			err = policy.ErrForbidden
+		}
+		fail(ctx, err)
+		return false
+	}
+	return true
+}
+
+func getUserId(ctx *gin.Context) int {
+	userId, _ := ctx.Get("userId")
+	id, _ := userId.(int32)
+	return int(id)
+}
@@ -6,21 +6,30 @@ import (
 	"github.com/swaggo/gin-swagger"
 	_ "stlab.itechart-group.com/go/food_delivery/courier_service/docs"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/middleware"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
 )
 
 type Handler struct {
 	services *service.Service
+	policy   *policy.Policy
 }
 
+// NewHandler authorises requests with the policy of the services, or with
+// the default policy when they have none.
 func NewHandler(services *service.Service) *Handler {
-	return &Handler{services: services}
+	p := services.Policy
+	if p == nil {
+		p = policy.Default()
+	}
+	return &Handler{services: services, policy: p}
 }
 
 func (h *Handler) InitRoutesGin() *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/health", h.Health)
 
 	router.Use(
 		middleware.CorsMiddleware,
@@ -32,6 +41,7 @@ func (h *Handler) InitRoutesGin() *gin.Engine {
 		couriers.GET("/", h.GetCouriers)
 		couriers.POST("/photo", h.SaveCourierPhoto)
 		couriers.GET("/service", h.GetCouriersOfCourierService)
+		couriers.GET("/applications", h.GetCourierApplications)
 	}
 
 	courier := router.Group("/courier")
@@ -40,6 +50,16 @@ func (h *Handler) InitRoutesGin() *gin.Engine {
 		courier.GET("/:id", h.GetCourier)
 		courier.POST("/", h.SaveCourier)
 		courier.PUT("/:id", h.NewUpdateCourier)
+		courier.PATCH("/:id", h.PatchCourier)
+		courier.PUT("/:id/ready", h.SetCourierReadyToGo)
+		courier.POST("/:id/submit", h.SubmitCourierApplication)
+		courier.POST("/:id/review", h.ReviewCourierApplication)
+		courier.GET("/:id/documents", h.GetCourierDocuments)
+		courier.PUT("/:id/documents/:type", h.SaveCourierDocument)
+		courier.GET("/:id/documents/:type/file", h.GetDocumentFile)
+		courier.GET("/:id/vehicle", h.GetVehicle)
+		courier.PUT("/:id/vehicle", h.SaveVehicle)
+		courier.DELETE("/:id/vehicle", h.DeleteVehicle)
 	}
 
 	orders := router.Group("/orders")
@@ -61,6 +81,33 @@ func (h *Handler) InitRoutesGin() *gin.Engine {
 		order.GET("/:id", h.GetOrder)
 		order.PUT("/status_change/:id", h.ChangeOrderStatus)
 		order.GET("/detailed/:id", h.GetDetailedOrderById)
+		order.GET("/:id/proof_of_delivery", h.GetProofOfDelivery)
+	}
+
+	me := router.Group("/me")
+	me.Use(h.userIdentity)
+	{
+		me.GET("/profile", h.GetMyProfile)
+		me.GET("/orders/active", h.GetMyActiveOrders)
+		me.GET("/orders/completed", h.GetMyCompletedOrders)
+		me.GET("/earnings", h.GetMyEarnings)
+		me.PUT("/availability", h.SetMyAvailability)
+		me.GET("/service", h.GetMyService)
+	}
+
+	router.POST("/auth/logout", h.userIdentity, h.Logout)
+	router.GET("/debug/vars", h.userIdentity, h.DebugVars)
+	router.GET("/search", h.userIdentity, h.Search)
+	router.GET("/documents/expiring", h.userIdentity, h.GetExpiringDocuments)
+	router.GET("/documents/warnings", h.userIdentity, h.GetDocumentWarnings)
+	router.POST("/documents/warnings/:id/ack", h.userIdentity, h.AcknowledgeDocumentWarning)
+	router.GET("/files/*key", h.GetFile)
+
+	uploads := router.Group("/uploads")
+	uploads.Use(h.userIdentity)
+	{
+		uploads.POST("", h.CreateUpload)
+		uploads.POST("/:id/complete", h.CompleteUpload)
 	}
 
 	deliveryService := router.Group("/deliveryservice")
@@ -70,6 +117,7 @@ func (h *Handler) InitRoutesGin() *gin.Engine {
 		deliveryService.GET("/:id", h.GetDeliveryServiceById)
 		deliveryService.GET("/", h.GetAllDeliveryServices)
 		deliveryService.PUT("/:id", h.UpdateDeliveryService)
+		deliveryService.PATCH("/:id", h.PatchDeliveryService)
 		deliveryService.POST("/logo", h.SaveLogoController)
 	}
 	return router
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"log"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"strconv"
+)
+
+// caller resolves the delivery service and courier record of the user once
+// per request. Superadmin is not bound to any delivery service.
+func (h *Handler) caller(ctx *gin.Context) (dao.Caller, bool) {
+	if value, ok := ctx.Get("caller"); ok {
+		return value.(dao.Caller), true
+	}
+	caller := dao.Caller{UserId: getUserId(ctx), Role: ctx.GetString("role")}
+	if !caller.IsSuperadmin() {
+		var err error
+		caller, err = h.services.ResolveCaller(ctx.Request.Context(), caller.UserId, caller.Role)
+		if err != nil {
+			log.Println(err)
+			fail(ctx, err)
+			return dao.Caller{}, false
+		}
+	}
+	ctx.Set("caller", caller)
+	return caller, true
+}
+
+// scopedDeliveryService returns the delivery service the request works with:
+// the one of the caller, or the iddeliveryservice query param for Superadmin.
+func (h *Handler) scopedDeliveryService(ctx *gin.Context) (int, bool) {
+	caller, ok := h.caller(ctx)
+	if !ok {
+		return 0, false
+	}
+	if caller.IsSuperadmin() {
+		idService, err := strconv.Atoi(ctx.Query("iddeliveryservice"))
+		if err != nil || idService <= 0 {
+			badRequest(ctx, "expect an integer greater than 0")
+			return 0, false
+		}
+		return idService, true
+	}
+	if caller.DeliveryServiceId == 0 {
+		fail(ctx, service.ErrNoTenant)
+		return 0, false
+	}
+	if query := ctx.Query("iddeliveryservice"); query != "" && query != strconv.Itoa(caller.DeliveryServiceId) {
+		fail(ctx, apperr.Errorf("delivery service %w", service.ErrOtherTenant))
+		return 0, false
+	}
+	return caller.DeliveryServiceId, true
+}
+
+// checkDeliveryService answers 403 unless the caller may work with the
+// delivery service.
+func (h *Handler) checkDeliveryService(ctx *gin.Context, id int) bool {
+	caller, ok := h.caller(ctx)
+	if !ok {
+		return false
+	}
+	if !caller.CanAccessDeliveryService(id) {
+		fail(ctx, apperr.Errorf("delivery service %d %w", id, service.ErrOtherTenant))
+		return false
+	}
+	return true
+}
+
+// checkCourier answers 403 unless the caller may work with the courier.
+func (h *Handler) checkCourier(ctx *gin.Context, id int) bool {
+	caller, ok := h.caller(ctx)
+	if !ok {
+		return false
+	}
+	if err := h.services.CheckCourierAccess(ctx.Request.Context(), caller, id); err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return false
+	}
+	return true
+}
+
+// checkOrder answers 403 unless the caller may work with the order.
+func (h *Handler) checkOrder(ctx *gin.Context, id int) bool {
+	caller, ok := h.caller(ctx)
+	if !ok {
+		return false
+	}
+	if err := h.services.CheckOrderAccess(ctx.Request.Context(), caller, id); err != nil {
+		log.Println(err)
+		fail(ctx, err)
+		return false
+	}
+	return true
+}
+
+// checkUploadTarget answers 403 unless the caller may work with the courier,
+// delivery service, order or document the upload is for.
+func (h *Handler) checkUploadTarget(ctx *gin.Context, upload dao.Upload) bool {
+	switch upload.Kind {
+	case service.UploadCourierPhoto:
+		return h.checkCourier(ctx, upload.TargetId)
+	case service.UploadLogo:
+		return h.checkDeliveryService(ctx, upload.TargetId)
+	case service.UploadProofOfDelivery:
+		return h.checkOrder(ctx, upload.TargetId)
+	case service.UploadCourierDocument:
+		caller, ok := h.caller(ctx)
+		if !ok {
+			return false
+		}
+		if err := h.services.CheckDocumentAccess(ctx.Request.Context(), caller, upload.TargetId); err != nil {
+			log.Println(err)
+			fail(ctx, err)
+			return false
+		}
+	}
+	return true
+}
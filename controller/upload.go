@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"io"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/imaging"
+)
+
+// readImageUpload reads the request body, stopping one byte past the size the
+// service accepts so that an oversized upload is never buffered whole.
+func readImageUpload(ctx *gin.Context) ([]byte, bool) {
+	if ctx.Request.Body == nil {
+		badRequest(ctx, "empty")
+		return nil, false
+	}
+	defer ctx.Request.Body.Close()
+	upload, err := io.ReadAll(io.LimitReader(ctx.Request.Body, int64(imaging.DefaultLimits.MaxBytes)+1))
+	if err != nil {
+		badRequest(ctx, "Invalid request")
+		return nil, false
+	}
+	if len(upload) == 0 {
+		badRequest(ctx, "empty")
+		return nil, false
+	}
+	return upload, true
+}
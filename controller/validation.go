@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"log"
+	"reflect"
+	"regexp"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"strings"
+)
+
+// phonePattern is a phone number in the E.164 format, the + being optional.
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+
+// pageQuery is the pagination of list endpoints. Pages hold up to 100 items.
+type pageQuery struct {
+	Page  int `form:"page" binding:"required,min=1"`
+	Limit int `form:"limit" binding:"required,min=1,max=100"`
+}
+
+// cursorPageQuery is the pagination of endpoints that also page by cursor:
+// after, even empty, switches to keyset pagination and the page isn't needed.
+type cursorPageQuery struct {
+	Page  int     `form:"page" binding:"required_without=After,omitempty,min=1"`
+	Limit int     `form:"limit" binding:"required,min=1,max=100"`
+	After *string `form:"after"`
+}
+
+// monthQuery is a month of the years the service has been delivering in.
+type monthQuery struct {
+	Month int `form:"month" binding:"required,min=1,max=12"`
+	Year  int `form:"year" binding:"required,min=2022"`
+}
+
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(fieldName)
+	// Fields of merge patches are validated by their value.
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.FieldByName("Value").Interface()
+	}, dao.PatchString{}, dao.PatchInt{}, dao.PatchBool{}, dao.PatchFloat{})
+	v.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		return phonePattern.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("order_status", func(fl validator.FieldLevel) bool {
+		return service.IsOrderStatus(fl.Field().String())
+	})
+}
+
+// fieldName names fields in validation errors the way clients send them.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// bindJSON reads the request body into dst and validates it. Requests that
+// aren't JSON of the expected shape are answered with 400, and invalid ones
+// with 400 listing the fields at fault.
+func bindJSON(ctx *gin.Context, dst interface{}) bool {
+	if err := ctx.ShouldBindJSON(dst); err != nil {
+		log.Println(err)
+		invalid(ctx, err)
+		return false
+	}
+	return true
+}
+
+// bindQuery reads and validates the query parameters into dst.
+func bindQuery(ctx *gin.Context, dst interface{}) bool {
+	if err := ctx.ShouldBindQuery(dst); err != nil {
+		invalid(ctx, err)
+		return false
+	}
+	return true
+}
+
+func invalid(ctx *gin.Context, err error) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		fail(ctx, apperr.Invalid(apperr.FieldError{Field: typeErr.Field, Message: "must be a " + typeErr.Type.String()}))
+		return
+	}
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		badRequest(ctx, "Invalid request")
+		return
+	}
+	fields := make([]apperr.FieldError, 0, len(errs))
+	for _, e := range errs {
+		fields = append(fields, apperr.FieldError{Field: e.Field(), Message: fieldMessage(e)})
+	}
+	fail(ctx, apperr.Invalid(fields...))
+}
+
+func fieldMessage(e validator.FieldError) string {
+	text := e.Kind() == reflect.String
+	switch e.Tag() {
+	case "required", "required_without":
+		return "is required"
+	case "email":
+		return "must be an email address"
+	case "phone":
+		return "must be a phone number, like +375291234567"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(e.Param()), ", ")
+	case "datetime":
+		return "must be a date like " + e.Param()
+	case "order_status":
+		return "must be ready to delivery or completed"
+	case "min", "gte":
+		if text {
+			return fmt.Sprintf("must be at least %s characters long", e.Param())
+		}
+		return "must be at least " + e.Param()
+	case "max", "lte":
+		if text {
+			return fmt.Sprintf("must be at most %s characters long", e.Param())
+		}
+		return "must be at most " + e.Param()
+	}
+	return "is invalid"
+}
@@ -0,0 +1,42 @@
+package dao
+
+// Caller is the user behind a request, with the delivery service they work
+// for and, for couriers, their courier record. Zero ids mean the user has
+// none yet.
+type Caller struct {
+	UserId            int
+	Role              string
+	DeliveryServiceId int
+	CourierId         int
+}
+
+func (c Caller) IsSuperadmin() bool {
+	return c.Role == "Superadmin"
+}
+
+// CanAccessDeliveryService reports whether the caller may work with the data
+// of the delivery service.
+func (c Caller) CanAccessDeliveryService(id int) bool {
+	return c.IsSuperadmin() || c.DeliveryServiceId != 0 && c.DeliveryServiceId == id
+}
+
+// CanAccessCourier reports whether the caller may work with the courier of
+// the delivery service: couriers only with themselves, managers with the
+// couriers of their delivery service.
+func (c Caller) CanAccessCourier(courierId, deliveryServiceId int) bool {
+	if c.Role == "Courier" {
+		return c.CourierId != 0 && c.CourierId == courierId
+	}
+	return c.CanAccessDeliveryService(deliveryServiceId)
+}
+
+// CanAccessOrder reports whether the caller may work with the order of the
+// delivery service assigned to the courier: couriers with their own orders
+// and the unassigned orders of their delivery service, managers with all
+// orders of their delivery service.
+func (c Caller) CanAccessOrder(deliveryServiceId, courierId int) bool {
+	if !c.CanAccessDeliveryService(deliveryServiceId) {
+		return false
+	}
+	return c.Role != "Courier" || courierId == 0 || courierId == c.CourierId
+}
@@ -0,0 +1,30 @@
+package dao
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCaller_CanAccess(t *testing.T) {
+	superadmin := Caller{UserId: 1, Role: "Superadmin"}
+	manager := Caller{UserId: 7, Role: "Courier manager", DeliveryServiceId: 2}
+	courier := Caller{UserId: 9, Role: "Courier", DeliveryServiceId: 2, CourierId: 4}
+	newcomer := Caller{UserId: 10, Role: "Courier manager"}
+
+	assert.True(t, superadmin.CanAccessDeliveryService(3))
+	assert.True(t, manager.CanAccessDeliveryService(2))
+	assert.False(t, manager.CanAccessDeliveryService(3))
+	assert.False(t, newcomer.CanAccessDeliveryService(0))
+
+	assert.True(t, manager.CanAccessCourier(5, 2))
+	assert.False(t, manager.CanAccessCourier(5, 3))
+	assert.True(t, courier.CanAccessCourier(4, 2))
+	assert.False(t, courier.CanAccessCourier(5, 2))
+
+	assert.True(t, manager.CanAccessOrder(2, 5))
+	assert.True(t, courier.CanAccessOrder(2, 4))
+	assert.True(t, courier.CanAccessOrder(2, 0))
+	assert.False(t, courier.CanAccessOrder(2, 5))
+	assert.False(t, courier.CanAccessOrder(3, 0))
+	assert.True(t, superadmin.CanAccessOrder(3, 5))
+}
@@ -0,0 +1,32 @@
+package dao
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Config is how the repositories talk to the database.
+type Config struct {
+	// QueryTimeout bounds each call of a repository, whatever the deadline
+	// of its context; 0 leaves calls to their context.
+	QueryTimeout time.Duration
+}
+
+// ConfigFromEnv reads DB_QUERY_TIMEOUT (5s by default).
+func ConfigFromEnv() Config {
+	cfg := Config{QueryTimeout: 5 * time.Second}
+	if timeout, err := time.ParseDuration(os.Getenv("DB_QUERY_TIMEOUT")); err == nil && timeout >= 0 {
+		cfg.QueryTimeout = timeout
+	}
+	return cfg
+}
+
+// withQueryTimeout is the context of one call of a repository. It is done
+// when ctx is, e.g. when the client goes away, or when timeout has passed.
+func withQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
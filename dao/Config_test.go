@@ -0,0 +1,58 @@
+package dao
+
+import (
+	"context"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestRepository_QueryTimeout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{QueryTimeout: 10 * time.Millisecond})
+
+	mock.ExpectQuery(`SELECT id, courier_id, type, capacity_kg, plate FROM vehicles WHERE courier_id = \$1`).
+		WithArgs(5).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "courier_id", "type", "capacity_kg", "plate"}))
+
+	start := time.Now()
+	_, err = r.GetVehicleOfCourierFromDB(context.Background(), 5)
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRepository_CanceledContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectQuery(`SELECT id, courier_id, type, capacity_kg, plate FROM vehicles WHERE courier_id = \$1`).
+		WithArgs(5).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "courier_id", "type", "capacity_kg", "plate"}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = r.GetVehicleOfCourierFromDB(ctx, 5)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("DB_QUERY_TIMEOUT", "")
+	assert.Equal(t, 5*time.Second, ConfigFromEnv().QueryTimeout)
+
+	t.Setenv("DB_QUERY_TIMEOUT", "250ms")
+	assert.Equal(t, 250*time.Millisecond, ConfigFromEnv().QueryTimeout)
+}
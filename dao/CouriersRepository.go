@@ -1,63 +1,113 @@
 package dao
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/lib/pq"
 	"log"
+	"time"
 )
 
 type CourierPostgres struct {
-	db *sql.DB
+	db      *sql.DB
+	timeout time.Duration
 }
 
-func NewCourierPostgres(db *sql.DB) *CourierPostgres {
-	return &CourierPostgres{db: db}
+func NewCourierPostgres(db *sql.DB, timeout time.Duration) *CourierPostgres {
+	return &CourierPostgres{db: db, timeout: timeout}
 }
 
+var (
+	// ErrUserHasCourier is returned when saving a second courier of a user.
+	ErrUserHasCourier = errors.New("user already has a courier")
+	// ErrSeveralCouriers is returned when looking up the courier of a user
+	// who has more than one, which only rows older than the unique index on
+	// couriers.user_id can do.
+	ErrSeveralCouriers = errors.New("user has several couriers")
+)
+
 type Courier struct {
-	Id                uint16 `json:"id_courier"`
-	UserId            int    `json:"user_id"`
-	CourierName       string `json:"courier_name"`
-	ReadyToGo         bool   `json:"ready_to_go"`
-	PhoneNumber       string `json:"phone_number"`
-	Email             string `json:"email"`
-	Rating            uint16 `json:"rating"`
-	Photo             string `json:"photo"`
-	Surname           string `json:"surname"`
-	NumberOfFailures  uint16 `json:"number_of_failures"`
-	Deleted           bool   `json:"deleted"`
-	DeliveryServiceId uint16 `json:"delivery_service_id"`
+	Id                uint16           `json:"id_courier"`
+	UserId            int              `json:"user_id"`
+	CourierName       string           `json:"courier_name"`
+	ReadyToGo         bool             `json:"ready_to_go"`
+	PhoneNumber       string           `json:"phone_number"`
+	Email             string           `json:"email"`
+	Rating            uint16           `json:"rating"`
+	Photo             string           `json:"photo"`
+	PhotoMedium       string           `json:"photo_medium"`
+	PhotoThumbnail    string           `json:"photo_thumbnail"`
+	Surname           string           `json:"surname"`
+	NumberOfFailures  uint16           `json:"number_of_failures"`
+	Deleted           bool             `json:"deleted"`
+	DeliveryServiceId uint16           `json:"delivery_service_id"`
+	Status            string           `json:"status"`
+	StatusReason      string           `json:"status_reason,omitempty"`
+	Onboarding        []OnboardingStep `json:"onboarding,omitempty"`
+	// Version is sent as the ETag of the courier.
+	Version int `json:"-"`
 }
 
-type SmallInfo struct {
-	Id          uint16 `json:"id_courier"`
-	CourierName string `json:"courier_name"`
-	PhoneNumber string `json:"phone_number"`
-	Photo       string `json:"photo"`
-	Surname     string `json:"surname"`
-	Deleted     bool   `json:"deleted"`
+// OnboardingStep is one change of a courier's onboarding status.
+type OnboardingStep struct {
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
-func (r *CourierPostgres) SaveCourierInDB(courier *Courier) error {
+type SmallInfo struct {
+	Id             uint16 `json:"id_courier"`
+	CourierName    string `json:"courier_name"`
+	PhoneNumber    string `json:"phone_number"`
+	Photo          string `json:"photo"`
+	PhotoThumbnail string `json:"photo_thumbnail"`
+	Surname        string `json:"surname"`
+	Deleted        bool   `json:"deleted"`
+}
 
-	insertValue := `INSERT INTO "couriers" ("user_id","name","ready to go","phone_number","email","photo","surname", "delivery_service_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`
+// SaveCourierInDB creates the courier in the given onboarding status and
+// records it as the first onboarding step.
+func (r *CourierPostgres) SaveCourierInDB(ctx context.Context, courier *Courier) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	transaction, err := beginTx(ctx, r.db)
+	if err != nil {
+		log.Println("Error of saving courier in dao :" + err.Error())
+		return err
+	}
+	defer transaction.Rollback()
 
-	_, err := r.db.Exec(insertValue, courier.UserId, courier.CourierName, courier.ReadyToGo, courier.PhoneNumber, courier.Email, courier.Photo, courier.Surname, courier.DeliveryServiceId)
+	insertValue := `INSERT INTO "couriers" ("user_id","name","ready to go","phone_number","email","photo","surname", "delivery_service_id","status") VALUES ($1,$2,$3,$4,$5,$6,$7,NULLIF($8, 0),$9) RETURNING "id_courier"`
 
+	err = transaction.QueryRowContext(ctx, insertValue, courier.UserId, courier.CourierName, courier.ReadyToGo, courier.PhoneNumber, courier.Email, courier.Photo, courier.Surname, courier.DeliveryServiceId, courier.Status).Scan(&courier.Id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "couriers_user_id_key" {
+		return ErrUserHasCourier
+	}
 	if err != nil {
-
 		log.Println("Error of saving courier in dao :" + err.Error())
 		return err
 	}
-	return nil
+	if _, err := transaction.ExecContext(ctx, `INSERT INTO courier_onboarding (courier_id, status) VALUES ($1, $2)`, courier.Id, courier.Status); err != nil {
+		log.Println("Error of saving courier in dao :" + err.Error())
+		return err
+	}
+	return transaction.Commit()
 }
 
-func (r *CourierPostgres) GetCouriersFromDB() ([]SmallInfo, error) {
+// GetCouriersFromDB lists the couriers of the delivery service, or of all
+// delivery services when idService is 0.
+func (r *CourierPostgres) GetCouriersFromDB(ctx context.Context, idService int) ([]SmallInfo, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var Couriers []SmallInfo
 
-	selectValue := `Select "id_courier","name", "phone_number","photo", "surname", "deleted" from "couriers" order by "surname"`
+	selectValue := `Select "id_courier","name", "phone_number","photo","photo_thumbnail", "surname", "deleted" from "couriers"
+			where $1 = 0 or "delivery_service_id" = $1 order by "surname"`
 
-	get, err := r.db.Query(selectValue)
+	get, err := conn(ctx, r.db).QueryContext(ctx, selectValue, idService)
 
 	if err != nil {
 
@@ -67,36 +117,52 @@ func (r *CourierPostgres) GetCouriersFromDB() ([]SmallInfo, error) {
 
 	for get.Next() {
 		var courier SmallInfo
-		err = get.Scan(&courier.Id, &courier.CourierName, &courier.PhoneNumber, &courier.Photo, &courier.Surname, &courier.Deleted)
+		err = get.Scan(&courier.Id, &courier.CourierName, &courier.PhoneNumber, &courier.Photo, &courier.PhotoThumbnail, &courier.Surname, &courier.Deleted)
 		Couriers = append(Couriers, courier)
 	}
 	return Couriers, nil
 }
 
-func (r *CourierPostgres) GetCourierFromDB(id int) (Courier, error) {
+// GetCourierFromDB returns the courier of the user, or a zero Courier when
+// the user has none.
+func (r *CourierPostgres) GetCourierFromDB(ctx context.Context, id int) (Courier, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var courier Courier
 
-	selectValue := `Select id_courier,name,phone_number,photo, surname, deleted,email,delivery_service_id
-			from couriers where user_id = $1`
+	selectValue := `Select id_courier,name,phone_number,photo,photo_medium,photo_thumbnail, surname, deleted,email,coalesce(delivery_service_id, 0),status,status_reason,version
+			from couriers where user_id = $1 LIMIT 2`
 
-	get, err := r.db.Query(selectValue, id)
+	get, err := conn(ctx, r.db).QueryContext(ctx, selectValue, id)
 
 	if err != nil {
 		log.Println("Error of getting courier :" + err.Error())
 		return Courier{}, err
 	}
+	defer get.Close()
 
+	found := 0
 	for get.Next() {
-		err = get.Scan(&courier.Id, &courier.CourierName, &courier.PhoneNumber, &courier.Photo, &courier.Surname,
-			&courier.Deleted, &courier.Email, &courier.DeliveryServiceId)
+		if found++; found > 1 {
+			log.Printf("Error of getting courier : user %d has several couriers", id)
+			return Courier{}, ErrSeveralCouriers
+		}
+		err = get.Scan(&courier.Id, &courier.CourierName, &courier.PhoneNumber, &courier.Photo, &courier.PhotoMedium,
+			&courier.PhotoThumbnail, &courier.Surname, &courier.Deleted, &courier.Email, &courier.DeliveryServiceId, &courier.Status, &courier.StatusReason, &courier.Version)
+		if err != nil {
+			log.Println("Error of getting courier :" + err.Error())
+			return Courier{}, err
+		}
 	}
-	return courier, nil
+	return courier, get.Err()
 }
 
-func (r *CourierPostgres) UpdateCourierInDB(id uint16, status bool) (uint16, error) {
+func (r *CourierPostgres) UpdateCourierInDB(ctx context.Context, id uint16, status bool) (uint16, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 
 	UpdateValue := `UPDATE couriers SET deleted = $1 WHERE id_courier = $2`
-	_, err := r.db.Exec(UpdateValue, status, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, UpdateValue, status, id)
 	if err != nil {
 		log.Println("Error with getting courier by id: " + err.Error())
 		return 0, fmt.Errorf("updateCourier: error while scanning:%w", err)
@@ -104,12 +170,14 @@ func (r *CourierPostgres) UpdateCourierInDB(id uint16, status bool) (uint16, err
 	return id, nil
 }
 
-func (r *CourierPostgres) GetCouriersWithServiceFromDB() ([]Courier, error) {
+func (r *CourierPostgres) GetCouriersWithServiceFromDB(ctx context.Context) ([]Courier, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var Couriers []Courier
 
-	selectValue := `Select "id_courier","name", "phone_number","photo", "surname","delivery_service_id" from "couriers"`
+	selectValue := `Select "id_courier","name", "phone_number","photo","photo_medium","photo_thumbnail", "surname",coalesce("delivery_service_id", 0) from "couriers"`
 
-	get, err := r.db.Query(selectValue)
+	get, err := conn(ctx, r.db).QueryContext(ctx, selectValue)
 
 	if err != nil {
 		log.Println("Error of getting list of couriers :" + err.Error())
@@ -118,32 +186,38 @@ func (r *CourierPostgres) GetCouriersWithServiceFromDB() ([]Courier, error) {
 
 	for get.Next() {
 		var courier Courier
-		err = get.Scan(&courier.Id, &courier.CourierName, &courier.PhoneNumber, &courier.Photo, &courier.Surname, &courier.DeliveryServiceId)
+		err = get.Scan(&courier.Id, &courier.CourierName, &courier.PhoneNumber, &courier.Photo, &courier.PhotoMedium, &courier.PhotoThumbnail,
+			&courier.Surname, &courier.DeliveryServiceId)
 		Couriers = append(Couriers, courier)
 	}
 	return Couriers, nil
 }
 
-func (r *CourierPostgres) UpdateCourierDB(courier Courier) error {
+// UpdateCourierDB overwrites the fields of the courier that are set. It
+// returns sql.ErrNoRows for an unknown courier and ErrVersionMismatch when
+// courier.Version isn't 0 and the courier has another version.
+func (r *CourierPostgres) UpdateCourierDB(ctx context.Context, courier Courier) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var oldCourier Courier
-	transaction, err := r.db.Begin()
-	if err != nil {
-		log.Println(err)
-	}
-	defer transaction.Commit()
-	res, err := transaction.Query(`SELECT id_courier, name, surname, delivery_service_id, email, photo, phone_number
-                                  FROM couriers Where id_courier=$1`, courier.Id)
+	transaction, err := beginTx(ctx, r.db)
 	if err != nil {
 		log.Println(err)
 		return err
 	}
-	for res.Next() {
-		err = res.Scan(&oldCourier.Id, &oldCourier.CourierName, &oldCourier.Surname, &oldCourier.DeliveryServiceId, &oldCourier.Email,
-			&oldCourier.Photo, &oldCourier.PhoneNumber)
-		if err != nil {
+	defer transaction.Rollback()
+	err = transaction.QueryRowContext(ctx, `SELECT id_courier, name, surname, coalesce(delivery_service_id, 0), email, photo, photo_medium, photo_thumbnail, phone_number, version
+                                  FROM couriers Where id_courier=$1 FOR UPDATE`, courier.Id).
+		Scan(&oldCourier.Id, &oldCourier.CourierName, &oldCourier.Surname, &oldCourier.DeliveryServiceId, &oldCourier.Email,
+			&oldCourier.Photo, &oldCourier.PhotoMedium, &oldCourier.PhotoThumbnail, &oldCourier.PhoneNumber, &oldCourier.Version)
+	if err != nil {
+		if err != sql.ErrNoRows {
 			log.Println(err)
-			return err
 		}
+		return err
+	}
+	if courier.Version != 0 && courier.Version != oldCourier.Version {
+		return ErrVersionMismatch
 	}
 	if courier.CourierName == "" {
 		courier.CourierName = oldCourier.CourierName
@@ -154,6 +228,12 @@ func (r *CourierPostgres) UpdateCourierDB(courier Courier) error {
 	if courier.Photo == "" {
 		courier.Photo = oldCourier.Photo
 	}
+	if courier.PhotoMedium == "" {
+		courier.PhotoMedium = oldCourier.PhotoMedium
+	}
+	if courier.PhotoThumbnail == "" {
+		courier.PhotoThumbnail = oldCourier.PhotoThumbnail
+	}
 	if courier.Surname == "" {
 		courier.Surname = oldCourier.Surname
 	}
@@ -167,50 +247,261 @@ func (r *CourierPostgres) UpdateCourierDB(courier Courier) error {
 		courier.Deleted = oldCourier.Deleted
 	}
 
-	s := `UPDATE couriers SET name=$1, surname=$2, delivery_service_id=$3, email=$4, photo=$5, phone_number=$6, deleted=$7
-                            WHERE id_courier = $8`
-	log.Println(s)
-	insert, err := transaction.Query(s, courier.CourierName, courier.Surname, courier.DeliveryServiceId, courier.Email,
-		courier.Photo, courier.PhoneNumber, courier.Deleted, courier.Id)
-	defer insert.Close()
-	if err != nil {
+	s := `UPDATE couriers SET name=$1, surname=$2, delivery_service_id=NULLIF($3, 0), email=$4, photo=$5, phone_number=$6, deleted=$7,
+                            photo_medium=$8, photo_thumbnail=$9 WHERE id_courier = $10`
+	if _, err := transaction.ExecContext(ctx, s, courier.CourierName, courier.Surname, courier.DeliveryServiceId, courier.Email,
+		courier.Photo, courier.PhoneNumber, courier.Deleted, courier.PhotoMedium, courier.PhotoThumbnail, courier.Id); err != nil {
 		log.Println(err)
 		return err
 	}
-	return nil
+	return transaction.Commit()
 }
 
-func (r *CourierPostgres) GetCouriersOfCourierServiceFromDB(limit, page, idService int) ([]Courier, int) {
-	var Couriers []Courier
-	transaction, err := r.db.Begin()
+// CourierPatch is a merge patch of the fields of a courier its managers edit.
+type CourierPatch struct {
+	CourierName       PatchString
+	Surname           PatchString
+	PhoneNumber       PatchString
+	Email             PatchString
+	DeliveryServiceId PatchInt
+	Deleted           PatchBool
+	// Version is the version of the courier the patch was made against; 0
+	// patches any version.
+	Version int
+}
+
+// PatchCourierInDB applies patch to the courier in one transaction that
+// locks the row, and returns the courier as patched. It returns
+// sql.ErrNoRows for an unknown courier and ErrVersionMismatch for a patch
+// made against another version.
+func (r *CourierPostgres) PatchCourierInDB(ctx context.Context, id int, patch CourierPatch) (Courier, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var courier Courier
+	transaction, err := beginTx(ctx, r.db)
 	if err != nil {
 		log.Println(err)
+		return courier, err
+	}
+	defer transaction.Rollback()
+
+	if err := transaction.QueryRowContext(ctx, `SELECT version FROM couriers WHERE id_courier = $1 FOR UPDATE`, id).Scan(&courier.Version); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println(err)
+		}
+		return courier, err
+	}
+	if patch.Version != 0 && patch.Version != courier.Version {
+		return courier, ErrVersionMismatch
+	}
+	var set patchSet
+	set.add("name", patch.CourierName.Set, patch.CourierName.Value)
+	set.add("surname", patch.Surname.Set, patch.Surname.Value)
+	set.add("phone_number", patch.PhoneNumber.Set, patch.PhoneNumber.Value)
+	set.add("email", patch.Email.Set, patch.Email.Value)
+	set.add("delivery_service_id", patch.DeliveryServiceId.Set, patch.DeliveryServiceId.Value)
+	set.add("deleted", patch.Deleted.Set, patch.Deleted.Value)
+	if !set.empty() {
+		query, args := set.update("couriers", "id_courier", id)
+		if _, err := transaction.ExecContext(ctx, query, args...); err != nil {
+			log.Println(err)
+			return courier, fmt.Errorf("patchCourier: %w", err)
+		}
 	}
-	defer transaction.Commit()
-	//запрос!!!
-	res, err := transaction.Query("SELECT id_courier,name,surname,phone_number,email,rating,photo,deleted,delivery_service_id FROM couriers Where delivery_service_id=$1 ORDER BY surname LIMIT $2 OFFSET $3", idService, limit, limit*(page-1))
+	err = transaction.QueryRowContext(ctx, `SELECT id_courier, user_id, name, "ready to go", phone_number, email, rating, photo, photo_medium, photo_thumbnail,
+       surname, number_of_failures, deleted, coalesce(delivery_service_id, 0), status, status_reason, version FROM couriers WHERE id_courier = $1`, id).
+		Scan(&courier.Id, &courier.UserId, &courier.CourierName, &courier.ReadyToGo, &courier.PhoneNumber, &courier.Email, &courier.Rating,
+			&courier.Photo, &courier.PhotoMedium, &courier.PhotoThumbnail, &courier.Surname, &courier.NumberOfFailures, &courier.Deleted,
+			&courier.DeliveryServiceId, &courier.Status, &courier.StatusReason, &courier.Version)
 	if err != nil {
 		log.Println(err)
+		return courier, fmt.Errorf("patchCourier: %w", err)
 	}
+	return courier, transaction.Commit()
+}
+
+func (r *CourierPostgres) GetCouriersOfCourierServiceFromDB(ctx context.Context, limit, page, idService int) ([]Courier, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var Couriers []Courier
+	db := conn(ctx, r.db)
+	res, err := db.QueryContext(ctx, "SELECT id_courier,name,surname,phone_number,email,rating,photo,photo_medium,photo_thumbnail,deleted,delivery_service_id FROM couriers Where delivery_service_id=$1 ORDER BY surname LIMIT $2 OFFSET $3",
+		idService, limit, limit*(page-1))
+	if err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+	defer res.Close()
 	for res.Next() {
 		var courier Courier
-		err = res.Scan(&courier.Id, &courier.CourierName, &courier.Surname, &courier.PhoneNumber, &courier.Email, &courier.Rating, &courier.Photo, &courier.Deleted, &courier.DeliveryServiceId)
+		err = res.Scan(&courier.Id, &courier.CourierName, &courier.Surname, &courier.PhoneNumber, &courier.Email, &courier.Rating, &courier.Photo, &courier.PhotoMedium, &courier.PhotoThumbnail, &courier.Deleted, &courier.DeliveryServiceId)
 		if err != nil {
 			log.Println(err)
+			return nil, 0, err
 		}
 		Couriers = append(Couriers, courier)
 	}
+	if err := res.Err(); err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
 
 	var length int
-	resl, err := transaction.Query("SELECT count(*) FROM couriers WHERE delivery_service_id=$1", idService)
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM couriers WHERE delivery_service_id=$1", idService).Scan(&length); err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+	return Couriers, length, nil
+}
+
+func (r *CourierPostgres) SetCourierReadyToGoInDB(ctx context.Context, id int, ready bool) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE couriers SET "ready to go" = $1 WHERE id_courier = $2`, ready, id); err != nil {
+		log.Println(err)
+		return fmt.Errorf("setCourierReadyToGo: %w", err)
+	}
+	return nil
+}
+
+// GetDeliveryServiceOfCourierFromDB returns sql.ErrNoRows for an unknown
+// courier and 0 for a courier without a delivery service.
+func (r *CourierPostgres) GetDeliveryServiceOfCourierFromDB(ctx context.Context, id int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var idService sql.NullInt64
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT delivery_service_id FROM couriers WHERE id_courier = $1`, id).Scan(&idService)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println(err)
+		}
+		return 0, err
+	}
+	return int(idService.Int64), nil
+}
+
+// DeleteCourierFromDB removes a courier that was just registered, together
+// with its onboarding steps. It undoes SaveCourierInDB.
+func (r *CourierPostgres) DeleteCourierFromDB(ctx context.Context, id int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	transaction, err := beginTx(ctx, r.db)
 	if err != nil {
 		log.Println(err)
+		return err
 	}
-	for resl.Next() {
-		err = resl.Scan(&length)
-		if err != nil {
+	defer transaction.Rollback()
+	if _, err := transaction.ExecContext(ctx, `DELETE FROM courier_onboarding WHERE courier_id = $1`, id); err != nil {
+		log.Println(err)
+		return fmt.Errorf("deleteCourier: %w", err)
+	}
+	if _, err := transaction.ExecContext(ctx, `DELETE FROM couriers WHERE id_courier = $1`, id); err != nil {
+		log.Println(err)
+		return fmt.Errorf("deleteCourier: %w", err)
+	}
+	return transaction.Commit()
+}
+
+// SetCourierStatusInDB moves the courier to status if its current status is
+// one of from, and records the step. It reports false when the courier is in
+// another status.
+func (r *CourierPostgres) SetCourierStatusInDB(ctx context.Context, id int, from []string, status, reason string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	transaction, err := beginTx(ctx, r.db)
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	defer transaction.Rollback()
+
+	res, err := transaction.ExecContext(ctx, `UPDATE couriers SET status = $1, status_reason = $2 WHERE id_courier = $3 AND status = ANY($4)`,
+		status, reason, id, pq.Array(from))
+	if err != nil {
+		log.Println(err)
+		return false, fmt.Errorf("setCourierStatus: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if _, err := transaction.ExecContext(ctx, `INSERT INTO courier_onboarding (courier_id, status, reason) VALUES ($1, $2, $3)`, id, status, reason); err != nil {
+		log.Println(err)
+		return false, fmt.Errorf("setCourierStatus: %w", err)
+	}
+	if err := transaction.Commit(); err != nil {
+		log.Println(err)
+		return false, err
+	}
+	return true, nil
+}
+
+// LockCourierInDB locks the row of the courier until the end of the unit
+// of work ctx runs in. It returns sql.ErrNoRows for an unknown courier.
+func (r *CourierPostgres) LockCourierInDB(ctx context.Context, id int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var locked int
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT id_courier FROM couriers WHERE id_courier = $1 FOR UPDATE`, id).Scan(&locked)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Println(err)
+	}
+	return err
+}
+
+// GetCourierStatusFromDB returns sql.ErrNoRows for an unknown courier.
+func (r *CourierPostgres) GetCourierStatusFromDB(ctx context.Context, id int) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var status string
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT status FROM couriers WHERE id_courier = $1`, id).Scan(&status)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Println(err)
+	}
+	return status, err
+}
+
+func (r *CourierPostgres) GetOnboardingOfCourierFromDB(ctx context.Context, id int) ([]OnboardingStep, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var steps []OnboardingStep
+	res, err := conn(ctx, r.db).QueryContext(ctx, `SELECT status, reason, created_at FROM courier_onboarding WHERE courier_id = $1 ORDER BY created_at, id`, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	defer res.Close()
+	for res.Next() {
+		var step OnboardingStep
+		if err := res.Scan(&step.Status, &step.Reason, &step.CreatedAt); err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, res.Err()
+}
+
+// GetCouriersByStatusFromDB lists the couriers of the delivery service in
+// the given onboarding status, oldest applications first.
+func (r *CourierPostgres) GetCouriersByStatusFromDB(ctx context.Context, idService int, status string) ([]Courier, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var couriers []Courier
+	res, err := conn(ctx, r.db).QueryContext(ctx, `SELECT id_courier, user_id, name, surname, phone_number, email, photo_thumbnail, delivery_service_id, status, status_reason
+FROM couriers WHERE delivery_service_id = $1 AND status = $2 AND NOT deleted ORDER BY id_courier`, idService, status)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	defer res.Close()
+	for res.Next() {
+		var courier Courier
+		if err := res.Scan(&courier.Id, &courier.UserId, &courier.CourierName, &courier.Surname, &courier.PhoneNumber, &courier.Email,
+			&courier.PhotoThumbnail, &courier.DeliveryServiceId, &courier.Status, &courier.StatusReason); err != nil {
 			log.Println(err)
+			return nil, err
 		}
+		couriers = append(couriers, courier)
 	}
-	return Couriers, length
+	return couriers, res.Err()
 }
@@ -0,0 +1,193 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func TestRepository_SetCourierStatusInDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	from := []string{"pending_review"}
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE couriers SET status = \$1, status_reason = \$2 WHERE id_courier = \$3 AND status = ANY\(\$4\)`).
+		WithArgs("rejected", "no photo", 5, pq.Array(from)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO courier_onboarding \(courier_id, status, reason\) VALUES \(\$1, \$2, \$3\)`).
+		WithArgs(5, "rejected", "no photo").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	ok, err := r.SetCourierStatusInDB(context.Background(), 5, from, "rejected", "no photo")
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_SetCourierStatusInDB_WrongStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	from := []string{"pending_review"}
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE couriers SET status = \$1`).
+		WithArgs("approved", "", 5, pq.Array(from)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	ok, err := r.SetCourierStatusInDB(context.Background(), 5, from, "approved", "")
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_PatchCourierInDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT version FROM couriers WHERE id_courier = \$1 FOR UPDATE`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(`UPDATE couriers SET email = \$1, deleted = \$2 WHERE id_courier = \$3`).
+		WithArgs("", false, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id_courier, user_id, name`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id_courier", "user_id", "name", "ready to go", "phone_number", "email", "rating", "photo",
+			"photo_medium", "photo_thumbnail", "surname", "number_of_failures", "deleted", "delivery_service_id", "status", "status_reason", "version"}).
+			AddRow(5, 9, "Ivan", false, "+375291234567", "", 4, "", "", "", "Petrov", 0, false, 2, "approved", "", 4))
+	mock.ExpectCommit()
+
+	courier, err := r.PatchCourierInDB(context.Background(), 5, CourierPatch{
+		Email:   PatchString{Set: true, Null: true},
+		Deleted: PatchBool{Set: true},
+		Version: 3,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Courier{Id: 5, UserId: 9, CourierName: "Ivan", PhoneNumber: "+375291234567", Rating: 4, Surname: "Petrov",
+		DeliveryServiceId: 2, Status: "approved", Version: 4}, courier)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_PatchCourierInDB_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT version FROM couriers WHERE id_courier = \$1 FOR UPDATE`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
+	mock.ExpectRollback()
+
+	_, err = r.PatchCourierInDB(context.Background(), 5, CourierPatch{Surname: PatchString{Set: true, Value: "Petrov"}})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_PatchCourierInDB_VersionMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT version FROM couriers WHERE id_courier = \$1 FOR UPDATE`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+	mock.ExpectRollback()
+
+	_, err = r.PatchCourierInDB(context.Background(), 5, CourierPatch{Surname: PatchString{Set: true, Value: "Petrov"}, Version: 3})
+
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_UpdateCourierDB_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id_courier, (.+) FROM couriers Where id_courier=\$1 FOR UPDATE`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id_courier", "name", "surname", "delivery_service_id", "email", "photo", "photo_medium", "photo_thumbnail", "phone_number", "version"}))
+	mock.ExpectRollback()
+
+	err = r.UpdateCourierDB(context.Background(), Courier{Id: 5, Surname: "Petrov"})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetCourierFromDB_SeveralCouriers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	columns := []string{"id_courier", "name", "phone_number", "photo", "photo_medium", "photo_thumbnail", "surname",
+		"deleted", "email", "delivery_service_id", "status", "status_reason", "version"}
+	mock.ExpectQuery(`Select id_courier,(.+) from couriers where user_id = \$1 LIMIT 2`).
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(4, "Ivan", "", "", "", "", "Petrov", false, "", 1, "approved", "", 1).
+			AddRow(7, "Ivan", "", "", "", "", "Petrov", false, "", 2, "applied", "", 1))
+
+	_, err = r.GetCourierFromDB(context.Background(), 9)
+
+	assert.ErrorIs(t, err, ErrSeveralCouriers)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_SaveCourierInDB_UserHasCourier(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "couriers" (.+) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7,NULLIF\(\$8, 0\),\$9\)`).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "couriers_user_id_key"})
+	mock.ExpectRollback()
+
+	err = r.SaveCourierInDB(context.Background(), &Courier{UserId: 9, CourierName: "Ivan", Status: "applied"})
+
+	assert.ErrorIs(t, err, ErrUserHasCourier)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
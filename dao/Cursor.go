@@ -0,0 +1,91 @@
+package dao
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"strings"
+)
+
+// Cursor is the position of the last row of a keyset page. Clients receive it
+// as an opaque token and send it back in the "after" query parameter.
+type Cursor struct {
+	Id        int    `json:"id"`
+	OrderDate string `json:"order_date,omitempty"`
+	CourierId int    `json:"courier_id,omitempty"`
+}
+
+type CursorPagination struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func (c Cursor) IsEmpty() bool {
+	return c == Cursor{}
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(token string) (Cursor, error) {
+	var cursor Cursor
+	if token == "" {
+		return cursor, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, apperr.New(apperr.Validation, "invalid cursor")
+	}
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return Cursor{}, apperr.New(apperr.Validation, "invalid cursor")
+	}
+	return cursor, nil
+}
+
+func CursorFromOrder(order Order) Cursor {
+	return Cursor{Id: order.Id, OrderDate: order.OrderDate, CourierId: order.IdCourier}
+}
+
+func CursorFromDetailedOrder(order DetailedOrder) Cursor {
+	return Cursor{Id: order.IdOrder, OrderDate: order.OrderDate, CourierId: order.IdCourier}
+}
+
+// keyset appends the "after cursor" condition, the ordering on the key columns
+// and the limit to a query whose WHERE clause is already present.
+func keyset(query string, args []interface{}, columns []string, after []interface{}, limit int) (string, []interface{}) {
+	if len(after) != 0 {
+		placeholders := make([]string, len(after))
+		for i, value := range after {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		query += fmt.Sprintf(" and (%s) > (%s)", strings.Join(columns, ","), strings.Join(placeholders, ","))
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", strings.Join(columns, ","), len(args))
+	return query, args
+}
+
+func afterId(cursor Cursor) []interface{} {
+	if cursor.IsEmpty() {
+		return nil
+	}
+	return []interface{}{cursor.Id}
+}
+
+func afterOrderDate(cursor Cursor) []interface{} {
+	if cursor.IsEmpty() {
+		return nil
+	}
+	return []interface{}{cursor.OrderDate, cursor.Id}
+}
+
+func afterCourierId(cursor Cursor) []interface{} {
+	if cursor.IsEmpty() {
+		return nil
+	}
+	return []interface{}{cursor.CourierId, cursor.Id}
+}
@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar day. It is read and written as "2006-01-02" in JSON and
+// maps to a DATE column.
+type Date struct {
+	time.Time
+}
+
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func Today() Date {
+	now := time.Now()
+	return NewDate(now.Year(), now.Month(), now.Day())
+}
+
+func ParseDate(value string) (Date, error) {
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return Date{}, fmt.Errorf("expected a date like 2006-01-02, got %q", value)
+	}
+	return Date{t}, nil
+}
+
+func (d Date) AddDays(days int) Date {
+	return Date{d.Time.AddDate(0, 0, days)}
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(dateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "" || value == "null" {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d *Date) Scan(src interface{}) error {
+	t, ok := src.(time.Time)
+	if !ok {
+		return fmt.Errorf("dao.Date: cannot scan %T", src)
+	}
+	*d = NewDate(t.Year(), t.Month(), t.Day())
+	return nil
+}
+
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
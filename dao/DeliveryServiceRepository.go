@@ -1,36 +1,47 @@
 package dao
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 )
 
 type DeliveryServicePostgres struct {
-	db *sql.DB
+	db      *sql.DB
+	timeout time.Duration
 }
 
-func NewDeliveryServicePostgres(db *sql.DB) *DeliveryServicePostgres {
-	return &DeliveryServicePostgres{db: db}
+func NewDeliveryServicePostgres(db *sql.DB, timeout time.Duration) *DeliveryServicePostgres {
+	return &DeliveryServicePostgres{db: db, timeout: timeout}
 }
 
 type DeliveryService struct {
-	Id            int    `json:"id"`
-	Name          string `json:"name"`
-	Email         string `json:"email"`
-	Photo         string `json:"photo"`
-	Description   string `json:"description"`
-	PhoneNumber   string `json:"phone_number"`
-	ManagerId     int    `json:"manager_id"`
-	Status        string `json:"status"`
-	NumOfCouriers int
+	Id             int     `json:"id"`
+	Name           string  `json:"name"`
+	Email          string  `json:"email"`
+	Photo          string  `json:"photo"`
+	PhotoMedium    string  `json:"photo_medium"`
+	PhotoThumbnail string  `json:"photo_thumbnail"`
+	Description    string  `json:"description"`
+	PhoneNumber    string  `json:"phone_number"`
+	ManagerId      int     `json:"manager_id"`
+	Status         string  `json:"status"`
+	CourierRate    float64 `json:"courier_rate"`
+	NumOfCouriers  int
+	// Version is sent as the ETag of the delivery service.
+	Version int `json:"-"`
 }
 
-func (r *DeliveryServicePostgres) SaveDeliveryServiceInDB(service *DeliveryService) (int, error) {
-	row := r.db.QueryRow(`INSERT INTO delivery_service (name, email, photo, description,
-                              phone_number,manager_id, status) VALUES ($1, $2, $3, $4, $5,$6, $7) RETURNING id`,
+func (r *DeliveryServicePostgres) SaveDeliveryServiceInDB(ctx context.Context, service *DeliveryService) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	row := conn(ctx, r.db).QueryRowContext(ctx, `INSERT INTO delivery_service (name, email, photo, description,
+                              phone_number,manager_id, status, courier_rate) VALUES ($1, $2, $3, $4, $5,$6, $7, $8) RETURNING id`,
 		service.Name, service.Email, service.Photo, service.Description,
-		service.PhoneNumber, service.ManagerId, service.Status)
+		service.PhoneNumber, service.ManagerId, service.Status, service.CourierRate)
 	var id int
 	if err := row.Scan(&id); err != nil {
 		log.Println(fmt.Sprintf("Create Delivery : error:%s", err))
@@ -39,16 +50,30 @@ func (r *DeliveryServicePostgres) SaveDeliveryServiceInDB(service *DeliveryServi
 	return id, nil
 }
 
-func (r *DeliveryServicePostgres) GetDeliveryServiceByIdFromDB(Id int) (*DeliveryService, error) {
+// DeleteDeliveryServiceFromDB removes a delivery service that was just
+// created. It undoes SaveDeliveryServiceInDB.
+func (r *DeliveryServicePostgres) DeleteDeliveryServiceFromDB(ctx context.Context, id int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM delivery_service WHERE id = $1`, id); err != nil {
+		log.Println(err)
+		return fmt.Errorf("Delete Delivery Service: error:%s", err)
+	}
+	return nil
+}
+
+func (r *DeliveryServicePostgres) GetDeliveryServiceByIdFromDB(ctx context.Context, Id int) (*DeliveryService, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var service DeliveryService
-	res, err := r.db.Query("SELECT id, name,email,photo,description,phone_number,manager_id,status FROM delivery_service Where manager_id=$1", Id)
+	res, err := conn(ctx, r.db).QueryContext(ctx, "SELECT id, name,email,photo,photo_medium,photo_thumbnail,description,phone_number,manager_id,status,courier_rate,version FROM delivery_service Where manager_id=$1", Id)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 	for res.Next() {
-		err = res.Scan(&service.Id, &service.Name, &service.Email, &service.Photo, &service.Description,
-			&service.PhoneNumber, &service.ManagerId, &service.Status)
+		err = res.Scan(&service.Id, &service.Name, &service.Email, &service.Photo, &service.PhotoMedium, &service.PhotoThumbnail,
+			&service.Description, &service.PhoneNumber, &service.ManagerId, &service.Status, &service.CourierRate, &service.Version)
 		if err != nil {
 			log.Println(err)
 			return nil, err
@@ -58,9 +83,11 @@ func (r *DeliveryServicePostgres) GetDeliveryServiceByIdFromDB(Id int) (*Deliver
 }
 
 //SELECT count(*) FROM couriers AS co JOIN delivery_service AS d ON co.delivery_service_id=d.id WHERE d.id=2
-func (r *DeliveryServicePostgres) GetAllDeliveryServicesFromDB() ([]DeliveryService, error) {
+func (r *DeliveryServicePostgres) GetAllDeliveryServicesFromDB(ctx context.Context) ([]DeliveryService, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var services []DeliveryService
-	res, err := r.db.Query(`SELECT id, name, email, photo, description, phone_number, manager_id, status
+	res, err := conn(ctx, r.db).QueryContext(ctx, `SELECT id, name, email, photo, photo_medium, photo_thumbnail, description, phone_number, manager_id, status, courier_rate
                                   FROM delivery_service ORDER BY id`)
 	if err != nil {
 		log.Println(err)
@@ -68,8 +95,8 @@ func (r *DeliveryServicePostgres) GetAllDeliveryServicesFromDB() ([]DeliveryServ
 	}
 	for res.Next() {
 		var service DeliveryService
-		err = res.Scan(&service.Id, &service.Name, &service.Email, &service.Photo, &service.Description,
-			&service.PhoneNumber, &service.ManagerId, &service.Status)
+		err = res.Scan(&service.Id, &service.Name, &service.Email, &service.Photo, &service.PhotoMedium, &service.PhotoThumbnail,
+			&service.Description, &service.PhoneNumber, &service.ManagerId, &service.Status, &service.CourierRate)
 		if err != nil {
 			log.Println(err)
 			return nil, err
@@ -80,27 +107,33 @@ func (r *DeliveryServicePostgres) GetAllDeliveryServicesFromDB() ([]DeliveryServ
 	return services, nil
 }
 
-func (r *DeliveryServicePostgres) UpdateDeliveryServiceInDB(service DeliveryService) error {
+// UpdateDeliveryServiceInDB overwrites the fields of the delivery service
+// that are set. It returns sql.ErrNoRows for an unknown delivery service and
+// ErrVersionMismatch when service.Version isn't 0 and the delivery service
+// has another version.
+func (r *DeliveryServicePostgres) UpdateDeliveryServiceInDB(ctx context.Context, service DeliveryService) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var oldService DeliveryService
-	transaction, err := r.db.Begin()
-	if err != nil {
-		log.Println(err)
-	}
-	defer transaction.Commit()
-	res, err := transaction.Query(`SELECT id, name,email,photo,description,phone_number,manager_id,status 
-                                  FROM delivery_service Where id=$1`, service.Id)
+	transaction, err := beginTx(ctx, r.db)
 	if err != nil {
 		log.Println(err)
 		return err
 	}
-	for res.Next() {
-		err = res.Scan(&oldService.Id, &oldService.Name, &oldService.Email,
-			&oldService.Photo, &oldService.Description, &oldService.PhoneNumber,
-			&oldService.ManagerId, &oldService.Status)
-		if err != nil {
+	defer transaction.Rollback()
+	err = transaction.QueryRowContext(ctx, `SELECT id, name,email,photo,photo_medium,photo_thumbnail,description,phone_number,manager_id,status,courier_rate,version
+                                  FROM delivery_service Where id=$1 FOR UPDATE`, service.Id).
+		Scan(&oldService.Id, &oldService.Name, &oldService.Email,
+			&oldService.Photo, &oldService.PhotoMedium, &oldService.PhotoThumbnail, &oldService.Description, &oldService.PhoneNumber,
+			&oldService.ManagerId, &oldService.Status, &oldService.CourierRate, &oldService.Version)
+	if err != nil {
+		if err != sql.ErrNoRows {
 			log.Println(err)
-			return err
 		}
+		return err
+	}
+	if service.Version != 0 && service.Version != oldService.Version {
+		return ErrVersionMismatch
 	}
 	if service.Name == "" {
 		service.Name = oldService.Name
@@ -111,6 +144,12 @@ func (r *DeliveryServicePostgres) UpdateDeliveryServiceInDB(service DeliveryServ
 	if service.Photo == "" {
 		service.Photo = oldService.Photo
 	}
+	if service.PhotoMedium == "" {
+		service.PhotoMedium = oldService.PhotoMedium
+	}
+	if service.PhotoThumbnail == "" {
+		service.PhotoThumbnail = oldService.PhotoThumbnail
+	}
 	if service.Description == "" {
 		service.Description = oldService.Description
 	}
@@ -120,24 +159,89 @@ func (r *DeliveryServicePostgres) UpdateDeliveryServiceInDB(service DeliveryServ
 	if service.Status == "" {
 		service.Status = oldService.Status
 	}
+	if service.CourierRate == 0 {
+		service.CourierRate = oldService.CourierRate
+	}
 
 	s := `UPDATE delivery_service SET name = $1, email = $2, description = $3, 
-                            phone_number = $4, status = $5, photo=$6 WHERE id = $7`
-	log.Println(s)
-	insert, err := transaction.Query(s, service.Name, service.Email, service.Description,
-		service.PhoneNumber, service.Status, &service.Photo, service.Id)
-	defer insert.Close()
-	if err != nil {
+                            phone_number = $4, status = $5, photo=$6, photo_medium=$7, photo_thumbnail=$8, courier_rate=$9 WHERE id = $10`
+	if _, err := transaction.ExecContext(ctx, s, service.Name, service.Email, service.Description,
+		service.PhoneNumber, service.Status, &service.Photo, service.PhotoMedium, service.PhotoThumbnail, service.CourierRate, service.Id); err != nil {
 		log.Println(err)
 		return err
 	}
-	return nil
+	return transaction.Commit()
+}
+
+// DeliveryServicePatch is a merge patch of the fields of a delivery service
+// its manager edits.
+type DeliveryServicePatch struct {
+	Name        PatchString
+	Email       PatchString
+	Description PatchString
+	PhoneNumber PatchString
+	Status      PatchString
+	CourierRate PatchFloat
+	// Version is the version of the delivery service the patch was made
+	// against; 0 patches any version.
+	Version int
+}
+
+// PatchDeliveryServiceInDB applies patch to the delivery service in one
+// transaction that locks the row, and returns the service as patched. It
+// returns sql.ErrNoRows for an unknown delivery service and
+// ErrVersionMismatch for a patch made against another version.
+func (r *DeliveryServicePostgres) PatchDeliveryServiceInDB(ctx context.Context, id int, patch DeliveryServicePatch) (DeliveryService, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var service DeliveryService
+	transaction, err := beginTx(ctx, r.db)
+	if err != nil {
+		log.Println(err)
+		return service, err
+	}
+	defer transaction.Rollback()
+
+	if err := transaction.QueryRowContext(ctx, `SELECT version FROM delivery_service WHERE id = $1 FOR UPDATE`, id).Scan(&service.Version); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println(err)
+		}
+		return service, err
+	}
+	if patch.Version != 0 && patch.Version != service.Version {
+		return service, ErrVersionMismatch
+	}
+	var set patchSet
+	set.add("name", patch.Name.Set, patch.Name.Value)
+	set.add("email", patch.Email.Set, patch.Email.Value)
+	set.add("description", patch.Description.Set, patch.Description.Value)
+	set.add("phone_number", patch.PhoneNumber.Set, patch.PhoneNumber.Value)
+	set.add("status", patch.Status.Set, patch.Status.Value)
+	set.add("courier_rate", patch.CourierRate.Set, patch.CourierRate.Value)
+	if !set.empty() {
+		query, args := set.update("delivery_service", "id", id)
+		if _, err := transaction.ExecContext(ctx, query, args...); err != nil {
+			log.Println(err)
+			return service, fmt.Errorf("patchDeliveryService: %w", err)
+		}
+	}
+	err = transaction.QueryRowContext(ctx, `SELECT id, name, email, photo, photo_medium, photo_thumbnail, description, phone_number, manager_id, status, courier_rate, version
+                                  FROM delivery_service WHERE id = $1`, id).
+		Scan(&service.Id, &service.Name, &service.Email, &service.Photo, &service.PhotoMedium, &service.PhotoThumbnail,
+			&service.Description, &service.PhoneNumber, &service.ManagerId, &service.Status, &service.CourierRate, &service.Version)
+	if err != nil {
+		log.Println(err)
+		return service, fmt.Errorf("patchDeliveryService: %w", err)
+	}
+	return service, transaction.Commit()
 }
 
-func (r *DeliveryServicePostgres) GetNumberCouriersByServiceFromDB(id int) (int, error) {
+func (r *DeliveryServicePostgres) GetNumberCouriersByServiceFromDB(ctx context.Context, id int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 
 	selectValue := `SELECT count(*) FROM couriers AS co JOIN delivery_service AS d ON co.delivery_service_id=d.id WHERE d.id=$1`
-	get, err := r.db.Query(selectValue, id)
+	get, err := conn(ctx, r.db).QueryContext(ctx, selectValue, id)
 
 	if err != nil {
 		log.Println("Error of getting list of couriers :" + err.Error())
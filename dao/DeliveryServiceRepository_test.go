@@ -0,0 +1,31 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func TestRepository_UpdateDeliveryServiceInDB_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, (.+) FROM delivery_service Where id=\$1 FOR UPDATE`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "photo", "photo_medium", "photo_thumbnail", "description",
+			"phone_number", "manager_id", "status", "courier_rate", "version"}))
+	mock.ExpectRollback()
+
+	err = r.UpdateDeliveryServiceInDB(context.Background(), DeliveryService{Id: 1, Name: "name"})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
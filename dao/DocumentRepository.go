@@ -0,0 +1,286 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"github.com/lib/pq"
+	"log"
+	"time"
+)
+
+type DocumentPostgres struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+func NewDocumentPostgres(db *sql.DB, timeout time.Duration) *DocumentPostgres {
+	return &DocumentPostgres{db: db, timeout: timeout}
+}
+
+type CourierDocument struct {
+	Id         int    `json:"id"`
+	CourierId  int    `json:"courier_id"`
+	Type       string `json:"type"`
+	Number     string `json:"number"`
+	File       string `json:"file"`
+	IssueDate  Date   `json:"issue_date"`
+	ExpiryDate Date   `json:"expiry_date"`
+}
+
+type ExpiringDocument struct {
+	CourierDocument
+	CourierName       string `json:"courier_name"`
+	CourierSurname    string `json:"surname"`
+	DeliveryServiceId int    `json:"delivery_service_id"`
+}
+
+// DocumentWarning tells the managers of a delivery service that a document
+// of one of their couriers expires soon. It stays until one of them
+// acknowledges it.
+type DocumentWarning struct {
+	Id        int              `json:"id"`
+	CreatedAt time.Time        `json:"created_at"`
+	Document  ExpiringDocument `json:"document"`
+}
+
+const expiringDocumentColumns = `d.id, d.courier_id, d.type, d.number, d.file, d.issue_date, d.expiry_date,
+       co.name, co.surname, coalesce(co.delivery_service_id, 0)`
+
+func scanExpiringDocuments(res *sql.Rows) ([]ExpiringDocument, error) {
+	defer res.Close()
+	documents := []ExpiringDocument{}
+	for res.Next() {
+		var document ExpiringDocument
+		err := res.Scan(&document.Id, &document.CourierId, &document.Type, &document.Number, &document.File,
+			&document.IssueDate, &document.ExpiryDate, &document.CourierName, &document.CourierSurname, &document.DeliveryServiceId)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, res.Err()
+}
+
+// SaveCourierDocumentInDB creates the document or replaces the courier's
+// document of the same type. A new expiry date re-arms the expiry warning.
+func (r *DocumentPostgres) SaveCourierDocumentInDB(ctx context.Context, document *CourierDocument) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, `INSERT INTO courier_documents (courier_id, type, number, file, issue_date, expiry_date)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (courier_id, type) DO UPDATE SET number = EXCLUDED.number,
+    file = CASE WHEN EXCLUDED.file = '' THEN courier_documents.file ELSE EXCLUDED.file END,
+    issue_date = EXCLUDED.issue_date, expiry_date = EXCLUDED.expiry_date, updated_at = now(),
+    warned_at = CASE WHEN courier_documents.expiry_date = EXCLUDED.expiry_date THEN courier_documents.warned_at END
+RETURNING id`,
+		document.CourierId, document.Type, document.Number, document.File, document.IssueDate, document.ExpiryDate).Scan(&id)
+	if err != nil {
+		log.Println("Error of saving courier document in dao :" + err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *DocumentPostgres) GetCourierDocumentsFromDB(ctx context.Context, courierId int) ([]CourierDocument, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	res, err := conn(ctx, r.db).QueryContext(ctx, `SELECT id, courier_id, type, number, file, issue_date, expiry_date
+FROM courier_documents WHERE courier_id = $1 ORDER BY type`, courierId)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	defer res.Close()
+	documents := []CourierDocument{}
+	for res.Next() {
+		var document CourierDocument
+		err = res.Scan(&document.Id, &document.CourierId, &document.Type, &document.Number, &document.File,
+			&document.IssueDate, &document.ExpiryDate)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, res.Err()
+}
+
+// SetDocumentFileInDB keeps the storage key of the document's scan and
+// returns the courier and type of the document. It returns sql.ErrNoRows for
+// an unknown document.
+func (r *DocumentPostgres) SetDocumentFileInDB(ctx context.Context, id int, key string) (int, string, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var courierId int
+	var documentType string
+	err := conn(ctx, r.db).QueryRowContext(ctx, `UPDATE courier_documents SET file = $1, updated_at = now() WHERE id = $2
+RETURNING courier_id, type`, key, id).Scan(&courierId, &documentType)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println(err)
+	}
+	return courierId, documentType, err
+}
+
+// GetDocumentFileFromDB returns what SetDocumentFileInDB kept for the
+// courier's document of the type, "" when it has no scan. It returns
+// sql.ErrNoRows when the courier has no such document.
+func (r *DocumentPostgres) GetDocumentFileFromDB(ctx context.Context, courierId int, documentType string) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var key string
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT file FROM courier_documents WHERE courier_id = $1 AND type = $2`,
+		courierId, documentType).Scan(&key)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println(err)
+	}
+	return key, err
+}
+
+// GetCourierOfDocumentFromDB returns sql.ErrNoRows for an unknown document.
+func (r *DocumentPostgres) GetCourierOfDocumentFromDB(ctx context.Context, id int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var courierId int
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT courier_id FROM courier_documents WHERE id = $1`, id).Scan(&courierId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println(err)
+	}
+	return courierId, err
+}
+
+// GetExpiringDocumentsFromDB lists documents of the delivery service that
+// expire on or before the given day, already expired ones included.
+func (r *DocumentPostgres) GetExpiringDocumentsFromDB(ctx context.Context, idService int, before Date) ([]ExpiringDocument, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	res, err := conn(ctx, r.db).QueryContext(ctx, `SELECT `+expiringDocumentColumns+`
+FROM courier_documents AS d JOIN couriers AS co ON co.id_courier = d.courier_id
+WHERE co.delivery_service_id = $1 AND d.expiry_date <= $2 AND NOT co.deleted
+ORDER BY d.expiry_date, d.id`, idService, before)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return scanExpiringDocuments(res)
+}
+
+// ClaimDocumentsToWarnInDB marks the documents expiring on or before the
+// given day whose managers have not been warned yet, leaves a warning for the
+// courier's delivery service and returns them. The update claims the rows, so
+// two instances never warn twice; documents of couriers without a delivery
+// service stay unclaimed until there is someone to warn.
+func (r *DocumentPostgres) ClaimDocumentsToWarnInDB(ctx context.Context, before Date) ([]ExpiringDocument, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	res, err := conn(ctx, r.db).QueryContext(ctx, `WITH d AS (
+    UPDATE courier_documents SET warned_at = now()
+    WHERE warned_at IS NULL AND expiry_date <= $1 AND courier_id IN (
+        SELECT id_courier FROM couriers WHERE delivery_service_id IS NOT NULL AND NOT deleted)
+    RETURNING id, courier_id, type, number, file, issue_date, expiry_date),
+w AS (
+    INSERT INTO document_warnings (delivery_service_id, document_id, expiry_date)
+    SELECT co.delivery_service_id, d.id, d.expiry_date FROM d JOIN couriers AS co ON co.id_courier = d.courier_id)
+SELECT `+expiringDocumentColumns+`
+FROM d JOIN couriers AS co ON co.id_courier = d.courier_id
+ORDER BY d.expiry_date, d.id`, before)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return scanExpiringDocuments(res)
+}
+
+// GetDocumentWarningsFromDB lists the warnings of the delivery service no
+// manager has acknowledged yet. A warning whose document got a new expiry
+// date since is left out.
+func (r *DocumentPostgres) GetDocumentWarningsFromDB(ctx context.Context, idService int) ([]DocumentWarning, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	res, err := conn(ctx, r.db).QueryContext(ctx, `SELECT w.id, w.created_at, `+expiringDocumentColumns+`
+FROM document_warnings AS w
+JOIN courier_documents AS d ON d.id = w.document_id
+JOIN couriers AS co ON co.id_courier = d.courier_id
+WHERE w.delivery_service_id = $1 AND w.acknowledged_at IS NULL AND d.expiry_date = w.expiry_date
+ORDER BY d.expiry_date, w.id`, idService)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	defer res.Close()
+	warnings := []DocumentWarning{}
+	for res.Next() {
+		var warning DocumentWarning
+		document := &warning.Document
+		err := res.Scan(&warning.Id, &warning.CreatedAt, &document.Id, &document.CourierId, &document.Type, &document.Number, &document.File,
+			&document.IssueDate, &document.ExpiryDate, &document.CourierName, &document.CourierSurname, &document.DeliveryServiceId)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings, res.Err()
+}
+
+// AcknowledgeDocumentWarningInDB marks the warning of the delivery service
+// read. It returns sql.ErrNoRows for an unknown or already acknowledged one.
+func (r *DocumentPostgres) AcknowledgeDocumentWarningInDB(ctx context.Context, idService, id int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	res, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE document_warnings SET acknowledged_at = now()
+WHERE id = $1 AND delivery_service_id = $2 AND acknowledged_at IS NULL`, id, idService)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CountExpiredDocumentsOfCourierFromDB counts the courier's documents of the
+// given types that expired before today.
+func (r *DocumentPostgres) CountExpiredDocumentsOfCourierFromDB(ctx context.Context, courierId int, types []string, today Date) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var count int
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT count(*) FROM courier_documents
+WHERE courier_id = $1 AND type = ANY($2) AND expiry_date < $3`, courierId, pq.Array(types), today).Scan(&count)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// StopCouriersWithExpiredDocumentsInDB takes couriers with an expired
+// document of the given types off "ready to go" and returns their ids.
+func (r *DocumentPostgres) StopCouriersWithExpiredDocumentsInDB(ctx context.Context, types []string, today Date) ([]int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	res, err := conn(ctx, r.db).QueryContext(ctx, `UPDATE couriers SET "ready to go" = false
+WHERE "ready to go" AND id_courier IN (
+    SELECT courier_id FROM courier_documents WHERE type = ANY($1) AND expiry_date < $2)
+RETURNING id_courier`, pq.Array(types), today)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	defer res.Close()
+	ids := []int{}
+	for res.Next() {
+		var id int
+		if err := res.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, res.Err()
+}
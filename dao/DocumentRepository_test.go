@@ -0,0 +1,87 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func TestDate_JSON(t *testing.T) {
+	var document CourierDocument
+	err := json.Unmarshal([]byte(`{"number":"AB123","issue_date":"2020-03-01","expiry_date":"2030-02-28"}`), &document)
+	assert.NoError(t, err)
+	assert.Equal(t, NewDate(2030, 2, 28), document.ExpiryDate)
+
+	out, err := json.Marshal(document)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":0,"courier_id":0,"type":"","number":"AB123","file":"","issue_date":"2020-03-01","expiry_date":"2030-02-28"}`, string(out))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"expiry_date":"28.02.2030"}`), &document))
+}
+
+func TestRepository_CountExpiredDocumentsOfCourierFromDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	types := []string{"driving_licence", "id_card"}
+	mock.ExpectQuery(`SELECT count\(\*\) FROM courier_documents WHERE courier_id = \$1 AND type = ANY\(\$2\) AND expiry_date < \$3`).
+		WithArgs(5, pq.Array(types), "2022-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	count, err := r.CountExpiredDocumentsOfCourierFromDB(context.Background(), 5, types, NewDate(2022, 3, 1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_ClaimDocumentsToWarnInDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectQuery(`WITH d AS \(\s+UPDATE courier_documents SET warned_at = now\(\)(.+)delivery_service_id IS NOT NULL(.+)` +
+		`INSERT INTO document_warnings \(delivery_service_id, document_id, expiry_date\)(.+)FROM d JOIN couriers`).
+		WithArgs("2022-03-31").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "courier_id", "type", "number", "file", "issue_date", "expiry_date", "name", "surname", "delivery_service_id"}).
+			AddRow(2, 5, "driving_licence", "AB123", "", NewDate(2012, 3, 1).Time, NewDate(2022, 3, 20).Time, "Ivan", "Petrov", 1))
+
+	documents, err := r.ClaimDocumentsToWarnInDB(context.Background(), NewDate(2022, 3, 31))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ExpiringDocument{{
+		CourierDocument: CourierDocument{Id: 2, CourierId: 5, Type: "driving_licence", Number: "AB123",
+			IssueDate: NewDate(2012, 3, 1), ExpiryDate: NewDate(2022, 3, 20)},
+		CourierName: "Ivan", CourierSurname: "Petrov", DeliveryServiceId: 1,
+	}}, documents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_AcknowledgeDocumentWarningInDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	query := `UPDATE document_warnings SET acknowledged_at = now\(\)\s+WHERE id = \$1 AND delivery_service_id = \$2 AND acknowledged_at IS NULL`
+	mock.ExpectExec(query).WithArgs(4, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs(4, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, r.AcknowledgeDocumentWarningInDB(context.Background(), 1, 4))
+	assert.ErrorIs(t, r.AcknowledgeDocumentWarningInDB(context.Background(), 1, 4), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,7 +1,9 @@
 package dao
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"log"
@@ -10,11 +12,12 @@ import (
 )
 
 type OrderPostgres struct {
-	db *sql.DB
+	db      *sql.DB
+	timeout time.Duration
 }
 
-func NewDeliveryPostgres(db *sql.DB) *OrderPostgres {
-	return &OrderPostgres{db: db}
+func NewDeliveryPostgres(db *sql.DB, timeout time.Duration) *OrderPostgres {
+	return &OrderPostgres{db: db, timeout: timeout}
 }
 
 type Order struct {
@@ -27,6 +30,8 @@ type Order struct {
 	OrderDate         string    `json:"order_date"`
 	RestaurantAddress string    `json:"restaurant_address"`
 	Picked            bool      `json:"picked"`
+	// Version is sent as the ETag of the order.
+	Version int `json:"-"`
 }
 
 type DetailedOrder struct {
@@ -63,212 +68,256 @@ type AllInfoAboutOrder struct {
 	CustomerName          string    `json:"customer_name"`
 	CustomerPhone         string    `json:"customer_phone"`
 	PaymentType           int       `json:"payment_type"`
+	ProofOfDelivery       string    `json:"proof_of_delivery,omitempty"`
+	OrderLoad
+	// Version is sent as the ETag of the order.
+	Version int `json:"-"`
 }
 
-func (r *OrderPostgres) GetActiveOrdersFromDB(id int) ([]Order, error) {
+// OrderLoad is what dispatch knows about the size of an order.
+type OrderLoad struct {
+	WeightKg   float64 `json:"weight_kg,omitempty"`
+	Size       string  `json:"size,omitempty"`
+	DistanceKm float64 `json:"distance_km,omitempty"`
+}
+
+// Earnings of a courier for the deliveries completed in a period, paid at
+// the rate of their delivery service.
+type Earnings struct {
+	Month      int     `json:"month"`
+	Year       int     `json:"year"`
+	Deliveries int     `json:"deliveries"`
+	Rate       float64 `json:"rate"`
+	Amount     float64 `json:"amount"`
+}
+
+func (r *OrderPostgres) GetActiveOrdersFromDB(ctx context.Context, id int) ([]Order, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var Orders []Order
 
 	insertValue := `Select delivery_service_id,id,courier_id,delivery_time,customer_address,status,order_date,restaurant_address,picked from delivery where courier_id = $1 and status = 'ready to delivery'`
-	get, err := r.db.Query(insertValue, id)
+	get, err := conn(ctx, r.db).QueryContext(ctx, insertValue, id)
 	if err != nil {
 		log.Println("Error with getting list of orders: " + err.Error())
 		return nil, err
 	}
+	defer get.Close()
 
 	for get.Next() {
 		var order Order
 		err = get.Scan(&order.IdDeliveryService, &order.Id, &order.IdCourier, &order.DeliveryTime, &order.CustomerAddress, &order.Status, &order.OrderDate, &order.RestaurantAddress, &order.Picked)
+		if err != nil {
+			log.Println("Error with getting list of orders: " + err.Error())
+			return nil, err
+		}
 		Orders = append(Orders, order)
 	}
+	if err := get.Err(); err != nil {
+		log.Println("Error with getting list of orders: " + err.Error())
+		return nil, err
+	}
 	return Orders, nil
 }
 
-func (r *OrderPostgres) GetActiveOrderFromDB(id int) (Order, error) {
-	var Ord Order
+// GetActiveOrderFromDB returns sql.ErrNoRows for an unknown order.
+func (r *OrderPostgres) GetActiveOrderFromDB(ctx context.Context, id int) (Order, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var order Order
 
-	insertValue := `Select delivery_service_id,id,courier_id,delivery_time,customer_address,status,order_date,restaurant_address,picked from delivery where id = $1`
-	get, err := r.db.Query(insertValue, id)
+	insertValue := `Select delivery_service_id,id,courier_id,delivery_time,customer_address,status,order_date,restaurant_address,picked,version from delivery where id = $1`
+	err := conn(ctx, r.db).QueryRowContext(ctx, insertValue, id).
+		Scan(&order.IdDeliveryService, &order.Id, &order.IdCourier, &order.DeliveryTime, &order.CustomerAddress, &order.Status, &order.OrderDate, &order.RestaurantAddress, &order.Picked, &order.Version)
 	if err != nil {
-		log.Println("Error with getting order by id: " + err.Error())
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error with getting order by id: " + err.Error())
+		}
 		return Order{}, err
 	}
-
-	for get.Next() {
-		var order Order
-		err = get.Scan(&order.IdDeliveryService, &order.Id, &order.IdCourier, &order.DeliveryTime, &order.CustomerAddress, &order.Status, &order.OrderDate, &order.RestaurantAddress, &order.Picked)
-		Ord = order
-	}
-	return Ord, nil
+	return order, nil
 }
-func (r *OrderPostgres) GetOrderFromDB(id int) (Order, error) {
-	var Ord Order
 
-	insertValue := `Select delivery_service_id,id,courier_id,delivery_time,customer_address,status,order_date,restaurant_address,picked from delivery where id = $1`
-	get, err := r.db.Query(insertValue, id)
+// GetOrderFromDB returns sql.ErrNoRows for an unknown order.
+func (r *OrderPostgres) GetOrderFromDB(ctx context.Context, id int) (Order, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var order Order
+
+	insertValue := `Select delivery_service_id,id,courier_id,delivery_time,customer_address,status,order_date,restaurant_address,picked,version from delivery where id = $1`
+	err := conn(ctx, r.db).QueryRowContext(ctx, insertValue, id).
+		Scan(&order.IdDeliveryService, &order.Id, &order.IdCourier, &order.DeliveryTime, &order.CustomerAddress, &order.Status, &order.OrderDate, &order.RestaurantAddress, &order.Picked, &order.Version)
 	if err != nil {
-		log.Println("Error with getting order by id: " + err.Error())
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error with getting order by id: " + err.Error())
+		}
 		return Order{}, err
 	}
-
-	for get.Next() {
-		var order Order
-		err = get.Scan(&order.IdDeliveryService, &order.Id, &order.IdCourier, &order.DeliveryTime, &order.CustomerAddress, &order.Status, &order.OrderDate, &order.RestaurantAddress, &order.Picked)
-		Ord = order
-	}
-	return Ord, nil
+	return order, nil
 }
 
-func (r *OrderPostgres) ChangeOrderStatusInDB(text string, id uint16) (uint16, error) {
+// ChangeOrderStatusInDB returns ErrVersionMismatch when version isn't 0 and
+// the order has another version.
+func (r *OrderPostgres) ChangeOrderStatusInDB(ctx context.Context, text string, id uint16, version int) (uint16, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 
-	UpdateValue := `UPDATE "delivery" SET "status" = $1 WHERE "id" = $2`
-	_, err := r.db.Exec(UpdateValue, text, id)
+	UpdateValue := `UPDATE "delivery" SET "status" = $1 WHERE "id" = $2 AND ($3 = 0 OR "version" = $3)`
+	res, err := conn(ctx, r.db).ExecContext(ctx, UpdateValue, text, id, version)
 	if err != nil {
 		log.Println("Error with getting order by id: " + err.Error())
 		return 0, fmt.Errorf("updateOrder: error while scanning for order:%w", err)
 	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 && version != 0 {
+		return 0, ErrVersionMismatch
+	}
 	return id, nil
 }
 
-func (r *OrderPostgres) GetCourierCompletedOrdersWithPage_fromDB(limit, page, idCourier int) ([]DetailedOrder, int) {
+func (r *OrderPostgres) GetCourierCompletedOrdersWithPage_fromDB(ctx context.Context, limit, page, idCourier int) ([]DetailedOrder, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var Orders []DetailedOrder
-	transaction, err := r.db.Begin()
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer transaction.Commit()
-	res, err := transaction.Query(fmt.Sprintf("SELECT delivery.order_date, delivery.courier_id,delivery.id,delivery.delivery_service_id,delivery.delivery_time,delivery.status,delivery.customer_address,delivery.restaurant_address,couriers.name,couriers.phone_number FROM delivery JOIN couriers ON couriers.id_courier=delivery.courier_id Where delivery.status='completed' and delivery.courier_id=%d LIMIT %d OFFSET %d", idCourier, limit, limit*(page-1)))
+	db := conn(ctx, r.db)
+	res, err := db.QueryContext(ctx, "SELECT delivery.order_date, delivery.courier_id,delivery.id,delivery.delivery_service_id,delivery.delivery_time,delivery.status,delivery.customer_address,delivery.restaurant_address,couriers.name,couriers.phone_number FROM delivery JOIN couriers ON couriers.id_courier=delivery.courier_id Where delivery.status='completed' and delivery.courier_id=$1 ORDER BY delivery.id LIMIT $2 OFFSET $3",
+		idCourier, limit, limit*(page-1))
 	if err != nil {
-		log.Fatal(err)
+		log.Println(err)
+		return nil, 0, err
 	}
+	defer res.Close()
 	for res.Next() {
 		var order DetailedOrder
 		err = res.Scan(&order.OrderDate, &order.IdCourier, &order.IdOrder, &order.IdDeliveryService, &order.DeliveryTime, &order.Status, &order.CustomerAddress, &order.RestaurantAddress, &order.CourierName, &order.CourierPhoneNumber)
 		if err != nil {
-			panic(err)
+			log.Println(err)
+			return nil, 0, err
 		}
 		Orders = append(Orders, order)
 	}
-
-	var Ordersss []Order
-	resl, err := transaction.Query(fmt.Sprintf("SELECT courier_id FROM delivery WHERE status='completed' and courier_id=%d ", idCourier))
-	if err != nil {
+	if err := res.Err(); err != nil {
 		log.Println(err)
+		return nil, 0, err
 	}
-	for resl.Next() {
-		var order1 Order
-		err = resl.Scan(&order1.IdCourier)
-		if err != nil {
-			panic(err)
-		}
 
-		Ordersss = append(Ordersss, order1)
+	var length int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM delivery WHERE status='completed' and courier_id=$1", idCourier).Scan(&length); err != nil {
+		log.Println(err)
+		return nil, 0, err
 	}
-	return Orders, len(Ordersss)
+	return Orders, length, nil
 }
 
-func (r *OrderPostgres) GetAllOrdersOfCourierServiceWithPageFromDB(limit, page, idService int) ([]DetailedOrder, int) {
+func (r *OrderPostgres) GetAllOrdersOfCourierServiceWithPageFromDB(ctx context.Context, limit, page, idService int) ([]DetailedOrder, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var Orders []DetailedOrder
-	transaction, err := r.db.Begin()
-	if err != nil {
-		log.Println(err)
-	}
-	defer transaction.Commit()
-	res, err := transaction.Query("SELECT d.id_from_restaurant, d.order_date, d.courier_id,d.id,d.delivery_service_id,d.delivery_time,d.status,d.customer_address,d.restaurant_address,co.name, co.surname,co.phone_number FROM delivery AS d JOIN couriers AS co ON co.id_courier=d.courier_id Where d.delivery_service_id=$1 and status = 'ready to delivery' ORDER BY d.id LIMIT $2 OFFSET $3", idService, limit, limit*(page-1))
+	db := conn(ctx, r.db)
+	res, err := db.QueryContext(ctx, "SELECT d.id_from_restaurant, d.order_date, d.courier_id,d.id,d.delivery_service_id,d.delivery_time,d.status,d.customer_address,d.restaurant_address,co.name, co.surname,co.phone_number FROM delivery AS d JOIN couriers AS co ON co.id_courier=d.courier_id Where d.delivery_service_id=$1 and status = 'ready to delivery' ORDER BY d.id LIMIT $2 OFFSET $3",
+		idService, limit, limit*(page-1))
 	if err != nil {
 		log.Println(err)
+		return nil, 0, err
 	}
+	defer res.Close()
 	for res.Next() {
 		var order DetailedOrder
 		err = res.Scan(&order.OrderIdFromRestaurant, &order.OrderDate, &order.IdCourier, &order.IdOrder, &order.IdDeliveryService, &order.DeliveryTime, &order.Status, &order.CustomerAddress, &order.RestaurantAddress, &order.CourierName, &order.CourierSurname, &order.CourierPhoneNumber)
 		if err != nil {
 			log.Println(err)
+			return nil, 0, err
 		}
-
 		Orders = append(Orders, order)
 	}
+	if err := res.Err(); err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
 
 	var length int
-	resl, err := transaction.Query("SELECT count(*) FROM delivery WHERE delivery_service_id=$1", idService)
-	if err != nil {
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM delivery AS d JOIN couriers AS co ON co.id_courier=d.courier_id WHERE d.delivery_service_id=$1 and status = 'ready to delivery'", idService).Scan(&length); err != nil {
 		log.Println(err)
+		return nil, 0, err
 	}
-	for resl.Next() {
-		err = resl.Scan(&length)
-		if err != nil {
-			log.Println(err)
-		}
-	}
-	return Orders, length
+	return Orders, length, nil
 }
 
-func (r *OrderPostgres) GetCourierCompletedOrdersByMouthWithPageFromDB(limit, page, idCourier, Month, Year int) ([]Order, int) {
+func (r *OrderPostgres) GetCourierCompletedOrdersByMouthWithPageFromDB(ctx context.Context, limit, page, idCourier, Month, Year int) ([]Order, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var Orders []Order
-	transaction, err := r.db.Begin()
-	if err != nil {
-		log.Fatal(err)
-	}
-	log.Println("connected to db")
-
-	defer transaction.Commit()
-	res, err := transaction.Query("SELECT courier_id ,id ,delivery_service_id ,delivery_time ,order_date ,status ,customer_address, restaurant_address FROM delivery where status='completed' and courier_id=$1 and Extract(MONTH from order_date )=$2 and Extract(Year from order_date )=$3 LIMIT $4 OFFSET $5", idCourier, Month, Year, limit, limit*(page-1))
+	db := conn(ctx, r.db)
+	res, err := db.QueryContext(ctx, "SELECT courier_id ,id ,delivery_service_id ,delivery_time ,order_date ,status ,customer_address, restaurant_address FROM delivery where status='completed' and courier_id=$1 and Extract(MONTH from order_date )=$2 and Extract(Year from order_date )=$3 ORDER BY id LIMIT $4 OFFSET $5",
+		idCourier, Month, Year, limit, limit*(page-1))
 	if err != nil {
-		panic(err)
+		log.Println(err)
+		return nil, 0, err
 	}
+	defer res.Close()
 	for res.Next() {
 		var order Order
 		err = res.Scan(&order.IdCourier, &order.Id, &order.IdDeliveryService, &order.DeliveryTime, &order.OrderDate, &order.Status, &order.CustomerAddress, &order.RestaurantAddress)
 		if err != nil {
-			panic(err)
+			log.Println(err)
+			return nil, 0, err
 		}
-
 		Orders = append(Orders, order)
 	}
-	var Ordersss []Order
-	resl, err := transaction.Query(fmt.Sprintf("SELECT courier_id FROM delivery WHERE courier_id=%d and Extract(MONTH from order_date )=%d", idCourier, Month))
-	if err != nil {
-		panic(err)
+	if err := res.Err(); err != nil {
+		log.Println(err)
+		return nil, 0, err
 	}
-	for resl.Next() {
-		var order Order
-		err = resl.Scan(&order.IdCourier)
-		if err != nil {
-			panic(err)
-		}
 
-		Ordersss = append(Ordersss, order)
+	var length int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM delivery WHERE status='completed' and courier_id=$1 and Extract(MONTH from order_date )=$2 and Extract(Year from order_date )=$3", idCourier, Month, Year).Scan(&length); err != nil {
+		log.Println(err)
+		return nil, 0, err
 	}
-
-	return Orders, len(Ordersss)
+	return Orders, length, nil
 }
 
-func (r *OrderPostgres) AssigningOrderToCourierInDB(order Order) error {
-	log.Println("connected to db")
-	s := "UPDATE delivery SET courier_id = $1 WHERE id = $2"
-	log.Println(s)
-	insert, err := r.db.Query(s, order.IdCourier, order.Id)
-	defer insert.Close()
+// AssigningOrderToCourierInDB returns sql.ErrNoRows for an unknown order and
+// ErrVersionMismatch when order.Version isn't 0 and the order has another
+// version.
+func (r *OrderPostgres) AssigningOrderToCourierInDB(ctx context.Context, order Order) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	transaction, err := beginTx(ctx, r.db)
 	if err != nil {
 		log.Println(err)
 		return err
 	}
-	return nil
-}
+	defer transaction.Rollback()
 
-func (r *OrderPostgres) GetDetailedOrderByIdFromDB(Id int) (*AllInfoAboutOrder, error) {
-	var order AllInfoAboutOrder
-	transaction, err := r.db.Begin()
-	if err != nil {
+	var version int
+	if err := transaction.QueryRowContext(ctx, `SELECT version FROM delivery WHERE id = $1 FOR UPDATE`, order.Id).Scan(&version); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println(err)
+		}
+		return err
+	}
+	if order.Version != 0 && order.Version != version {
+		return ErrVersionMismatch
+	}
+	if _, err := transaction.ExecContext(ctx, `UPDATE delivery SET courier_id = $1 WHERE id = $2`, order.IdCourier, order.Id); err != nil {
 		log.Println(err)
-		return nil, err
+		return err
 	}
-	defer transaction.Commit()
-	res, err := transaction.Query(fmt.Sprintf("SELECT d.payment_type,d.customer_name,d.customer_phone,d.id_from_restaurant,d.id, d.order_date, d.courier_id,d.id,d.delivery_service_id,d.delivery_time,d.status,d.customer_address,d.restaurant_name,d.restaurant_address,co.name,co.surname,co.phone_number FROM delivery AS d JOIN couriers AS co ON co.id_courier=d.courier_id Where d.id=%d", Id))
+	return transaction.Commit()
+}
+
+func (r *OrderPostgres) GetDetailedOrderByIdFromDB(ctx context.Context, Id int) (*AllInfoAboutOrder, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var order AllInfoAboutOrder
+	db := conn(ctx, r.db)
+	res, err := db.QueryContext(ctx, fmt.Sprintf("SELECT d.payment_type,d.customer_name,d.customer_phone,d.id_from_restaurant,d.id, d.order_date, d.courier_id,d.id,d.delivery_service_id,d.delivery_time,d.status,d.customer_address,d.restaurant_name,d.restaurant_address,co.name,co.surname,co.phone_number,d.proof_of_delivery,d.weight_kg,d.size,d.distance_km,d.version FROM delivery AS d JOIN couriers AS co ON co.id_courier=d.courier_id Where d.id=%d", Id))
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 	for res.Next() {
-		err = res.Scan(&order.PaymentType, &order.CustomerName, &order.CustomerPhone, &order.OrderIdFromRestaurant, &order.IdOrder, &order.OrderDate, &order.IdCourier, &order.IdOrder, &order.IdDeliveryService, &order.DeliveryTime, &order.Status, &order.CustomerAddress, &order.RestaurantName, &order.RestaurantAddress, &order.CourierName, &order.CourierSurname, &order.CourierPhoneNumber)
+		err = res.Scan(&order.PaymentType, &order.CustomerName, &order.CustomerPhone, &order.OrderIdFromRestaurant, &order.IdOrder, &order.OrderDate, &order.IdCourier, &order.IdOrder, &order.IdDeliveryService, &order.DeliveryTime, &order.Status, &order.CustomerAddress, &order.RestaurantName, &order.RestaurantAddress, &order.CourierName, &order.CourierSurname, &order.CourierPhoneNumber, &order.ProofOfDelivery, &order.WeightKg, &order.Size, &order.DistanceKm, &order.Version)
 		if err != nil {
 			log.Println(err)
 			return nil, err
@@ -277,21 +326,103 @@ func (r *OrderPostgres) GetDetailedOrderByIdFromDB(Id int) (*AllInfoAboutOrder,
 	return &order, nil
 }
 
-func (r *OrderPostgres) CreateOrder(order *courierProto.OrderCourierServer) (*emptypb.Empty, error) {
+// SetProofOfDeliveryInDB keeps the storage key of the order's proof of
+// delivery.
+func (r *OrderPostgres) SetProofOfDeliveryInDB(ctx context.Context, id int, key string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE delivery SET proof_of_delivery = $1 WHERE id = $2`, key, id); err != nil {
+		log.Println(err)
+		return fmt.Errorf("SetProofOfDelivery: %w", err)
+	}
+	return nil
+}
+
+// GetProofOfDeliveryFromDB returns what SetProofOfDeliveryInDB kept for the
+// order, "" when it has none. It returns sql.ErrNoRows for an unknown order.
+func (r *OrderPostgres) GetProofOfDeliveryFromDB(ctx context.Context, id int) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var key string
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT coalesce(proof_of_delivery, '') FROM delivery WHERE id = $1`, id).Scan(&key)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println(err)
+	}
+	return key, err
+}
+
+// GetOrderOwnerFromDB returns the delivery service of the order and its
+// courier, 0 when unassigned. It returns sql.ErrNoRows for an unknown order.
+func (r *OrderPostgres) GetOrderOwnerFromDB(ctx context.Context, id int) (int, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var idService int
+	var idCourier sql.NullInt64
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT delivery_service_id, courier_id FROM delivery WHERE id = $1`, id).Scan(&idService, &idCourier)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println(err)
+		}
+		return 0, 0, err
+	}
+	return idService, int(idCourier.Int64), nil
+}
+
+// GetEarningsOfCourierFromDB counts the deliveries the courier completed
+// from the start of the month and takes the rate of their delivery service.
+// It returns sql.ErrNoRows for an unknown courier.
+func (r *OrderPostgres) GetEarningsOfCourierFromDB(ctx context.Context, idCourier, month, year int) (Earnings, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	earnings := Earnings{Month: month, Year: year}
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT count(d.id), coalesce(ds.courier_rate, 0) FROM couriers c
+			LEFT JOIN delivery_service ds ON ds.id = c.delivery_service_id
+			LEFT JOIN delivery d ON d.courier_id = c.id_courier AND d.status = 'completed' AND d.order_date >= $2 AND d.order_date < $3
+			WHERE c.id_courier = $1 GROUP BY ds.courier_rate`, idCourier, from, from.AddDate(0, 1, 0)).
+		Scan(&earnings.Deliveries, &earnings.Rate)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println(err)
+		}
+		return Earnings{}, err
+	}
+	earnings.Amount = float64(earnings.Deliveries) * earnings.Rate
+	return earnings, nil
+}
+
+func (r *OrderPostgres) GetOrderLoadFromDB(ctx context.Context, id int) (OrderLoad, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var load OrderLoad
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT weight_kg, size, distance_km FROM delivery WHERE id = $1`, id).
+		Scan(&load.WeightKg, &load.Size, &load.DistanceKm)
+	if err != nil {
+		log.Println(err)
+		return OrderLoad{}, err
+	}
+	return load, nil
+}
+
+func (r *OrderPostgres) CreateOrder(ctx context.Context, order *courierProto.OrderCourierServer) (*emptypb.Empty, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	timestamp1 := time.Now()
 	timestamp2 := time.Now().Add(45 * time.Minute)
-	_, err := r.db.Exec("INSERT INTO delivery (delivery_service_id, customer_address, order_date, restaurant_address, delivery_time, restaurant_name, id_from_restaurant,customer_name,payment_type,customer_phone) VALUES ($1, $2, $3, $4, $5, $6, $7,$8,$9,$10)", order.CourierServiceID, order.ClientAddress, timestamp1, order.RestaurantAddress, timestamp2, order.RestaurantName, order.OrderID, order.ClientFullName, order.PaymentType, order.ClientPhoneNumber)
+	_, err := conn(ctx, r.db).ExecContext(ctx, "INSERT INTO delivery (delivery_service_id, customer_address, order_date, restaurant_address, delivery_time, restaurant_name, id_from_restaurant,customer_name,payment_type,customer_phone,weight_kg,size,distance_km) VALUES ($1, $2, $3, $4, $5, $6, $7,$8,$9,$10,$11,$12,$13)", order.CourierServiceID, order.ClientAddress, timestamp1, order.RestaurantAddress, timestamp2, order.RestaurantName, order.OrderID, order.ClientFullName, order.PaymentType, order.ClientPhoneNumber, order.WeightKg, order.Size, order.DistanceKm)
 	if err != nil {
-		log.Fatalf("CreateOrder:%s", err)
-		return &emptypb.Empty{}, fmt.Errorf("CreateOrder:%w", err)
+		log.Printf("CreateOrder:%s", err)
+		return nil, fmt.Errorf("CreateOrder:%w", err)
 	}
 	return &emptypb.Empty{}, nil
 }
 
-func (r *OrderPostgres) GetServices(in *emptypb.Empty) (*courierProto.ServicesResponse, error) {
+func (r *OrderPostgres) GetServices(ctx context.Context, in *emptypb.Empty) (*courierProto.ServicesResponse, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var Services courierProto.ServicesResponse
 
-	res, err := r.db.Query("SELECT id, name, email, photo, description, phone_number, manager_id, status FROM delivery_service")
+	res, err := conn(ctx, r.db).QueryContext(ctx, "SELECT id, name, email, photo, description, phone_number, manager_id, status FROM delivery_service")
 	if err != nil {
 		log.Println("Error with getting list of orders: " + err.Error())
 		return nil, err
@@ -308,151 +439,284 @@ func (r *OrderPostgres) GetServices(in *emptypb.Empty) (*courierProto.ServicesRe
 	return &Services, nil
 }
 
-func (r *OrderPostgres) GetCompletedOrdersOfCourierServiceFromDB(limit, page, idService int) ([]Order, int) {
+func (r *OrderPostgres) GetCompletedOrdersOfCourierServiceFromDB(ctx context.Context, limit, page, idService int) ([]Order, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
 	var Orders []Order
-	transaction, err := r.db.Begin()
-	if err != nil {
-		log.Println(err)
-	}
-	defer transaction.Commit()
-
-	res, err := transaction.Query("SELECT order_date,courier_id,id,delivery_time,status,customer_address FROM delivery WHERE status='completed' and delivery_service_id=$1 ORDER BY id LIMIT $2 OFFSET $3",
+	db := conn(ctx, r.db)
+	res, err := db.QueryContext(ctx, "SELECT order_date,courier_id,id,delivery_time,status,customer_address FROM delivery WHERE status='completed' and delivery_service_id=$1 ORDER BY id LIMIT $2 OFFSET $3",
 		idService, limit, limit*(page-1))
 	if err != nil {
 		log.Println(err)
+		return nil, 0, err
 	}
+	defer res.Close()
 	for res.Next() {
 		var order Order
 		err = res.Scan(&order.OrderDate, &order.IdCourier, &order.Id, &order.DeliveryTime, &order.Status, &order.CustomerAddress)
 		if err != nil {
 			log.Println(err)
+			return nil, 0, err
 		}
-
 		Orders = append(Orders, order)
 	}
-
-	resl, err := transaction.Query("SELECT count(*) FROM delivery WHERE status='completed' and delivery_service_id=$1", idService)
-	if err != nil {
+	if err := res.Err(); err != nil {
 		log.Println(err)
+		return nil, 0, err
 	}
+
 	var length int
-	for resl.Next() {
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM delivery WHERE status='completed' and delivery_service_id=$1", idService).Scan(&length); err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+	return Orders, length, nil
+}
 
-		err = resl.Scan(&length)
+func (r *OrderPostgres) GetCompletedOrdersOfCourierServiceByDateFromDB(ctx context.Context, limit, page, idService int) ([]Order, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var Orders []Order
+	db := conn(ctx, r.db)
+	res, err := db.QueryContext(ctx, "SELECT delivery_service_id, order_date, courier_id,id,delivery_time,status,customer_address FROM delivery WHERE status='completed' and delivery_service_id=$1 ORDER BY order_date LIMIT $2 OFFSET $3",
+		idService, limit, limit*(page-1))
+	if err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+	defer res.Close()
+	for res.Next() {
+		var order Order
+		err = res.Scan(&order.IdDeliveryService, &order.OrderDate, &order.IdCourier, &order.Id, &order.DeliveryTime, &order.Status, &order.CustomerAddress)
 		if err != nil {
 			log.Println(err)
+			return nil, 0, err
 		}
+		Orders = append(Orders, order)
+	}
+	if err := res.Err(); err != nil {
+		log.Println(err)
+		return nil, 0, err
 	}
-	return Orders, length
-}
 
-func (r *OrderPostgres) GetCompletedOrdersOfCourierServiceByDateFromDB(limit, page, idService int) ([]Order, int) {
-	var Orders []Order
-	transaction, err := r.db.Begin()
-	if err != nil {
+	var length int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM delivery WHERE status='completed' and delivery_service_id=$1", idService).Scan(&length); err != nil {
 		log.Println(err)
+		return nil, 0, err
 	}
-	defer transaction.Commit()
+	return Orders, length, nil
+}
 
-	res, err := transaction.Query("SELECT delivery_service_id, order_date, courier_id,id,delivery_time,status,customer_address FROM delivery WHERE status='completed' and delivery_service_id=$1 ORDER BY order_date LIMIT $2 OFFSET $3",
+func (r *OrderPostgres) GetCompletedOrdersOfCourierServiceByCourierIdFromDB(ctx context.Context, limit, page, idService int) ([]Order, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var Orders []Order
+	db := conn(ctx, r.db)
+	res, err := db.QueryContext(ctx, "SELECT delivery_service_id, order_date, courier_id,id,delivery_time,status,customer_address FROM delivery WHERE status='completed' and delivery_service_id=$1 ORDER BY courier_id LIMIT $2 OFFSET $3",
 		idService, limit, limit*(page-1))
 	if err != nil {
 		log.Println(err)
+		return nil, 0, err
 	}
+	defer res.Close()
 	for res.Next() {
 		var order Order
 		err = res.Scan(&order.IdDeliveryService, &order.OrderDate, &order.IdCourier, &order.Id, &order.DeliveryTime, &order.Status, &order.CustomerAddress)
 		if err != nil {
 			log.Println(err)
+			return nil, 0, err
 		}
-
 		Orders = append(Orders, order)
 	}
+	if err := res.Err(); err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
 
-	resl, err := transaction.Query("SELECT count(*) FROM delivery WHERE status='completed' and delivery_service_id=$1", idService)
+	var length int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM delivery WHERE status='completed' and delivery_service_id=$1", idService).Scan(&length); err != nil {
+		log.Println(err)
+		return nil, 0, err
+	}
+	return Orders, length, nil
+}
+
+func (r *OrderPostgres) GetOrdersOfCourierServiceForManagerFromDB(ctx context.Context, limit, page, idService int) ([]DetailedOrder, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var Orders []DetailedOrder
+	db := conn(ctx, r.db)
+	res, err := db.QueryContext(ctx, "SELECT d.order_date, d.courier_id,d.id,d.delivery_service_id,d.delivery_time,d.status,d.customer_address,d.restaurant_address,co.name, co.surname,co.phone_number FROM delivery AS d JOIN couriers AS co ON co.id_courier=d.courier_id Where d.delivery_service_id=$1 and status != 'completed' ORDER BY d.id LIMIT $2 OFFSET $3",
+		idService, limit, limit*(page-1))
 	if err != nil {
 		log.Println(err)
+		return nil, 0, err
 	}
-	var length int
-	for resl.Next() {
-		err = resl.Scan(&length)
+	defer res.Close()
+	for res.Next() {
+		var order DetailedOrder
+		err = res.Scan(&order.OrderDate, &order.IdCourier, &order.IdOrder, &order.IdDeliveryService, &order.DeliveryTime,
+			&order.Status, &order.CustomerAddress, &order.RestaurantAddress, &order.CourierName, &order.CourierSurname,
+			&order.CourierPhoneNumber)
 		if err != nil {
 			log.Println(err)
+			return nil, 0, err
 		}
+		Orders = append(Orders, order)
+	}
+	if err := res.Err(); err != nil {
+		log.Println(err)
+		return nil, 0, err
 	}
-	return Orders, length
-}
 
-func (r *OrderPostgres) GetCompletedOrdersOfCourierServiceByCourierIdFromDB(limit, page, idService int) ([]Order, int) {
-	var Orders []Order
-	transaction, err := r.db.Begin()
-	if err != nil {
+	var length int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM delivery AS d JOIN couriers AS co ON co.id_courier=d.courier_id WHERE d.delivery_service_id=$1 and status != 'completed'", idService).Scan(&length); err != nil {
 		log.Println(err)
+		return nil, 0, err
 	}
-	defer transaction.Commit()
+	return Orders, length, nil
+}
 
-	res, err := transaction.Query("SELECT delivery_service_id, order_date, courier_id,id,delivery_time,status,customer_address FROM delivery WHERE status='completed' and delivery_service_id=$1 ORDER BY courier_id LIMIT $2 OFFSET $3",
-		idService, limit, limit*(page-1))
+func (r *OrderPostgres) GetCourierCompletedOrdersAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idCourier int) ([]DetailedOrder, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var Orders []DetailedOrder
+	query, args := keyset("SELECT delivery.order_date, delivery.courier_id,delivery.id,delivery.delivery_service_id,delivery.delivery_time,delivery.status,delivery.customer_address,delivery.restaurant_address,couriers.name,couriers.phone_number FROM delivery JOIN couriers ON couriers.id_courier=delivery.courier_id Where delivery.status='completed' and delivery.courier_id=$1",
+		[]interface{}{idCourier}, []string{"delivery.id"}, afterId(after), limit)
+	res, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Println(err)
+		return nil, err
 	}
+	defer res.Close()
 	for res.Next() {
-		var order Order
-		err = res.Scan(&order.IdDeliveryService, &order.OrderDate, &order.IdCourier, &order.Id, &order.DeliveryTime, &order.Status, &order.CustomerAddress)
+		var order DetailedOrder
+		err = res.Scan(&order.OrderDate, &order.IdCourier, &order.IdOrder, &order.IdDeliveryService, &order.DeliveryTime, &order.Status, &order.CustomerAddress, &order.RestaurantAddress, &order.CourierName, &order.CourierPhoneNumber)
 		if err != nil {
 			log.Println(err)
+			return nil, err
 		}
-
 		Orders = append(Orders, order)
 	}
-	reslen, err := transaction.Query("SELECT count(*) FROM delivery WHERE status='completed' and delivery_service_id=$1", idService)
+	return Orders, nil
+}
+
+func (r *OrderPostgres) GetAllOrdersOfCourierServiceAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idService int) ([]DetailedOrder, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var Orders []DetailedOrder
+	query, args := keyset("SELECT d.id_from_restaurant, d.order_date, d.courier_id,d.id,d.delivery_service_id,d.delivery_time,d.status,d.customer_address,d.restaurant_address,co.name, co.surname,co.phone_number FROM delivery AS d JOIN couriers AS co ON co.id_courier=d.courier_id Where d.delivery_service_id=$1 and status = 'ready to delivery'",
+		[]interface{}{idService}, []string{"d.id"}, afterId(after), limit)
+	res, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Println(err)
+		return nil, err
 	}
-	var length int
-	for reslen.Next() {
-		err = reslen.Scan(&length)
+	defer res.Close()
+	for res.Next() {
+		var order DetailedOrder
+		err = res.Scan(&order.OrderIdFromRestaurant, &order.OrderDate, &order.IdCourier, &order.IdOrder, &order.IdDeliveryService, &order.DeliveryTime, &order.Status, &order.CustomerAddress, &order.RestaurantAddress, &order.CourierName, &order.CourierSurname, &order.CourierPhoneNumber)
 		if err != nil {
 			log.Println(err)
+			return nil, err
 		}
+		Orders = append(Orders, order)
 	}
-	return Orders, length
+	return Orders, nil
 }
 
-func (r *OrderPostgres) GetOrdersOfCourierServiceForManagerFromDB(limit, page, idService int) ([]DetailedOrder, int) {
-	var Orders []DetailedOrder
-	transaction, err := r.db.Begin()
+func (r *OrderPostgres) GetCourierCompletedOrdersByMouthAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idCourier, Month, Year int) ([]Order, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var Orders []Order
+	query, args := keyset("SELECT courier_id ,id ,delivery_service_id ,delivery_time ,order_date ,status ,customer_address, restaurant_address FROM delivery where status='completed' and courier_id=$1 and Extract(MONTH from order_date )=$2 and Extract(Year from order_date )=$3",
+		[]interface{}{idCourier, Month, Year}, []string{"id"}, afterId(after), limit)
+	res, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Println(err)
+		return nil, err
 	}
-	defer transaction.Commit()
-	res, err := transaction.Query("SELECT d.order_date, d.courier_id,d.id,d.delivery_service_id,d.delivery_time,d.status,d.customer_address,d.restaurant_address,co.name, co.surname,co.phone_number FROM delivery AS d JOIN couriers AS co ON co.id_courier=d.courier_id Where d.delivery_service_id=$1 and status != 'completed' ORDER BY d.id LIMIT $2 OFFSET $3",
-		idService, limit, limit*(page-1))
+	defer res.Close()
+	for res.Next() {
+		var order Order
+		err = res.Scan(&order.IdCourier, &order.Id, &order.IdDeliveryService, &order.DeliveryTime, &order.OrderDate, &order.Status, &order.CustomerAddress, &order.RestaurantAddress)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		Orders = append(Orders, order)
+	}
+	return Orders, nil
+}
+
+func (r *OrderPostgres) GetCompletedOrdersOfCourierServiceAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idService int) ([]Order, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	query, args := keyset("SELECT delivery_service_id, order_date, courier_id,id,delivery_time,status,customer_address FROM delivery WHERE status='completed' and delivery_service_id=$1",
+		[]interface{}{idService}, []string{"id"}, afterId(after), limit)
+	return r.getCompletedOrdersOfCourierService(ctx, query, args)
+}
+
+func (r *OrderPostgres) GetCompletedOrdersOfCourierServiceByDateAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idService int) ([]Order, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	query, args := keyset("SELECT delivery_service_id, order_date, courier_id,id,delivery_time,status,customer_address FROM delivery WHERE status='completed' and delivery_service_id=$1",
+		[]interface{}{idService}, []string{"order_date", "id"}, afterOrderDate(after), limit)
+	return r.getCompletedOrdersOfCourierService(ctx, query, args)
+}
+
+func (r *OrderPostgres) GetCompletedOrdersOfCourierServiceByCourierIdAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idService int) ([]Order, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	query, args := keyset("SELECT delivery_service_id, order_date, courier_id,id,delivery_time,status,customer_address FROM delivery WHERE status='completed' and delivery_service_id=$1",
+		[]interface{}{idService}, []string{"courier_id", "id"}, afterCourierId(after), limit)
+	return r.getCompletedOrdersOfCourierService(ctx, query, args)
+}
+
+func (r *OrderPostgres) getCompletedOrdersOfCourierService(ctx context.Context, query string, args []interface{}) ([]Order, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var Orders []Order
+	res, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Println(err)
+		return nil, err
 	}
+	defer res.Close()
 	for res.Next() {
-		var order DetailedOrder
-		err = res.Scan(&order.OrderDate, &order.IdCourier, &order.IdOrder, &order.IdDeliveryService, &order.DeliveryTime,
-			&order.Status, &order.CustomerAddress, &order.RestaurantAddress, &order.CourierName, &order.CourierSurname,
-			&order.CourierPhoneNumber)
+		var order Order
+		err = res.Scan(&order.IdDeliveryService, &order.OrderDate, &order.IdCourier, &order.Id, &order.DeliveryTime, &order.Status, &order.CustomerAddress)
 		if err != nil {
 			log.Println(err)
+			return nil, err
 		}
-
 		Orders = append(Orders, order)
 	}
+	return Orders, nil
+}
 
-	var length int
-	resl, err := transaction.Query("SELECT count(*) FROM delivery WHERE delivery_service_id=$1", idService)
+func (r *OrderPostgres) GetOrdersOfCourierServiceForManagerAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idService int) ([]DetailedOrder, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var Orders []DetailedOrder
+	query, args := keyset("SELECT d.order_date, d.courier_id,d.id,d.delivery_service_id,d.delivery_time,d.status,d.customer_address,d.restaurant_address,co.name, co.surname,co.phone_number FROM delivery AS d JOIN couriers AS co ON co.id_courier=d.courier_id Where d.delivery_service_id=$1 and status != 'completed'",
+		[]interface{}{idService}, []string{"d.id"}, afterId(after), limit)
+	res, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		panic(err)
+		log.Println(err)
+		return nil, err
 	}
-	for resl.Next() {
-		err = resl.Scan(&length)
+	defer res.Close()
+	for res.Next() {
+		var order DetailedOrder
+		err = res.Scan(&order.OrderDate, &order.IdCourier, &order.IdOrder, &order.IdDeliveryService, &order.DeliveryTime,
+			&order.Status, &order.CustomerAddress, &order.RestaurantAddress, &order.CourierName, &order.CourierSurname,
+			&order.CourierPhoneNumber)
 		if err != nil {
 			log.Println(err)
+			return nil, err
 		}
+		Orders = append(Orders, order)
 	}
-	return Orders, length
+	return Orders, nil
 }
@@ -1,6 +1,8 @@
 package dao
 
 import (
+	"context"
+	"database/sql"
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"log"
@@ -14,7 +16,7 @@ func TestRepository_GetCourierCompletedOrdersWithPage_fromDB(t *testing.T) {
 		log.Fatal(err)
 	}
 	defer db.Close()
-	r := NewRepository(db)
+	r := NewRepository(db, Config{})
 
 	testTable := []struct {
 		name          string
@@ -27,19 +29,16 @@ func TestRepository_GetCourierCompletedOrdersWithPage_fromDB(t *testing.T) {
 		{
 			name: "OK",
 			mock: func(courier_id, limit, page int) {
-				mock.ExpectBegin()
 				rows := sqlmock.NewRows([]string{"order_date", "courier_id", "id", "delivery_service_id", "delivery_time", "status", "customer_address", "restaurant_address", "name", "phone_number"}).
 					AddRow("2022-02-02", 1, 1, 1, time.Date(2020, time.May, 2, 2, 2, 2, 2, time.UTC), "completed", "address", "address", "name", "1234567")
 
 				mock.ExpectQuery(`SELECT delivery.order_date, delivery.courier_id,delivery.id,delivery.delivery_service_id,delivery.delivery_time,delivery.status,delivery.customer_address,delivery.restaurant_address,couriers.name,couriers.phone_number FROM delivery JOIN couriers ON`).
 					WillReturnRows(rows)
 
-				rows2 := sqlmock.NewRows([]string{"courier_id"}).
-					AddRow(1)
-				mock.ExpectQuery(`SELECT courier_id FROM delivery WHERE status='completed' (.+)`).
-					WillReturnRows(rows2)
+				mock.ExpectQuery(`SELECT count\(\*\) FROM delivery WHERE status='completed' and courier_id=\$1`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-				mock.ExpectCommit()
 			},
 			courier_id: 1,
 			limit:      1,
@@ -64,20 +63,45 @@ func TestRepository_GetCourierCompletedOrdersWithPage_fromDB(t *testing.T) {
 	for _, tt := range testTable {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock(tt.courier_id, tt.limit, tt.page)
-			got, _ := r.GetCourierCompletedOrdersWithPage_fromDB(tt.courier_id, tt.limit, tt.page)
+			got, count, err := r.GetCourierCompletedOrdersWithPage_fromDB(context.Background(), tt.courier_id, tt.limit, tt.page)
 
+			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedOrder, got)
+			assert.Equal(t, 1, count)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
+
+func TestRepository_GetCourierCompletedOrdersWithPage_fromDB_CountFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectQuery(`SELECT delivery.order_date, (.+) LIMIT \$2 OFFSET \$3`).
+		WithArgs(1, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"order_date", "courier_id", "id", "delivery_service_id", "delivery_time", "status", "customer_address", "restaurant_address", "name", "phone_number"}))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM delivery`).
+		WillReturnError(context.DeadlineExceeded)
+
+	got, count, err := r.GetCourierCompletedOrdersWithPage_fromDB(context.Background(), 10, 1, 1)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, got)
+	assert.Zero(t, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRepository_GetCourierCompletedOrdersByMouthWithPage_fromDB(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer db.Close()
-	r := NewRepository(db)
+	r := NewRepository(db, Config{})
 
 	testTable := []struct {
 		name          string
@@ -92,19 +116,15 @@ func TestRepository_GetCourierCompletedOrdersByMouthWithPage_fromDB(t *testing.T
 		{
 			name: "OK",
 			mock: func(courier_id, limit, page int) {
-				mock.ExpectBegin()
 				rows := sqlmock.NewRows([]string{"courier_id", "id", "delivery_service_id", "delivery_time", "order_date", "status", "customer_address", "restaurant_address"}).
 					AddRow(1, 1, 1, time.Date(2020, time.May, 2, 2, 2, 2, 2, time.UTC), "2022-02-02", "completed", "address", "restaurant_address")
 
 				mock.ExpectQuery(`SELECT courier_id ,id ,delivery_service_id ,delivery_time ,order_date ,status ,customer_address, restaurant_address FROM delivery where (.+)`).
 					WillReturnRows(rows)
 
-				rows2 := sqlmock.NewRows([]string{"courier_id"}).
-					AddRow(1)
-				mock.ExpectQuery(`SELECT courier_id FROM delivery WHERE (.+)`).
-					WillReturnRows(rows2)
+				mock.ExpectQuery(`SELECT count\(\*\) FROM delivery WHERE (.+)`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-				mock.ExpectCommit()
 			},
 			courier_id: 1,
 			limit:      1,
@@ -129,10 +149,218 @@ func TestRepository_GetCourierCompletedOrdersByMouthWithPage_fromDB(t *testing.T
 	for _, tt := range testTable {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock(tt.courier_id, tt.limit, tt.page)
-			got, _ := r.GetCourierCompletedOrdersByMouthWithPageFromDB(tt.courier_id, tt.limit, tt.page, tt.month, tt.year)
+			got, count, err := r.GetCourierCompletedOrdersByMouthWithPageFromDB(context.Background(), tt.courier_id, tt.limit, tt.page, tt.month, tt.year)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOrder, got)
+			assert.Equal(t, 1, count)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_GetCompletedOrdersOfCourierServiceByDateAfterCursorFromDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	testTable := []struct {
+		name          string
+		mock          func()
+		after         Cursor
+		limit         int
+		idService     int
+		expectedOrder []Order
+	}{
+		{
+			name: "First page",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"delivery_service_id", "order_date", "courier_id", "id", "delivery_time", "status", "customer_address"}).
+					AddRow(1, "2022-02-02", 1, 1, time.Date(2020, time.May, 2, 2, 2, 2, 2, time.UTC), "completed", "address")
+				mock.ExpectQuery(`SELECT (.+) FROM delivery WHERE status='completed' and delivery_service_id=\$1 ORDER BY order_date,id LIMIT \$2`).
+					WithArgs(1, 2).
+					WillReturnRows(rows)
+			},
+			limit:     2,
+			idService: 1,
+			expectedOrder: []Order{
+				{IdDeliveryService: 1, OrderDate: "2022-02-02", IdCourier: 1, Id: 1, DeliveryTime: time.Date(2020, time.May, 2, 2, 2, 2, 2, time.UTC), Status: "completed", CustomerAddress: "address"},
+			},
+		},
+		{
+			name: "After cursor",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"delivery_service_id", "order_date", "courier_id", "id", "delivery_time", "status", "customer_address"}).
+					AddRow(1, "2022-02-03", 1, 2, time.Date(2020, time.May, 2, 2, 2, 2, 2, time.UTC), "completed", "address")
+				mock.ExpectQuery(`SELECT (.+) FROM delivery WHERE status='completed' and delivery_service_id=\$1 and \(order_date,id\) > \(\$2,\$3\) ORDER BY order_date,id LIMIT \$4`).
+					WithArgs(1, "2022-02-02", 1, 2).
+					WillReturnRows(rows)
+			},
+			after:     Cursor{Id: 1, OrderDate: "2022-02-02"},
+			limit:     2,
+			idService: 1,
+			expectedOrder: []Order{
+				{IdDeliveryService: 1, OrderDate: "2022-02-03", IdCourier: 1, Id: 2, DeliveryTime: time.Date(2020, time.May, 2, 2, 2, 2, 2, time.UTC), Status: "completed", CustomerAddress: "address"},
+			},
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := r.GetCompletedOrdersOfCourierServiceByDateAfterCursorFromDB(context.Background(), tt.limit, tt.after, tt.idService)
 
+			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedOrder, got)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	cursor := Cursor{Id: 10, OrderDate: "2022-02-02T00:00:00Z", CourierId: 3}
+	got, err := DecodeCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, got)
+
+	_, err = DecodeCursor("not a cursor")
+	assert.Error(t, err)
+}
+
+func TestRepository_GetOrderOwnerFromDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectQuery(`SELECT delivery_service_id, courier_id FROM delivery WHERE id = \$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_service_id", "courier_id"}).AddRow(2, nil))
+	mock.ExpectQuery(`SELECT delivery_service_id, courier_id FROM delivery WHERE id = \$1`).
+		WithArgs(4).
+		WillReturnError(sql.ErrNoRows)
+
+	idService, idCourier, err := r.GetOrderOwnerFromDB(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, idService)
+	assert.Equal(t, 0, idCourier)
+
+	_, _, err = r.GetOrderOwnerFromDB(context.Background(), 4)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetEarningsOfCourierFromDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectQuery(`SELECT count\(d.id\), coalesce\(ds.courier_rate, 0\) FROM couriers c`).
+		WithArgs(4, time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "coalesce"}).AddRow(10, 2.5))
+
+	earnings, err := r.GetEarningsOfCourierFromDB(context.Background(), 4, 3, 2022)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Earnings{Month: 3, Year: 2022, Deliveries: 10, Rate: 2.5, Amount: 25}, earnings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_ChangeOrderStatusInDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectExec(`UPDATE "delivery" SET "status" = \$1 WHERE "id" = \$2 AND \(\$3 = 0 OR "version" = \$3\)`).
+		WithArgs("completed", 3, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "delivery" SET "status" = \$1 WHERE "id" = \$2 AND \(\$3 = 0 OR "version" = \$3\)`).
+		WithArgs("completed", 3, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	id, err := r.ChangeOrderStatusInDB(context.Background(), "completed", 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(3), id)
+
+	_, err = r.ChangeOrderStatusInDB(context.Background(), "completed", 3, 1)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_AssigningOrderToCourierInDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT version FROM delivery WHERE id = \$1 FOR UPDATE`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectExec(`UPDATE delivery SET courier_id = \$1 WHERE id = \$2`).WithArgs(5, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT version FROM delivery WHERE id = \$1 FOR UPDATE`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT version FROM delivery WHERE id = \$1 FOR UPDATE`).WithArgs(4).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	assert.NoError(t, r.AssigningOrderToCourierInDB(context.Background(), Order{Id: 3, IdCourier: 5, Version: 2}))
+	assert.ErrorIs(t, r.AssigningOrderToCourierInDB(context.Background(), Order{Id: 3, IdCourier: 5, Version: 1}), ErrVersionMismatch)
+	assert.ErrorIs(t, r.AssigningOrderToCourierInDB(context.Background(), Order{Id: 4, IdCourier: 5}), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOrderFromDB_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectQuery(`Select delivery_service_id,id,courier_id,(.+) from delivery where id = \$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_service_id", "id", "courier_id", "delivery_time", "customer_address", "status", "order_date", "restaurant_address", "picked", "version"}))
+
+	_, err = r.GetOrderFromDB(context.Background(), 3)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetActiveOrdersFromDB_ScanFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectQuery(`Select delivery_service_id,id,courier_id,(.+) from delivery where courier_id = \$1`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_service_id", "id", "courier_id", "delivery_time", "customer_address", "status", "order_date", "restaurant_address", "picked"}).
+			AddRow(1, "not a number", 4, time.Now(), "address", "ready to delivery", "2022-02-02", "address", false))
+
+	orders, err := r.GetActiveOrdersFromDB(context.Background(), 4)
+
+	assert.Error(t, err)
+	assert.Nil(t, orders)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
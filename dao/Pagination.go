@@ -0,0 +1,21 @@
+package dao
+
+type Pagination struct {
+	Page       int `json:"page"`
+	Limit      int `json:"limit"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+func NewPagination(limit, page, totalCount int) Pagination {
+	totalPages := totalCount / limit
+	if totalCount%limit != 0 {
+		totalPages++
+	}
+	return Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      totalCount,
+		TotalPages: totalPages,
+	}
+}
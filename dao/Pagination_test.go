@@ -0,0 +1,44 @@
+package dao
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewPagination(t *testing.T) {
+	testTable := []struct {
+		name       string
+		limit      int
+		page       int
+		totalCount int
+		expected   Pagination
+	}{
+		{
+			name:       "OK",
+			limit:      10,
+			page:       2,
+			totalCount: 25,
+			expected:   Pagination{Page: 2, Limit: 10, Total: 25, TotalPages: 3},
+		},
+		{
+			name:       "Exact pages",
+			limit:      5,
+			page:       1,
+			totalCount: 10,
+			expected:   Pagination{Page: 1, Limit: 5, Total: 10, TotalPages: 2},
+		},
+		{
+			name:       "Empty",
+			limit:      5,
+			page:       1,
+			totalCount: 0,
+			expected:   Pagination{Page: 1, Limit: 5, Total: 0, TotalPages: 0},
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NewPagination(tt.limit, tt.page, tt.totalCount))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The Patch types are fields of a JSON merge patch (RFC 7396). Set tells
+// whether the patch has the field at all, and Null whether it sets the field
+// to null; Value is zero then.
+
+type PatchString struct {
+	Set   bool
+	Null  bool
+	Value string
+}
+
+func (f *PatchString) UnmarshalJSON(data []byte) error {
+	f.Set, f.Null = true, string(data) == "null"
+	if f.Null {
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+type PatchInt struct {
+	Set   bool
+	Null  bool
+	Value int
+}
+
+func (f *PatchInt) UnmarshalJSON(data []byte) error {
+	f.Set, f.Null = true, string(data) == "null"
+	if f.Null {
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+type PatchBool struct {
+	Set   bool
+	Null  bool
+	Value bool
+}
+
+func (f *PatchBool) UnmarshalJSON(data []byte) error {
+	f.Set, f.Null = true, string(data) == "null"
+	if f.Null {
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+type PatchFloat struct {
+	Set   bool
+	Null  bool
+	Value float64
+}
+
+func (f *PatchFloat) UnmarshalJSON(data []byte) error {
+	f.Set, f.Null = true, string(data) == "null"
+	if f.Null {
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// patchSet collects the SET clause of an UPDATE from the fields a patch has.
+// A null clears the column to the zero value of its field.
+type patchSet struct {
+	columns []string
+	args    []interface{}
+}
+
+func (s *patchSet) add(column string, set bool, value interface{}) {
+	if !set {
+		return
+	}
+	s.args = append(s.args, value)
+	s.columns = append(s.columns, fmt.Sprintf("%s = $%d", column, len(s.args)))
+}
+
+func (s *patchSet) empty() bool {
+	return len(s.columns) == 0
+}
+
+// update is the UPDATE of table for the row with idColumn = id.
+func (s *patchSet) update(table, idColumn string, id int) (string, []interface{}) {
+	args := append(s.args, id)
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d", table, strings.Join(s.columns, ", "), idColumn, len(args)), args
+}
@@ -0,0 +1,33 @@
+package dao
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPatchString_UnmarshalJSON(t *testing.T) {
+	var patch struct {
+		Name    PatchString `json:"name"`
+		Surname PatchString `json:"surname"`
+		Email   PatchString `json:"email"`
+	}
+	err := json.Unmarshal([]byte(`{"name":"Ivan","email":null}`), &patch)
+
+	assert.NoError(t, err)
+	assert.Equal(t, PatchString{Set: true, Value: "Ivan"}, patch.Name)
+	assert.Equal(t, PatchString{}, patch.Surname)
+	assert.Equal(t, PatchString{Set: true, Null: true}, patch.Email)
+}
+
+func TestPatchSet_Update(t *testing.T) {
+	var set patchSet
+	set.add("name", true, "Ivan")
+	set.add("surname", false, "")
+	set.add("deleted", true, false)
+
+	query, args := set.update("couriers", "id_courier", 5)
+
+	assert.Equal(t, "UPDATE couriers SET name = $1, deleted = $2 WHERE id_courier = $3", query)
+	assert.Equal(t, []interface{}{"Ivan", false, 5}, args)
+}
@@ -1,57 +1,122 @@
 package dao
 
 import (
+	"context"
 	"database/sql"
 	"google.golang.org/protobuf/types/known/emptypb"
 	courierProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPC"
 )
 
 type Repository struct {
+	Transactor
 	OrderRep
 	CourierRep
 	DeliveryServiceRep
+	SearchRep
+	UploadRep
+	DocumentRep
+	VehicleRep
 }
 
-func NewRepository(db *sql.DB) *Repository {
+func NewRepository(db *sql.DB, cfg Config) *Repository {
 	return &Repository{
-		NewDeliveryPostgres(db),
-		NewCourierPostgres(db),
-		NewDeliveryServicePostgres(db),
+		NewTxPostgres(db),
+		NewDeliveryPostgres(db, cfg.QueryTimeout),
+		NewCourierPostgres(db, cfg.QueryTimeout),
+		NewDeliveryServicePostgres(db, cfg.QueryTimeout),
+		NewSearchPostgres(db, cfg.QueryTimeout),
+		NewUploadPostgres(db, cfg.QueryTimeout),
+		NewDocumentPostgres(db, cfg.QueryTimeout),
+		NewVehiclePostgres(db, cfg.QueryTimeout),
 	}
 }
 
 type OrderRep interface {
-	GetActiveOrdersFromDB(id int) ([]Order, error)
-	GetActiveOrderFromDB(id int) (Order, error)
-	ChangeOrderStatusInDB(text string, id uint16) (uint16, error)
-	GetOrderFromDB(id int) (Order, error)
-	GetCourierCompletedOrdersWithPage_fromDB(limit, page, idCourier int) ([]DetailedOrder, int)
-	GetAllOrdersOfCourierServiceWithPageFromDB(limit, page, idService int) ([]DetailedOrder, int)
-	GetCourierCompletedOrdersByMouthWithPageFromDB(limit, page, idCourier, Month, Year int) ([]Order, int)
-	AssigningOrderToCourierInDB(order Order) error
-	GetDetailedOrderByIdFromDB(Id int) (*AllInfoAboutOrder, error)
-	CreateOrder(order *courierProto.OrderCourierServer) (*emptypb.Empty, error)
-	GetServices(in *emptypb.Empty) (*courierProto.ServicesResponse, error)
-	GetCompletedOrdersOfCourierServiceFromDB(limit, page, idService int) ([]Order, int)
-	GetCompletedOrdersOfCourierServiceByDateFromDB(limit, page, idService int) ([]Order, int)
-	GetCompletedOrdersOfCourierServiceByCourierIdFromDB(limit, page, idService int) ([]Order, int)
-	GetOrdersOfCourierServiceForManagerFromDB(limit, page, idService int) ([]DetailedOrder, int)
+	GetActiveOrdersFromDB(ctx context.Context, id int) ([]Order, error)
+	GetActiveOrderFromDB(ctx context.Context, id int) (Order, error)
+	ChangeOrderStatusInDB(ctx context.Context, text string, id uint16, version int) (uint16, error)
+	GetOrderFromDB(ctx context.Context, id int) (Order, error)
+	GetCourierCompletedOrdersWithPage_fromDB(ctx context.Context, limit, page, idCourier int) ([]DetailedOrder, int, error)
+	GetAllOrdersOfCourierServiceWithPageFromDB(ctx context.Context, limit, page, idService int) ([]DetailedOrder, int, error)
+	GetCourierCompletedOrdersByMouthWithPageFromDB(ctx context.Context, limit, page, idCourier, Month, Year int) ([]Order, int, error)
+	AssigningOrderToCourierInDB(ctx context.Context, order Order) error
+	GetDetailedOrderByIdFromDB(ctx context.Context, Id int) (*AllInfoAboutOrder, error)
+	CreateOrder(ctx context.Context, order *courierProto.OrderCourierServer) (*emptypb.Empty, error)
+	GetServices(ctx context.Context, in *emptypb.Empty) (*courierProto.ServicesResponse, error)
+	GetCompletedOrdersOfCourierServiceFromDB(ctx context.Context, limit, page, idService int) ([]Order, int, error)
+	GetCompletedOrdersOfCourierServiceByDateFromDB(ctx context.Context, limit, page, idService int) ([]Order, int, error)
+	GetCompletedOrdersOfCourierServiceByCourierIdFromDB(ctx context.Context, limit, page, idService int) ([]Order, int, error)
+	GetOrdersOfCourierServiceForManagerFromDB(ctx context.Context, limit, page, idService int) ([]DetailedOrder, int, error)
+	GetCourierCompletedOrdersAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idCourier int) ([]DetailedOrder, error)
+	GetAllOrdersOfCourierServiceAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idService int) ([]DetailedOrder, error)
+	GetCourierCompletedOrdersByMouthAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idCourier, Month, Year int) ([]Order, error)
+	GetCompletedOrdersOfCourierServiceAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idService int) ([]Order, error)
+	GetCompletedOrdersOfCourierServiceByDateAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idService int) ([]Order, error)
+	GetCompletedOrdersOfCourierServiceByCourierIdAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idService int) ([]Order, error)
+	GetOrdersOfCourierServiceForManagerAfterCursorFromDB(ctx context.Context, limit int, after Cursor, idService int) ([]DetailedOrder, error)
+	SetProofOfDeliveryInDB(ctx context.Context, id int, key string) error
+	GetProofOfDeliveryFromDB(ctx context.Context, id int) (string, error)
+	GetOrderLoadFromDB(ctx context.Context, id int) (OrderLoad, error)
+	GetOrderOwnerFromDB(ctx context.Context, id int) (int, int, error)
+	GetEarningsOfCourierFromDB(ctx context.Context, idCourier, month, year int) (Earnings, error)
 }
 
 type CourierRep interface {
-	SaveCourierInDB(Courier *Courier) error
-	GetCouriersFromDB() ([]SmallInfo, error)
-	GetCourierFromDB(id int) (Courier, error)
-	UpdateCourierInDB(id uint16, status bool) (uint16, error)
-	GetCouriersWithServiceFromDB() ([]Courier, error)
-	UpdateCourierDB(courier Courier) error
-	GetCouriersOfCourierServiceFromDB(limit, page, idService int) ([]Courier, int)
+	SaveCourierInDB(ctx context.Context, Courier *Courier) error
+	GetCouriersFromDB(ctx context.Context, idService int) ([]SmallInfo, error)
+	GetCourierFromDB(ctx context.Context, id int) (Courier, error)
+	UpdateCourierInDB(ctx context.Context, id uint16, status bool) (uint16, error)
+	GetCouriersWithServiceFromDB(ctx context.Context) ([]Courier, error)
+	UpdateCourierDB(ctx context.Context, courier Courier) error
+	PatchCourierInDB(ctx context.Context, id int, patch CourierPatch) (Courier, error)
+	GetCouriersOfCourierServiceFromDB(ctx context.Context, limit, page, idService int) ([]Courier, int, error)
+	SetCourierReadyToGoInDB(ctx context.Context, id int, ready bool) error
+	DeleteCourierFromDB(ctx context.Context, id int) error
+	GetDeliveryServiceOfCourierFromDB(ctx context.Context, id int) (int, error)
+	SetCourierStatusInDB(ctx context.Context, id int, from []string, status, reason string) (bool, error)
+	GetCourierStatusFromDB(ctx context.Context, id int) (string, error)
+	LockCourierInDB(ctx context.Context, id int) error
+	GetOnboardingOfCourierFromDB(ctx context.Context, id int) ([]OnboardingStep, error)
+	GetCouriersByStatusFromDB(ctx context.Context, idService int, status string) ([]Courier, error)
 }
 
 type DeliveryServiceRep interface {
-	SaveDeliveryServiceInDB(service *DeliveryService) (int, error)
-	GetDeliveryServiceByIdFromDB(Id int) (*DeliveryService, error)
-	GetAllDeliveryServicesFromDB() ([]DeliveryService, error)
-	UpdateDeliveryServiceInDB(service DeliveryService) error
-	GetNumberCouriersByServiceFromDB(id int) (int, error)
+	SaveDeliveryServiceInDB(ctx context.Context, service *DeliveryService) (int, error)
+	DeleteDeliveryServiceFromDB(ctx context.Context, id int) error
+	GetDeliveryServiceByIdFromDB(ctx context.Context, Id int) (*DeliveryService, error)
+	GetAllDeliveryServicesFromDB(ctx context.Context) ([]DeliveryService, error)
+	UpdateDeliveryServiceInDB(ctx context.Context, service DeliveryService) error
+	PatchDeliveryServiceInDB(ctx context.Context, id int, patch DeliveryServicePatch) (DeliveryService, error)
+	GetNumberCouriersByServiceFromDB(ctx context.Context, id int) (int, error)
+}
+
+type SearchRep interface {
+	SearchCouriersInDB(ctx context.Context, idService int, query string, limit int) ([]FoundCourier, error)
+	SearchOrdersInDB(ctx context.Context, idService int, query string, limit int) ([]FoundOrder, error)
+}
+
+type UploadRep interface {
+	SaveUploadInDB(ctx context.Context, upload *Upload) error
+	GetUploadFromDB(ctx context.Context, id string) (*Upload, error)
+	CompleteUploadInDB(ctx context.Context, id string) (bool, error)
+}
+
+type DocumentRep interface {
+	SaveCourierDocumentInDB(ctx context.Context, document *CourierDocument) (int, error)
+	GetCourierDocumentsFromDB(ctx context.Context, courierId int) ([]CourierDocument, error)
+	SetDocumentFileInDB(ctx context.Context, id int, key string) (int, string, error)
+	GetDocumentFileFromDB(ctx context.Context, courierId int, documentType string) (string, error)
+	GetCourierOfDocumentFromDB(ctx context.Context, id int) (int, error)
+	GetExpiringDocumentsFromDB(ctx context.Context, idService int, before Date) ([]ExpiringDocument, error)
+	ClaimDocumentsToWarnInDB(ctx context.Context, before Date) ([]ExpiringDocument, error)
+	GetDocumentWarningsFromDB(ctx context.Context, idService int) ([]DocumentWarning, error)
+	AcknowledgeDocumentWarningInDB(ctx context.Context, idService, id int) error
+	CountExpiredDocumentsOfCourierFromDB(ctx context.Context, courierId int, types []string, today Date) (int, error)
+	StopCouriersWithExpiredDocumentsInDB(ctx context.Context, types []string, today Date) ([]int, error)
+}
+
+type VehicleRep interface {
+	SaveVehicleInDB(ctx context.Context, vehicle *Vehicle) (int, error)
+	GetVehicleOfCourierFromDB(ctx context.Context, courierId int) (*Vehicle, error)
+	DeleteVehicleOfCourierFromDB(ctx context.Context, courierId int) error
 }
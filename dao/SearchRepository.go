@@ -0,0 +1,120 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+)
+
+type SearchPostgres struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+func NewSearchPostgres(db *sql.DB, timeout time.Duration) *SearchPostgres {
+	return &SearchPostgres{db: db, timeout: timeout}
+}
+
+type FoundCourier struct {
+	Id          uint16  `json:"id_courier"`
+	CourierName string  `json:"courier_name"`
+	Surname     string  `json:"surname"`
+	PhoneNumber string  `json:"phone_number"`
+	Email       string  `json:"email"`
+	Photo       string  `json:"photo"`
+	Deleted     bool    `json:"deleted"`
+	Rank        float64 `json:"rank"`
+}
+
+type FoundOrder struct {
+	Id                    int     `json:"id"`
+	OrderIdFromRestaurant int     `json:"id_from_restaurant"`
+	IdCourier             int     `json:"courier_id,omitempty"`
+	CustomerName          string  `json:"customer_name"`
+	CustomerPhone         string  `json:"customer_phone"`
+	CustomerAddress       string  `json:"customer_address"`
+	RestaurantAddress     string  `json:"restaurant_address"`
+	Status                string  `json:"status"`
+	OrderDate             string  `json:"order_date"`
+	Rank                  float64 `json:"rank"`
+}
+
+type SearchResult struct {
+	Couriers []FoundCourier `json:"couriers"`
+	Orders   []FoundOrder   `json:"orders"`
+}
+
+// The document expressions must stay identical to the ones in the
+// 0002_search_indexes migration, otherwise PostgreSQL won't use the indexes.
+const (
+	courierDocument = `(coalesce(name,'') || ' ' || coalesce(surname,'') || ' ' || coalesce(phone_number,'') || ' ' || coalesce(email,''))`
+	orderDocument   = `(coalesce(customer_name,'') || ' ' || coalesce(customer_phone,'') || ' ' || coalesce(customer_address,'') || ' ' || coalesce(restaurant_address,'') || ' ' || coalesce(id_from_restaurant::text,''))`
+)
+
+// likeEscaper escapes the wildcards of LIKE, so a search term only matches
+// itself. The queries declare \ as their escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern is the ILIKE pattern of values containing term.
+func containsPattern(term string) string {
+	return "%" + likeEscaper.Replace(term) + "%"
+}
+
+func (r *SearchPostgres) SearchCouriersInDB(ctx context.Context, idService int, query string, limit int) ([]FoundCourier, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	Couriers := []FoundCourier{}
+	res, err := conn(ctx, r.db).QueryContext(ctx, `SELECT id_courier, name, surname, phone_number, email, photo, deleted,
+       ts_rank(to_tsvector('simple', `+courierDocument+`), plainto_tsquery('simple', $2)) + similarity(`+courierDocument+`, $2) AS rank
+FROM couriers
+WHERE delivery_service_id = $1
+  AND (to_tsvector('simple', `+courierDocument+`) @@ plainto_tsquery('simple', $2) OR `+courierDocument+` ILIKE $4 ESCAPE '\')
+ORDER BY rank DESC, id_courier LIMIT $3`, idService, query, limit, containsPattern(query))
+	if err != nil {
+		log.Println("Error of searching couriers :" + err.Error())
+		return nil, err
+	}
+	defer res.Close()
+	for res.Next() {
+		var courier FoundCourier
+		err = res.Scan(&courier.Id, &courier.CourierName, &courier.Surname, &courier.PhoneNumber, &courier.Email,
+			&courier.Photo, &courier.Deleted, &courier.Rank)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		Couriers = append(Couriers, courier)
+	}
+	return Couriers, nil
+}
+
+func (r *SearchPostgres) SearchOrdersInDB(ctx context.Context, idService int, query string, limit int) ([]FoundOrder, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	Orders := []FoundOrder{}
+	res, err := conn(ctx, r.db).QueryContext(ctx, `SELECT id, id_from_restaurant, coalesce(courier_id, 0), customer_name, customer_phone, customer_address,
+       restaurant_address, status, order_date,
+       ts_rank(to_tsvector('simple', `+orderDocument+`), plainto_tsquery('simple', $2)) + similarity(`+orderDocument+`, $2) AS rank
+FROM delivery
+WHERE delivery_service_id = $1
+  AND (to_tsvector('simple', `+orderDocument+`) @@ plainto_tsquery('simple', $2) OR `+orderDocument+` ILIKE $4 ESCAPE '\')
+ORDER BY rank DESC, id DESC LIMIT $3`, idService, query, limit, containsPattern(query))
+	if err != nil {
+		log.Println("Error of searching orders :" + err.Error())
+		return nil, err
+	}
+	defer res.Close()
+	for res.Next() {
+		var order FoundOrder
+		err = res.Scan(&order.Id, &order.OrderIdFromRestaurant, &order.IdCourier, &order.CustomerName, &order.CustomerPhone,
+			&order.CustomerAddress, &order.RestaurantAddress, &order.Status, &order.OrderDate, &order.Rank)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		Orders = append(Orders, order)
+	}
+	return Orders, nil
+}
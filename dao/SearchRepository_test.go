@@ -0,0 +1,41 @@
+package dao
+
+import (
+	"context"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func TestRepository_SearchCouriersInDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	rows := sqlmock.NewRows([]string{"id_courier", "name", "surname", "phone_number", "email", "photo", "deleted", "rank"}).
+		AddRow(2, "Ivan", "Petrov", "+375291234567", "ivan@mail.com", "", false, 0.8).
+		AddRow(5, "Petr", "Ivanov", "+375297654321", "petr@mail.com", "", false, 0.2)
+	mock.ExpectQuery(`SELECT id_courier, name, surname, phone_number, email, photo, deleted, (.+) FROM couriers WHERE delivery_service_id = \$1 (.+) ILIKE \$4 ESCAPE '\\'\) ORDER BY rank DESC, id_courier LIMIT \$3`).
+		WithArgs(1, "petrov", 10, "%petrov%").
+		WillReturnRows(rows)
+
+	got, err := r.SearchCouriersInDB(context.Background(), 1, "petrov", 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []FoundCourier{
+		{Id: 2, CourierName: "Ivan", Surname: "Petrov", PhoneNumber: "+375291234567", Email: "ivan@mail.com", Rank: 0.8},
+		{Id: 5, CourierName: "Petr", Surname: "Ivanov", PhoneNumber: "+375297654321", Email: "petr@mail.com", Rank: 0.2},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContainsPattern(t *testing.T) {
+	assert.Equal(t, "%petrov%", containsPattern("petrov"))
+	assert.Equal(t, `%100\%%`, containsPattern("100%"))
+	assert.Equal(t, `%ivan\_petrov%`, containsPattern("ivan_petrov"))
+	assert.Equal(t, `%a\\b%`, containsPattern(`a\b`))
+}
@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"log"
+)
+
+// Transactor runs several repository calls as one unit of work.
+type Transactor interface {
+	// InTx runs fn in one transaction: repository calls made with the ctx
+	// it is given join it. The transaction is committed when fn returns nil
+	// and rolled back when it returns an error or panics. Calling InTx inside
+	// fn joins the transaction as well.
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TxPostgres struct {
+	db *sql.DB
+}
+
+func NewTxPostgres(db *sql.DB) *TxPostgres {
+	return &TxPostgres{db: db}
+}
+
+type txKey struct{}
+
+func (t *TxPostgres) InTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	transaction, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			transaction.Rollback()
+			panic(p)
+		}
+	}()
+	if err := fn(context.WithValue(ctx, txKey{}, transaction)); err != nil {
+		transaction.Rollback()
+		return err
+	}
+	return transaction.Commit()
+}
+
+// querier is what *sql.DB and *sql.Tx have in common.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn is the transaction of the unit of work ctx runs in, or db outside
+// one.
+func conn(ctx context.Context, db *sql.DB) querier {
+	if transaction, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return transaction
+	}
+	return db
+}
+
+// tx is the transaction of a repository call that needs one. Inside a unit
+// of work it is the transaction of the unit, whose end is up to InTx:
+// Commit and Rollback do nothing then.
+type tx struct {
+	*sql.Tx
+	joined bool
+}
+
+func beginTx(ctx context.Context, db *sql.DB) (*tx, error) {
+	if transaction, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &tx{Tx: transaction, joined: true}, nil
+	}
+	transaction, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &tx{Tx: transaction}, nil
+}
+
+func (t *tx) Commit() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t *tx) Rollback() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
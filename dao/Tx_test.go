@@ -0,0 +1,94 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func TestRepository_InTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	from := []string{"applied"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id_courier FROM couriers WHERE id_courier = \$1 FOR UPDATE`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id_courier"}).AddRow(5))
+	mock.ExpectExec(`UPDATE couriers SET status = \$1, status_reason = \$2 WHERE id_courier = \$3 AND status = ANY\(\$4\)`).
+		WithArgs("pending_review", "", 5, pq.Array(from)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO courier_onboarding \(courier_id, status, reason\) VALUES \(\$1, \$2, \$3\)`).
+		WithArgs(5, "pending_review", "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE couriers SET "ready to go" = \$1 WHERE id_courier = \$2`).
+		WithArgs(false, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = r.InTx(context.Background(), func(ctx context.Context) error {
+		if err := r.LockCourierInDB(ctx, 5); err != nil {
+			return err
+		}
+		if _, err := r.SetCourierStatusInDB(ctx, 5, from, "pending_review", ""); err != nil {
+			return err
+		}
+		return r.SetCourierReadyToGoInDB(ctx, 5, false)
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_InTx_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	failed := errors.New("courier can't take the order")
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE couriers SET "ready to go" = \$1 WHERE id_courier = \$2`).
+		WithArgs(true, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	err = r.InTx(context.Background(), func(ctx context.Context) error {
+		if err := r.SetCourierReadyToGoInDB(ctx, 5, true); err != nil {
+			return err
+		}
+		return failed
+	})
+
+	assert.ErrorIs(t, err, failed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_InTx_Panic(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		r.InTx(context.Background(), func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
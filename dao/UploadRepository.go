@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+type UploadPostgres struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+func NewUploadPostgres(db *sql.DB, timeout time.Duration) *UploadPostgres {
+	return &UploadPostgres{db: db, timeout: timeout}
+}
+
+type Upload struct {
+	Id          string    `json:"upload_id"`
+	Kind        string    `json:"kind"`
+	TargetId    int       `json:"target_id"`
+	ObjectKey   string    `json:"-"`
+	ContentType string    `json:"content_type"`
+	UserId      int       `json:"-"`
+	Status      string    `json:"status"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func (r *UploadPostgres) SaveUploadInDB(ctx context.Context, upload *Upload) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	_, err := conn(ctx, r.db).ExecContext(ctx, `INSERT INTO uploads (id, kind, target_id, object_key, content_type, user_id, status, expires_at)
+                              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		upload.Id, upload.Kind, upload.TargetId, upload.ObjectKey, upload.ContentType, upload.UserId, upload.Status, upload.ExpiresAt)
+	if err != nil {
+		log.Println("Error of saving upload in dao :" + err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetUploadFromDB returns sql.ErrNoRows for an unknown upload id.
+func (r *UploadPostgres) GetUploadFromDB(ctx context.Context, id string) (*Upload, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var upload Upload
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT id, kind, target_id, object_key, content_type, user_id, status, expires_at
+                               FROM uploads WHERE id = $1`, id).
+		Scan(&upload.Id, &upload.Kind, &upload.TargetId, &upload.ObjectKey, &upload.ContentType, &upload.UserId,
+			&upload.Status, &upload.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// CompleteUploadInDB marks a pending upload as completed. It reports false
+// when the upload had already been completed by a concurrent request.
+func (r *UploadPostgres) CompleteUploadInDB(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	res, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE uploads SET status = 'completed', completed_at = now() WHERE id = $1 AND status = 'pending'`, id)
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// PresignedUpload tells the client where to PUT the file. The request must
+// carry the Content-Type that was declared for the upload.
+type PresignedUpload struct {
+	Upload
+	URL    string `json:"url"`
+	Method string `json:"method"`
+}
@@ -0,0 +1,34 @@
+package dao
+
+import (
+	"context"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func TestRepository_CompleteUploadInDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectExec(`UPDATE uploads SET status = 'completed', completed_at = now\(\) WHERE id = \$1 AND status = 'pending'`).
+		WithArgs("abc").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE uploads SET status = 'completed'`).
+		WithArgs("abc").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	completed, err := r.CompleteUploadInDB(context.Background(), "abc")
+	assert.NoError(t, err)
+	assert.True(t, completed)
+
+	completed, err = r.CompleteUploadInDB(context.Background(), "abc")
+	assert.NoError(t, err)
+	assert.False(t, completed, "a second completion must not win")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,68 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+)
+
+type VehiclePostgres struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+func NewVehiclePostgres(db *sql.DB, timeout time.Duration) *VehiclePostgres {
+	return &VehiclePostgres{db: db, timeout: timeout}
+}
+
+type Vehicle struct {
+	Id         int     `json:"id"`
+	CourierId  int     `json:"courier_id"`
+	Type       string  `json:"type"`
+	CapacityKg float64 `json:"capacity_kg"`
+	Plate      string  `json:"plate"`
+}
+
+// SaveVehicleInDB registers the courier's vehicle, replacing the previous one.
+func (r *VehiclePostgres) SaveVehicleInDB(ctx context.Context, vehicle *Vehicle) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var id int
+	err := conn(ctx, r.db).QueryRowContext(ctx, `INSERT INTO vehicles (courier_id, type, capacity_kg, plate) VALUES ($1, $2, $3, $4)
+ON CONFLICT (courier_id) DO UPDATE SET type = EXCLUDED.type, capacity_kg = EXCLUDED.capacity_kg, plate = EXCLUDED.plate
+RETURNING id`, vehicle.CourierId, vehicle.Type, vehicle.CapacityKg, vehicle.Plate).Scan(&id)
+	if err != nil {
+		log.Println("Error of saving vehicle in dao :" + err.Error())
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetVehicleOfCourierFromDB returns nil when the courier has no vehicle.
+func (r *VehiclePostgres) GetVehicleOfCourierFromDB(ctx context.Context, courierId int) (*Vehicle, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	var vehicle Vehicle
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT id, courier_id, type, capacity_kg, plate FROM vehicles WHERE courier_id = $1`, courierId).
+		Scan(&vehicle.Id, &vehicle.CourierId, &vehicle.Type, &vehicle.CapacityKg, &vehicle.Plate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &vehicle, nil
+}
+
+func (r *VehiclePostgres) DeleteVehicleOfCourierFromDB(ctx context.Context, courierId int) error {
+	ctx, cancel := withQueryTimeout(ctx, r.timeout)
+	defer cancel()
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM vehicles WHERE courier_id = $1`, courierId); err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
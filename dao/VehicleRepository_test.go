@@ -0,0 +1,47 @@
+package dao
+
+import (
+	"context"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func TestRepository_SaveVehicleInDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectQuery(`INSERT INTO vehicles \(courier_id, type, capacity_kg, plate\) VALUES \(\$1, \$2, \$3, \$4\)\s+ON CONFLICT \(courier_id\) DO UPDATE`).
+		WithArgs(5, "car", 250.0, "1234 AB-7").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	id, err := r.SaveVehicleInDB(context.Background(), &Vehicle{CourierId: 5, Type: "car", CapacityKg: 250, Plate: "1234 AB-7"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetVehicleOfCourierFromDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	r := NewRepository(db, Config{})
+
+	mock.ExpectQuery(`SELECT id, courier_id, type, capacity_kg, plate FROM vehicles WHERE courier_id = \$1`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "courier_id", "type", "capacity_kg", "plate"}))
+
+	vehicle, err := r.GetVehicleOfCourierFromDB(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Nil(t, vehicle)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,7 @@
+package dao
+
+import "errors"
+
+// ErrVersionMismatch is returned by writes made against a version of a row
+// that has changed since. Writes with version 0 don't check the version.
+var ErrVersionMismatch = errors.New("version mismatch")
@@ -16,6 +16,31 @@ const docTemplate_swagger = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "forget the cached validation of the token, so that a token revoked in the auth service stops working here at once",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/courier": {
             "post": {
                 "security": [
@@ -23,7 +48,7 @@ const docTemplate_swagger = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "post new courier",
+                "description": "register a courier applicant and grant the user the Courier role in the auth service. The courier starts in the \"applied\" onboarding status and takes orders once the application is approved; couriers register themselves without a delivery service, managers register couriers of their own delivery service. A user has one courier at most, and a manager of a delivery service can't be made a courier",
                 "consumes": [
                     "application/json"
                 ],
@@ -41,13 +66,13 @@ const docTemplate_swagger = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dao.Courier"
+                            "$ref": "#/definitions/controller.courierRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/dao.Courier"
                         }
@@ -58,8 +83,20 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "type": "string"
                         }
@@ -99,6 +136,12 @@ const docTemplate_swagger = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/dao.Courier"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the courier, for If-Match"
+                            }
                         }
                     },
                     "400": {
@@ -107,6 +150,12 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -121,7 +170,7 @@ const docTemplate_swagger = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "put courier status by courier ID",
+                "description": "update the courier's name, surname, phone number and email; Superadmin can also move the courier to another delivery service",
                 "consumes": [
                     "application/json"
                 ],
@@ -141,21 +190,25 @@ const docTemplate_swagger = `{
                         "required": true
                     },
                     {
-                        "description": "deleted",
+                        "type": "string",
+                        "description": "ETag of the courier",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Courier",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "boolean"
+                            "$ref": "#/definitions/controller.courierUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dao.Courier"
-                        }
+                    "204": {
+                        "description": ""
                     },
                     "400": {
                         "description": "Bad Request",
@@ -163,6 +216,30 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -170,11 +247,14 @@ const docTemplate_swagger = `{
                         }
                     }
                 }
-            }
-        },
-        "/couriers": {
-            "get": {
-                "description": "get all couriers",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "change some fields of the courier with a JSON merge patch: fields left out keep their value, and null clears surname, phone_number or email. Setting deleted needs the rights to delete couriers, and only Superadmin moves couriers to another delivery service",
                 "consumes": [
                     "application/json"
                 ],
@@ -182,14 +262,45 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Couriers"
+                    "Courier"
+                ],
+                "summary": "PatchCourier",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the courier",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "merge patch",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.courierPatch"
+                        }
+                    }
                 ],
-                "summary": "GetCouriers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dao.SmallInfo"
+                            "$ref": "#/definitions/dao.Courier"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "new version of the courier"
+                            }
                         }
                     },
                     "400": {
@@ -198,8 +309,26 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
                         "schema": {
                             "type": "string"
                         }
@@ -207,96 +336,106 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/couriers/photo": {
-            "post": {
+        "/courier/{id}/documents": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "set photo to DO Spaces, and it's way to DB",
-                "consumes": [
-                    "image/jpeg"
-                ],
+                "description": "get documents of the courier",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Couriers"
+                    "Documents"
                 ],
-                "summary": "SaveCourierPhoto",
+                "summary": "GetCourierDocuments",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "id courier",
                         "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "logo image",
-                        "name": "logo",
-                        "in": "formData",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": ""
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dao.CourierDocument"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/couriers/service": {
-            "get": {
+        "/courier/{id}/documents/{type}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get list of all couriers by courier service id",
+                "description": "create or replace a courier document: driving_licence, id_card, vehicle_insurance or medical_certificate. Attach the scan with an upload of kind courier_document",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Couriers"
+                    "Documents"
                 ],
-                "summary": "GetCouriersOfCourierService",
+                "summary": "SaveCourierDocument",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
+                        "description": "id courier",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query",
+                        "type": "string",
+                        "description": "document type",
+                        "name": "type",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "iddeliveryservice",
-                        "name": "iddeliveryservice",
-                        "in": "query",
-                        "required": true
+                        "description": "number, issue_date and expiry_date (2006-01-02)",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.documentRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.listCouriers"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
                         }
                     },
                     "400": {
@@ -305,8 +444,8 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -314,26 +453,42 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/deliveryservice": {
+        "/courier/{id}/documents/{type}/file": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get list of all delivery service",
+                "description": "get the scan of the courier's document",
                 "produces": [
-                    "application/json"
+                    "image/jpeg"
                 ],
                 "tags": [
-                    "DeliveryService"
+                    "Documents"
+                ],
+                "summary": "GetDocumentFile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id courier",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "document type: driving_licence, id_card, vehicle_insurance or medical_certificate",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "GetAllDeliveryServices",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.listDeliveryServices"
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -342,58 +497,72 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/courier/{id}/ready": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "create a Delivery Service",
+                "description": "mark the courier ready or not ready to take orders. A courier with expired mandatory documents can't be made ready",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "DeliveryService"
+                    "Couriers"
                 ],
-                "summary": "CreateDeliveryService",
+                "summary": "SetCourierReadyToGo",
                 "parameters": [
                     {
-                        "description": "Delivery Service",
+                        "type": "integer",
+                        "description": "id courier",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ready_to_go",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dao.DeliveryService"
+                            "$ref": "#/definitions/controller.readyToGo"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dao.DeliveryService"
-                        }
+                    "204": {
+                        "description": ""
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "string"
                         }
@@ -401,42 +570,41 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/deliveryservice/logo": {
+        "/courier/{id}/review": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "set logo to DO Spaces, and it's way to DB",
+                "description": "approve or reject a courier pending review; a rejection needs a reason",
                 "consumes": [
-                    "image/jpeg"
-                ],
-                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "DeliveryService"
+                    "Onboarding"
                 ],
-                "summary": "SaveLogoController",
+                "summary": "ReviewCourierApplication",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "id delivery service",
+                        "description": "id courier",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "logo image",
-                        "name": "logo",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
+                        "description": "decision (approve or reject) and reason",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.review"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
                         "description": ""
                     },
                     "400": {
@@ -444,29 +612,99 @@ const docTemplate_swagger = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/deliveryservice/{id}": {
+        "/courier/{id}/submit": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "send the courier's application to review. The ID card and the medical certificate are required, plus the driving licence and the vehicle insurance for scooters and cars; none of them may be expired",
+                "tags": [
+                    "Onboarding"
+                ],
+                "summary": "SubmitCourierApplication",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id courier",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/{id}/vehicle": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get delivery service by id",
+                "description": "get the courier's vehicle",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "DeliveryService"
+                    "Vehicles"
                 ],
-                "summary": "GetDeliveryServiceById",
+                "summary": "GetVehicle",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "id",
+                        "description": "id courier",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -476,17 +714,17 @@ const docTemplate_swagger = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dao.DeliveryService"
+                            "$ref": "#/definitions/dao.Vehicle"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
@@ -499,7 +737,7 @@ const docTemplate_swagger = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update delivery service information",
+                "description": "register the courier's vehicle, replacing the previous one. Type is foot, bike, scooter or car; scooters and cars need a plate. Without capacity_kg the usual capacity of the type is assumed",
                 "consumes": [
                     "application/json"
                 ],
@@ -507,70 +745,74 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DeliveryService"
+                    "Vehicles"
                 ],
-                "summary": "UpdateDeliveryService",
+                "summary": "SaveVehicle",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "order_id",
+                        "description": "id courier",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "delivery service",
+                        "description": "type, capacity_kg and plate",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dao.DeliveryService"
+                            "$ref": "#/definitions/controller.vehicleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": ""
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            }
-        },
-        "/order/detailed/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get detailed order by id",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "remove the courier's vehicle; the courier is then dispatched as one on foot",
                 "tags": [
-                    "order"
+                    "Vehicles"
                 ],
-                "summary": "GetDetailedOrderById",
+                "summary": "DeleteVehicle",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "id",
+                        "description": "id courier",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dao.AllInfoAboutOrder"
-                        }
+                    "204": {
+                        "description": ""
                     },
                     "400": {
                         "description": "Bad Request",
@@ -578,8 +820,8 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -587,14 +829,9 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/order/status_change/{id}": {
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "put order status by order ID",
+        "/couriers": {
+            "get": {
+                "description": "get the couriers of the caller's delivery service, or of all delivery services for Superadmin",
                 "consumes": [
                     "application/json"
                 ],
@@ -602,32 +839,14 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
-                ],
-                "summary": "ChangeOrderStatus",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "status",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
+                    "Couriers"
                 ],
+                "summary": "GetCouriers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dao.Order"
+                            "$ref": "#/definitions/dao.SmallInfo"
                         }
                     },
                     "400": {
@@ -636,6 +855,12 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -645,33 +870,37 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/order/{id}": {
+        "/couriers/applications": {
             "get": {
-                "description": "check auth information",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "get the couriers of the delivery service waiting for review",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Onboarding"
                 ],
-                "summary": "GetOrder",
+                "summary": "GetCourierApplications",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "delivery service, required for Superadmin",
+                        "name": "iddeliveryservice",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dao.Order"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dao.Courier"
+                            }
                         }
                     },
                     "400": {
@@ -680,8 +909,8 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -689,50 +918,45 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/orders": {
-            "get": {
+        "/couriers/photo": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get list of all orders by courier service id",
+                "description": "set photo to DO Spaces, and it's way to DB. JPEG, PNG and GIF up to 10 MB are accepted; metadata is stripped and medium and thumbnail copies are stored too",
+                "consumes": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Couriers"
                 ],
-                "summary": "GetAllOrdersOfCourierService",
+                "summary": "SaveCourierPhoto",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "limit",
-                        "name": "limit",
+                        "description": "id courier",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "iddeliveryservice",
-                        "name": "iddeliveryservice",
-                        "in": "query",
+                        "type": "file",
+                        "description": "logo image",
+                        "name": "logo",
+                        "in": "formData",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controller.listDetailedOrders"
-                        }
+                    "204": {
+                        "description": ""
                     },
                     "400": {
                         "description": "Bad Request",
@@ -740,8 +964,20 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
                         "schema": {
                             "type": "string"
                         }
@@ -749,21 +985,21 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/orders/bymonth": {
+        "/couriers/service": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get list of completed orders by courier id sorted by month",
+                "description": "get list of all couriers by courier service id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Couriers"
                 ],
-                "summary": "GetCourierCompletedOrdersByMonth",
+                "summary": "GetCouriersOfCourierService",
                 "parameters": [
                     {
                         "type": "integer",
@@ -774,38 +1010,23 @@ const docTemplate_swagger = `{
                     },
                     {
                         "type": "integer",
-                        "description": "limit",
+                        "description": "limit, up to 100",
                         "name": "limit",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "idcourier",
-                        "name": "idcourier",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "month",
-                        "name": "month",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "year",
-                        "name": "year",
-                        "in": "query",
-                        "required": true
+                        "description": "delivery service, required for Superadmin",
+                        "name": "iddeliveryservice",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.listShortOrders"
+                            "$ref": "#/definitions/controller.listCouriers"
                         }
                     },
                     "400": {
@@ -814,6 +1035,12 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -823,49 +1050,64 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/orders/completed": {
+        "/debug/vars": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get list of completed orders by courier id",
+                "description": "get the metrics of the service published with expvar, token_cache among them",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Health"
                 ],
-                "summary": "GetCourierCompletedOrders",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                "summary": "DebugVars",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/deliveryservice": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "idcourier",
-                        "name": "idcourier",
-                        "in": "query",
-                        "required": true
+                        "ApiKeyAuth": []
                     }
                 ],
+                "description": "get list of all delivery service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DeliveryService"
+                ],
+                "summary": "GetAllDeliveryServices",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.listOrders"
+                            "$ref": "#/definitions/controller.listDeliveryServices"
                         }
                     },
                     "400": {
@@ -881,57 +1123,47 @@ const docTemplate_swagger = `{
                         }
                     }
                 }
-            }
-        },
-        "/orders/manager": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get list of all orders by courier service id with custom status",
+                "description": "create a Delivery Service; its manager is granted the Courier manager role in the auth service. A Courier manager always creates the service for themselves",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "DeliveryService"
                 ],
-                "summary": "GetOrdersOfCourierServiceForManager",
+                "summary": "CreateDeliveryService",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "iddeliveryservice",
-                        "name": "iddeliveryservice",
-                        "in": "query",
-                        "required": true
+                        "description": "Delivery Service",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.deliveryServiceRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.listDetailedOrders"
+                            "$ref": "#/definitions/dao.DeliveryService"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
@@ -939,60 +1171,55 @@ const docTemplate_swagger = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/orders/service/completed": {
-            "get": {
+        "/deliveryservice/logo": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get list of completed orders by courier service id",
+                "description": "set logo to DO Spaces, and it's way to DB. JPEG, PNG and GIF up to 10 MB are accepted; metadata is stripped and medium and thumbnail copies are stored too",
+                "consumes": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "DeliveryService"
                 ],
-                "summary": "GetCompletedOrdersOfCourierService",
+                "summary": "SaveLogoController",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
+                        "description": "id delivery service",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "iddeliveryservice",
-                        "name": "iddeliveryservice",
-                        "in": "query",
+                        "type": "file",
+                        "description": "logo image",
+                        "name": "logo",
+                        "in": "formData",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "sort",
-                        "name": "sort",
-                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controller.listShortOrders"
-                        }
+                    "204": {
+                        "description": ""
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1000,8 +1227,20 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
                         "schema": {
                             "type": "string"
                         }
@@ -1009,28 +1248,25 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/orders/{id}": {
+        "/deliveryservice/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get orders by courier ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "get delivery service by id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "DeliveryService"
                 ],
-                "summary": "GetOrders",
+                "summary": "GetDeliveryServiceById",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Courier ID",
+                        "description": "id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1040,7 +1276,13 @@ const docTemplate_swagger = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dao.Order"
+                            "$ref": "#/definitions/dao.DeliveryService"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the delivery service, for If-Match"
+                            }
                         }
                     },
                     "400": {
@@ -1049,6 +1291,12 @@ const docTemplate_swagger = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1063,7 +1311,7 @@ const docTemplate_swagger = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "assign order to courier",
+                "description": "update delivery service information",
                 "consumes": [
                     "application/json"
                 ],
@@ -1071,10 +1319,9 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "DeliveryService"
                 ],
-                "summary": "UpdateOrder",
-                "operationId": "UpdateOrder",
+                "summary": "UpdateDeliveryService",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1084,12 +1331,19 @@ const docTemplate_swagger = `{
                         "required": true
                     },
                     {
-                        "description": "id courier",
+                        "type": "string",
+                        "description": "ETag of the delivery service",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "delivery service",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dao.Order"
+                            "$ref": "#/definitions/controller.deliveryServiceUpdateRequest"
                         }
                     }
                 ],
@@ -1102,68 +1356,2151 @@ const docTemplate_swagger = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "controller.listCouriers": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dao.Courier"
-                    }
-                }
-            }
-        },
-        "controller.listDeliveryServices": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dao.DeliveryService"
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
-                }
-            }
-        },
-        "controller.listDetailedOrders": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dao.DetailedOrder"
+                ],
+                "description": "change some fields of the delivery service with a JSON merge patch: fields left out keep their value, and null clears description, phone_number or courier_rate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DeliveryService"
+                ],
+                "summary": "PatchDeliveryService",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the delivery service",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "merge patch",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.deliveryServicePatch"
+                        }
                     }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.DeliveryService"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "new version of the delivery service"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/expiring": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get documents of the delivery service's couriers that have expired or expire within the given number of days",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "GetExpiringDocuments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "days ahead, 30 by default",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "delivery service, required for Superadmin",
+                        "name": "iddeliveryservice",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dao.ExpiringDocument"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/warnings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the warnings of the daily document job about documents of the delivery service's couriers expiring soon that no manager has acknowledged yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "GetDocumentWarnings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "delivery service, required for Superadmin",
+                        "name": "iddeliveryservice",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dao.DocumentWarning"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/documents/warnings/{id}/ack": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "mark a document warning of the delivery service read, so it is no longer listed",
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "AcknowledgeDocumentWarning",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id warning",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "delivery service, required for Superadmin",
+                        "name": "iddeliveryservice",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{key}": {
+            "get": {
+                "description": "get a public file (courier photo, logo) when the service stores files itself. Proofs of delivery and document scans are served by /order/{id}/proof_of_delivery and /courier/{id}/documents/{type}/file",
+                "produces": [
+                    "image/jpeg"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "GetFile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file key, e.g. courier_photo/1",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "report whether the service can reach the auth service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/grpcClient.Health"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/grpcClient.Health"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/availability": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "mark the user ready or not ready to take orders",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "SetMyAvailability",
+                "parameters": [
+                    {
+                        "description": "ready_to_go",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.readyToGo"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/earnings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get what the user earned for the deliveries completed in a month, at the courier rate of their delivery service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "GetMyEarnings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "month, the current one by default",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "year, the current one by default",
+                        "name": "year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Earnings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/orders/active": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the orders the user has to deliver",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "GetMyActiveOrders",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dao.Order"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/orders/completed": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get list of the orders the user has completed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "GetMyCompletedOrders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page, required unless after is given",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page, enables keyset pagination",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.listOrders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/profile": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the courier profile of the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "GetMyProfile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Courier"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/service": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the delivery service the user manages",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "GetMyService",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.DeliveryService"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/detailed/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get detailed order by id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "GetDetailedOrderById",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.AllInfoAboutOrder"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the order, for If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/status_change/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "put order status by order ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "ChangeOrderStatus",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the order",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "status: ready to delivery or completed",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.text"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}": {
+            "get": {
+                "description": "check auth information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "GetOrder",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Order"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the order, for If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/proof_of_delivery": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the proof of delivery of the order",
+                "produces": [
+                    "image/jpeg"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "GetProofOfDelivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get list of all orders by courier service id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "GetAllOrdersOfCourierService",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page, required unless after is given",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page, enables keyset pagination",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit, up to 100",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "delivery service, required for Superadmin",
+                        "name": "iddeliveryservice",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.listDetailedOrders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/bymonth": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get list of completed orders by courier id sorted by month",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "GetCourierCompletedOrdersByMonth",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page, required unless after is given",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page, enables keyset pagination",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit, up to 100",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "idcourier",
+                        "name": "idcourier",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "month, 1 to 12",
+                        "name": "month",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "year, 2022 or later",
+                        "name": "year",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.listShortOrders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/completed": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get list of completed orders by courier id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "GetCourierCompletedOrders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page, required unless after is given",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page, enables keyset pagination",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit, up to 100",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "idcourier",
+                        "name": "idcourier",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.listOrders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/manager": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get list of all orders by courier service id with custom status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "GetOrdersOfCourierServiceForManager",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page, required unless after is given",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page, enables keyset pagination",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit, up to 100",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "delivery service, required for Superadmin",
+                        "name": "iddeliveryservice",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.listDetailedOrders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/service/completed": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get list of completed orders by courier service id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "GetCompletedOrdersOfCourierService",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "limit, up to 100",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page, required unless after is given",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the next page, enables keyset pagination",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "delivery service, required for Superadmin",
+                        "name": "iddeliveryservice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sort",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.listShortOrders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get orders by courier ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "GetOrders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Courier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "assign order to a courier of its delivery service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "UpdateOrder",
+                "operationId": "UpdateOrder",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "order_id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the order",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "id courier",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.assignOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "search couriers and orders of the caller's delivery service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "search text: name, surname, phone, email, address or restaurant order id",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max results of each kind, 20 by default and up to 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "delivery service, required for Superadmin",
+                        "name": "iddeliveryservice",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.SearchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get a presigned URL to upload a file straight to storage. PUT the file there with the declared Content-Type, then call /uploads/{id}/complete",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "CreateUpload",
+                "parameters": [
+                    {
+                        "description": "kind (courier_photo, logo, proof_of_delivery or courier_document), target_id (courier, delivery service, order or document id) and content_type",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.uploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dao.PresignedUpload"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads/{id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "check that the file of an upload has arrived and attach it to its courier, delivery service or order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "CompleteUpload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "upload id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controller.assignOrder": {
+            "type": "object",
+            "required": [
+                "courier_id"
+            ],
+            "properties": {
+                "courier_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "controller.courierPatch": {
+            "type": "object",
+            "properties": {
+                "courier_name": {
+                    "$ref": "#/definitions/dao.PatchString"
+                },
+                "deleted": {
+                    "$ref": "#/definitions/dao.PatchBool"
+                },
+                "delivery_service_id": {
+                    "$ref": "#/definitions/dao.PatchInt"
+                },
+                "email": {
+                    "$ref": "#/definitions/dao.PatchString"
+                },
+                "phone_number": {
+                    "$ref": "#/definitions/dao.PatchString"
+                },
+                "surname": {
+                    "$ref": "#/definitions/dao.PatchString"
+                }
+            }
+        },
+        "controller.courierRequest": {
+            "type": "object",
+            "required": [
+                "courier_name"
+            ],
+            "properties": {
+                "courier_name": {
+                    "type": "string"
+                },
+                "delivery_service_id": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "controller.courierUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "courier_name": {
+                    "type": "string"
+                },
+                "delivery_service_id": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.deliveryServicePatch": {
+            "type": "object",
+            "properties": {
+                "courier_rate": {
+                    "$ref": "#/definitions/dao.PatchFloat"
+                },
+                "description": {
+                    "$ref": "#/definitions/dao.PatchString"
+                },
+                "email": {
+                    "$ref": "#/definitions/dao.PatchString"
+                },
+                "name": {
+                    "$ref": "#/definitions/dao.PatchString"
+                },
+                "phone_number": {
+                    "$ref": "#/definitions/dao.PatchString"
+                },
+                "status": {
+                    "$ref": "#/definitions/dao.PatchString"
+                }
+            }
+        },
+        "controller.deliveryServiceRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name"
+            ],
+            "properties": {
+                "courier_rate": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "description": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "manager_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "inactive"
+                    ]
+                }
+            }
+        },
+        "controller.deliveryServiceUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "courier_rate": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "description": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "inactive"
+                    ]
+                }
+            }
+        },
+        "controller.documentRequest": {
+            "type": "object",
+            "required": [
+                "expiry_date",
+                "issue_date",
+                "number"
+            ],
+            "properties": {
+                "expiry_date": {
+                    "type": "string"
+                },
+                "issue_date": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.listCouriers": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.Courier"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.listDeliveryServices": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.DeliveryService"
+                    }
+                }
+            }
+        },
+        "controller.listDetailedOrders": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.DetailedOrder"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.listOrders": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.DetailedOrder"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.listShortOrders": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.Order"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.readyToGo": {
+            "type": "object",
+            "required": [
+                "ready_to_go"
+            ],
+            "properties": {
+                "ready_to_go": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "controller.review": {
+            "type": "object",
+            "required": [
+                "decision"
+            ],
+            "properties": {
+                "decision": {
+                    "type": "string",
+                    "enum": [
+                        "approve",
+                        "reject"
+                    ]
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.text": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.uploadRequest": {
+            "type": "object",
+            "required": [
+                "content_type",
+                "kind",
+                "target_id"
+            ],
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "courier_photo",
+                        "logo",
+                        "proof_of_delivery",
+                        "courier_document"
+                    ]
+                },
+                "target_id": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "controller.vehicleRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "capacity_kg": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "plate": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "foot",
+                        "bike",
+                        "scooter",
+                        "car"
+                    ]
+                }
+            }
+        },
+        "dao.AllInfoAboutOrder": {
+            "type": "object",
+            "properties": {
+                "courier_id": {
+                    "type": "integer"
+                },
+                "customer_address": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "customer_phone": {
+                    "type": "string"
+                },
+                "delivery_service_id": {
+                    "type": "integer"
+                },
+                "delivery_time": {
+                    "type": "string"
+                },
+                "distance_km": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "id_from_restaurant": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "order_date": {
+                    "type": "string"
+                },
+                "payment_type": {
+                    "type": "integer"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "picked": {
+                    "type": "boolean"
+                },
+                "proof_of_delivery": {
+                    "type": "string"
+                },
+                "restaurant_address": {
+                    "type": "string"
+                },
+                "restaurant_name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "weight_kg": {
+                    "type": "number"
+                }
+            }
+        },
+        "dao.Courier": {
+            "type": "object",
+            "properties": {
+                "courier_name": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "type": "boolean"
+                },
+                "delivery_service_id": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id_courier": {
+                    "type": "integer"
+                },
+                "number_of_failures": {
+                    "type": "integer"
+                },
+                "onboarding": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.OnboardingStep"
+                    }
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "photo": {
+                    "type": "string"
+                },
+                "photo_medium": {
+                    "type": "string"
+                },
+                "photo_thumbnail": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "ready_to_go": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_reason": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dao.CourierDocument": {
+            "type": "object",
+            "properties": {
+                "courier_id": {
+                    "type": "integer"
+                },
+                "expiry_date": {
+                    "$ref": "#/definitions/dao.Date"
+                },
+                "file": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "issue_date": {
+                    "$ref": "#/definitions/dao.Date"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "controller.listOrders": {
+        "dao.Date": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dao.DetailedOrder"
-                    }
+                "time.Time": {
+                    "type": "string"
                 }
             }
         },
-        "controller.listShortOrders": {
+        "dao.DeliveryService": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dao.Order"
-                    }
+                "courier_rate": {
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "manager_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "numOfCouriers": {
+                    "type": "integer"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "photo": {
+                    "type": "string"
+                },
+                "photo_medium": {
+                    "type": "string"
+                },
+                "photo_thumbnail": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "dao.AllInfoAboutOrder": {
+        "dao.DetailedOrder": {
             "type": "object",
             "properties": {
                 "courier_id": {
@@ -1172,12 +3509,6 @@ const docTemplate_swagger = `{
                 "customer_address": {
                     "type": "string"
                 },
-                "customer_name": {
-                    "type": "string"
-                },
-                "customer_phone": {
-                    "type": "string"
-                },
                 "delivery_service_id": {
                     "type": "integer"
                 },
@@ -1196,9 +3527,6 @@ const docTemplate_swagger = `{
                 "order_date": {
                     "type": "string"
                 },
-                "payment_type": {
-                    "type": "integer"
-                },
                 "phone_number": {
                     "type": "string"
                 },
@@ -1208,9 +3536,6 @@ const docTemplate_swagger = `{
                 "restaurant_address": {
                     "type": "string"
                 },
-                "restaurant_name": {
-                    "type": "string"
-                },
                 "status": {
                     "type": "string"
                 },
@@ -1219,66 +3544,88 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "dao.Courier": {
+        "dao.DocumentWarning": {
             "type": "object",
             "properties": {
-                "courier_name": {
+                "created_at": {
                     "type": "string"
                 },
-                "deleted": {
-                    "type": "boolean"
+                "document": {
+                    "$ref": "#/definitions/dao.ExpiringDocument"
                 },
-                "delivery_service_id": {
+                "id": {
                     "type": "integer"
+                }
+            }
+        },
+        "dao.Earnings": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
                 },
-                "email": {
-                    "type": "string"
+                "deliveries": {
+                    "type": "integer"
                 },
-                "id_courier": {
+                "month": {
                     "type": "integer"
                 },
-                "number_of_failures": {
+                "rate": {
+                    "type": "number"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dao.ExpiringDocument": {
+            "type": "object",
+            "properties": {
+                "courier_id": {
                     "type": "integer"
                 },
-                "phone_number": {
+                "courier_name": {
                     "type": "string"
                 },
-                "photo": {
+                "delivery_service_id": {
+                    "type": "integer"
+                },
+                "expiry_date": {
+                    "$ref": "#/definitions/dao.Date"
+                },
+                "file": {
                     "type": "string"
                 },
-                "rating": {
+                "id": {
                     "type": "integer"
                 },
-                "ready_to_go": {
-                    "type": "boolean"
+                "issue_date": {
+                    "$ref": "#/definitions/dao.Date"
+                },
+                "number": {
+                    "type": "string"
                 },
                 "surname": {
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "integer"
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "dao.DeliveryService": {
+        "dao.FoundCourier": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "email": {
+                "courier_name": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "manager_id": {
-                    "type": "integer"
+                "deleted": {
+                    "type": "boolean"
                 },
-                "name": {
+                "email": {
                     "type": "string"
                 },
-                "numOfCouriers": {
+                "id_courier": {
                     "type": "integer"
                 },
                 "phone_number": {
@@ -1287,12 +3634,15 @@ const docTemplate_swagger = `{
                 "photo": {
                     "type": "string"
                 },
-                "status": {
+                "rank": {
+                    "type": "number"
+                },
+                "surname": {
                     "type": "string"
                 }
             }
         },
-        "dao.DetailedOrder": {
+        "dao.FoundOrder": {
             "type": "object",
             "properties": {
                 "courier_id": {
@@ -1301,10 +3651,10 @@ const docTemplate_swagger = `{
                 "customer_address": {
                     "type": "string"
                 },
-                "delivery_service_id": {
-                    "type": "integer"
+                "customer_name": {
+                    "type": "string"
                 },
-                "delivery_time": {
+                "customer_phone": {
                     "type": "string"
                 },
                 "id": {
@@ -1313,25 +3663,30 @@ const docTemplate_swagger = `{
                 "id_from_restaurant": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
-                },
                 "order_date": {
                     "type": "string"
                 },
-                "phone_number": {
-                    "type": "string"
-                },
-                "picked": {
-                    "type": "boolean"
+                "rank": {
+                    "type": "number"
                 },
                 "restaurant_address": {
                     "type": "string"
                 },
                 "status": {
                     "type": "string"
+                }
+            }
+        },
+        "dao.OnboardingStep": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "surname": {
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
@@ -1368,6 +3723,108 @@ const docTemplate_swagger = `{
                 }
             }
         },
+        "dao.PatchBool": {
+            "type": "object",
+            "properties": {
+                "null": {
+                    "type": "boolean"
+                },
+                "set": {
+                    "type": "boolean"
+                },
+                "value": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dao.PatchFloat": {
+            "type": "object",
+            "properties": {
+                "null": {
+                    "type": "boolean"
+                },
+                "set": {
+                    "type": "boolean"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "dao.PatchInt": {
+            "type": "object",
+            "properties": {
+                "null": {
+                    "type": "boolean"
+                },
+                "set": {
+                    "type": "boolean"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dao.PatchString": {
+            "type": "object",
+            "properties": {
+                "null": {
+                    "type": "boolean"
+                },
+                "set": {
+                    "type": "boolean"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dao.PresignedUpload": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "integer"
+                },
+                "upload_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dao.SearchResult": {
+            "type": "object",
+            "properties": {
+                "couriers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.FoundCourier"
+                    }
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.FoundOrder"
+                    }
+                }
+            }
+        },
         "dao.SmallInfo": {
             "type": "object",
             "properties": {
@@ -1386,10 +3843,50 @@ const docTemplate_swagger = `{
                 "photo": {
                     "type": "string"
                 },
+                "photo_thumbnail": {
+                    "type": "string"
+                },
                 "surname": {
                     "type": "string"
                 }
             }
+        },
+        "dao.Vehicle": {
+            "type": "object",
+            "properties": {
+                "capacity_kg": {
+                    "type": "number"
+                },
+                "courier_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "plate": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "grpcClient.Health": {
+            "type": "object",
+            "properties": {
+                "breaker": {
+                    "type": "string"
+                },
+                "connection": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -9,6 +9,7 @@ func CorsMiddleware(c *gin.Context) {
 	c.Header("Access-Control-Allow-Origin", "*")
 	c.Header("Access-Control-Allow-Methods", "*")
 	c.Header("Access-Control-Allow-Headers", "*")
+	c.Header("Access-Control-Expose-Headers", "ETag")
 	c.Header("Content-Type", "application/json")
 
 	if c.Request.Method != "OPTIONS" {
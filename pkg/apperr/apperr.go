@@ -0,0 +1,172 @@
+// Package apperr is the error model shared by the dao, service and transport
+// layers. A domain error has a Kind, which alone decides the HTTP status and
+// the gRPC code it is answered with.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+type Kind int
+
+const (
+	// Internal is a failure of ours; its details aren't shown to clients.
+	Internal Kind = iota
+	Validation
+	Unauthenticated
+	Forbidden
+	NotFound
+	Conflict
+	Gone
+	// PreconditionFailed is a write made against a version of a resource
+	// that has changed since.
+	PreconditionFailed
+	// PreconditionRequired is a write that doesn't say which version of the
+	// resource it was made against.
+	PreconditionRequired
+	Unprocessable
+	TooLarge
+	UnsupportedMediaType
+	NotImplemented
+	BadGateway
+	Unavailable
+)
+
+var kinds = map[Kind]struct {
+	name   string
+	status int
+	code   codes.Code
+}{
+	Internal:             {"internal", http.StatusInternalServerError, codes.Internal},
+	Validation:           {"validation", http.StatusBadRequest, codes.InvalidArgument},
+	Unauthenticated:      {"unauthenticated", http.StatusUnauthorized, codes.Unauthenticated},
+	Forbidden:            {"forbidden", http.StatusForbidden, codes.PermissionDenied},
+	NotFound:             {"not_found", http.StatusNotFound, codes.NotFound},
+	Conflict:             {"conflict", http.StatusConflict, codes.FailedPrecondition},
+	Gone:                 {"gone", http.StatusGone, codes.NotFound},
+	PreconditionFailed:   {"precondition_failed", http.StatusPreconditionFailed, codes.Aborted},
+	PreconditionRequired: {"precondition_required", http.StatusPreconditionRequired, codes.FailedPrecondition},
+	Unprocessable:        {"unprocessable", http.StatusUnprocessableEntity, codes.FailedPrecondition},
+	TooLarge:             {"too_large", http.StatusRequestEntityTooLarge, codes.InvalidArgument},
+	UnsupportedMediaType: {"unsupported_media_type", http.StatusUnsupportedMediaType, codes.InvalidArgument},
+	NotImplemented:       {"not_implemented", http.StatusNotImplemented, codes.Unimplemented},
+	BadGateway:           {"bad_gateway", http.StatusBadGateway, codes.Unavailable},
+	Unavailable:          {"unavailable", http.StatusServiceUnavailable, codes.Unavailable},
+}
+
+func (k Kind) String() string {
+	return kinds[k].name
+}
+
+// Error is a domain error. Sentinels like service.ErrCourierNotFound are
+// Errors; wrapping them with fmt.Errorf("...: %w", err) keeps their kind.
+type Error struct {
+	Kind    Kind
+	Message string
+	Err     error
+	// Fields lists the fields of a request that failed validation.
+	Fields []FieldError
+	// formatted is set by Errorf, whose message already says what Err does.
+	formatted bool
+}
+
+// FieldError is what is wrong with one field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil && !e.formatted {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func New(kind Kind, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap gives err a kind; errors.Is still finds err.
+func Wrap(kind Kind, message string, err error) error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// Errorf formats a message for clients the way fmt.Errorf does and wraps the
+// domain error its %w stands for, keeping the kind, e.g.
+// Errorf("%w: a %s needs a plate", ErrInvalidVehicle, vehicle.Type).
+func Errorf(format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	wrapped := errors.Unwrap(err)
+	var e *Error
+	if !errors.As(wrapped, &e) {
+		return err
+	}
+	return &Error{Kind: e.Kind, Message: err.Error(), Err: wrapped, Fields: e.Fields, formatted: true}
+}
+
+// Invalid is the Validation error of a request with the given fields at
+// fault.
+func Invalid(fields ...FieldError) error {
+	return &Error{Kind: Validation, Message: "invalid request", Fields: fields}
+}
+
+// KindOf returns the kind of the outermost domain error in err's chain, and
+// Internal for errors without one.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return Internal
+}
+
+func HTTPStatus(err error) int {
+	return kinds[KindOf(err)].status
+}
+
+func GRPCCode(err error) codes.Code {
+	return kinds[KindOf(err)].code
+}
+
+// GRPCStatus turns err into a gRPC status error.
+func GRPCStatus(err error) error {
+	return status.Error(GRPCCode(err), message(err))
+}
+
+// Problem is the JSON body of an error response.
+type Problem struct {
+	Status  int          `json:"status"`
+	Error   string       `json:"error"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+func ProblemOf(err error) Problem {
+	kind := KindOf(err)
+	problem := Problem{Status: kinds[kind].status, Error: kind.String(), Message: message(err)}
+	var e *Error
+	if errors.As(err, &e) {
+		problem.Fields = e.Fields
+	}
+	return problem
+}
+
+// message is what clients are told of err: the message of its outermost
+// domain error. The layers err went through and the causes it wraps are for
+// the logs only.
+func message(err error) string {
+	var e *Error
+	if !errors.As(err, &e) || e.Kind == Internal {
+		return "internal error"
+	}
+	return e.Message
+}
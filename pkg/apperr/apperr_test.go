@@ -0,0 +1,106 @@
+package apperr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"reflect"
+	"testing"
+)
+
+var errCourierNotFound = New(NotFound, "courier not found")
+
+func TestKindOf(t *testing.T) {
+	testTable := []struct {
+		name   string
+		err    error
+		kind   Kind
+		status int
+		code   codes.Code
+	}{
+		{name: "Sentinel", err: errCourierNotFound, kind: NotFound, status: 404, code: codes.NotFound},
+		{name: "Wrapped sentinel", err: fmt.Errorf("Error in CourierService: %w", errCourierNotFound), kind: NotFound, status: 404, code: codes.NotFound},
+		{name: "Validation", err: New(Validation, "no id"), kind: Validation, status: 400, code: codes.InvalidArgument},
+		{name: "Conflict", err: New(Conflict, "upload is already completed"), kind: Conflict, status: 409, code: codes.FailedPrecondition},
+		{name: "Stale version", err: New(PreconditionFailed, "courier was changed"), kind: PreconditionFailed, status: 412, code: codes.Aborted},
+		{name: "Forbidden", err: New(Forbidden, "not enough rights"), kind: Forbidden, status: 403, code: codes.PermissionDenied},
+		{name: "Unavailable", err: New(Unavailable, "auth service unavailable"), kind: Unavailable, status: 503, code: codes.Unavailable},
+		{name: "Plain error", err: errors.New("connection refused"), kind: Internal, status: 500, code: codes.Internal},
+		{name: "Error lost by %s", err: fmt.Errorf("Error in CourierService: %s", errCourierNotFound), kind: Internal, status: 500, code: codes.Internal},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			if kind := KindOf(testCase.err); kind != testCase.kind {
+				t.Fatalf("got kind %s, want %s", kind, testCase.kind)
+			}
+			if got := HTTPStatus(testCase.err); got != testCase.status {
+				t.Fatalf("got status %d, want %d", got, testCase.status)
+			}
+			if got := status.Code(GRPCStatus(testCase.err)); got != testCase.code {
+				t.Fatalf("got code %s, want %s", got, testCase.code)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	err := fmt.Errorf("Error in OrderService: %w", Wrap(NotFound, "order not found", sql.ErrNoRows))
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatal("wrapped error is lost")
+	}
+	if err.Error() != "Error in OrderService: order not found: sql: no rows in result set" {
+		t.Fatalf("got %q", err)
+	}
+}
+
+func TestProblemOf(t *testing.T) {
+	got := ProblemOf(fmt.Errorf("Error in CourierService: %w", errCourierNotFound))
+	want := Problem{Status: 404, Error: "not_found", Message: "courier not found"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	got = ProblemOf(fmt.Errorf("Error in OrderService: %w", Wrap(NotFound, "order not found", errors.New("pq: relation \"delivery\" does not exist"))))
+	want = Problem{Status: 404, Error: "not_found", Message: "order not found"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("cause shown: %+v", got)
+	}
+
+	got = ProblemOf(errors.New("pq: password authentication failed"))
+	want = Problem{Status: 500, Error: "internal", Message: "internal error"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("internal details shown: %+v", got)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	err := Invalid(FieldError{Field: "phone_number", Message: "must be a phone number"})
+	got := ProblemOf(err)
+	want := Problem{Status: 400, Error: "validation", Message: "invalid request",
+		Fields: []FieldError{{Field: "phone_number", Message: "must be a phone number"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	errInvalidVehicle := New(Validation, "invalid vehicle")
+	err := fmt.Errorf("Error in VehicleService: %w", Errorf("%w: a %s needs a plate", errInvalidVehicle, "scooter"))
+	if !errors.Is(err, errInvalidVehicle) {
+		t.Fatal("wrapped error is lost")
+	}
+	if err.Error() != "Error in VehicleService: invalid vehicle: a scooter needs a plate" {
+		t.Fatalf("got %q", err)
+	}
+	got := ProblemOf(err)
+	want := Problem{Status: 400, Error: "validation", Message: "invalid vehicle: a scooter needs a plate"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if KindOf(Errorf("%w: timeout", errors.New("connection refused"))) != Internal {
+		t.Fatal("plain error got a kind")
+	}
+}
@@ -0,0 +1,106 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// jpegOrientation returns the EXIF orientation tag (1-8) of a JPEG, or 1 when
+// the file has none. Cameras store photos unrotated and rely on this tag, so
+// it has to be applied before the EXIF block is thrown away.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA { // start of scan: no more metadata segments
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : end]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		pos = end
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// orient turns img so that it displays upright without the EXIF tag.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored
+				sx, sy = width-1-x, y
+			case 3: // rotated 180°
+				sx, sy = width-1-x, height-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, height-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // needs a 90° clockwise turn
+				sx, sy = y, height-1-x
+			case 7: // transversed
+				sx, sy = width-1-y, height-1-x
+			case 8: // needs a 90° counter-clockwise turn
+				sx, sy = width-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,110 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+)
+
+var (
+	ErrTooLarge        = apperr.New(apperr.TooLarge, "image is too large")
+	ErrTooManyPixels   = apperr.New(apperr.TooLarge, "image has too many pixels")
+	ErrUnsupportedType = apperr.New(apperr.UnsupportedMediaType, "unsupported image type")
+	ErrInvalidImage    = apperr.New(apperr.Validation, "invalid image")
+)
+
+const (
+	ThumbnailSide = 150
+	MediumSide    = 640
+)
+
+// Limits protect the service from oversized uploads. MaxPixels is checked
+// against the image header before decoding, so a small file that claims huge
+// dimensions is rejected without allocating the bitmap.
+type Limits struct {
+	MaxBytes  int
+	MaxPixels int
+}
+
+var DefaultLimits = Limits{MaxBytes: 10 << 20, MaxPixels: 40_000_000}
+
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Processed holds the cleaned original and its resized variants.
+type Processed struct {
+	Original  Image
+	Medium    Image
+	Thumbnail Image
+}
+
+// Process checks that data is a JPEG, PNG or GIF within limits, applies the
+// EXIF orientation and re-encodes it. Re-encoding drops every metadata block
+// of the upload (EXIF with GPS position, XMP, PNG text chunks). JPEGs stay
+// JPEGs; PNGs and GIFs become PNGs so logos keep their transparency.
+func Process(data []byte, limits Limits) (*Processed, error) {
+	if len(data) > limits.MaxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, at most %d allowed", ErrTooLarge, len(data), limits.MaxBytes)
+	}
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImage, err)
+	}
+	if config.Width*config.Height > limits.MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooManyPixels, config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImage, err)
+	}
+	if contentType == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	} else {
+		contentType = "image/png"
+	}
+
+	var processed Processed
+	if processed.Original, err = encode(img, contentType); err != nil {
+		return nil, err
+	}
+	medium := resize(img, MediumSide)
+	if processed.Medium, err = encode(medium, contentType); err != nil {
+		return nil, err
+	}
+	// The thumbnail is made from the medium variant: it is already close in
+	// size and far cheaper to scan than a full-size photo.
+	if processed.Thumbnail, err = encode(resize(medium, ThumbnailSide), contentType); err != nil {
+		return nil, err
+	}
+	return &processed, nil
+}
+
+func encode(img image.Image, contentType string) (Image, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return Image{}, fmt.Errorf("imaging: encode: %w", err)
+	}
+	bounds := img.Bounds()
+	return Image{Data: buf.Bytes(), ContentType: contentType, Width: bounds.Dx(), Height: bounds.Dy()}, nil
+}
@@ -0,0 +1,113 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testJPEG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width/2; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withExif inserts an APP1 segment with the given orientation and a fake GPS
+// tag right after the SOI marker.
+func withExif(data []byte, orientation uint16) []byte {
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 2,
+		0x01, 0x12, 0, 3, 0, 0, 0, 1, byte(orientation >> 8), byte(orientation), 0, 0,
+		0x88, 0x25, 0, 4, 0, 0, 0, 1, 0, 0, 0, 0,
+		0, 0, 0, 0}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := append([]byte{0xFF, 0xE1, byte((len(payload) + 2) >> 8), byte(len(payload) + 2)}, payload...)
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestProcess(t *testing.T) {
+	processed, err := Process(testJPEG(t, 1200, 800), DefaultLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "image/jpeg", processed.Original.ContentType)
+	assert.Equal(t, []int{1200, 800}, []int{processed.Original.Width, processed.Original.Height})
+	assert.Equal(t, []int{640, 426}, []int{processed.Medium.Width, processed.Medium.Height})
+	assert.Equal(t, []int{150, 99}, []int{processed.Thumbnail.Width, processed.Thumbnail.Height})
+
+	thumbnail, err := jpeg.Decode(bytes.NewReader(processed.Thumbnail.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _, _, _ := thumbnail.At(10, 10).RGBA()
+	assert.Greater(t, r, uint32(0xE000), "left half stays red")
+}
+
+func TestProcessStripsExifAndAppliesOrientation(t *testing.T) {
+	data := withExif(testJPEG(t, 300, 200), 6)
+	assert.Equal(t, 6, jpegOrientation(data))
+
+	processed, err := Process(data, DefaultLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []int{200, 300}, []int{processed.Original.Width, processed.Original.Height})
+	assert.False(t, bytes.Contains(processed.Original.Data, []byte("Exif")))
+	assert.Equal(t, 1, jpegOrientation(processed.Original.Data))
+
+	// After a clockwise turn the red left half ends up on top.
+	upright, err := jpeg.Decode(bytes.NewReader(processed.Original.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _, _, _ := upright.At(100, 10).RGBA()
+	assert.Greater(t, r, uint32(0xE000))
+}
+
+func TestProcessKeepsPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 100, 50))); err != nil {
+		t.Fatal(err)
+	}
+	processed, err := Process(buf.Bytes(), DefaultLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "image/png", processed.Thumbnail.ContentType)
+	assert.Equal(t, []int{100, 50}, []int{processed.Thumbnail.Width, processed.Thumbnail.Height})
+}
+
+func TestProcessRejects(t *testing.T) {
+	data := testJPEG(t, 100, 100)
+	testTable := []struct {
+		name     string
+		data     []byte
+		limits   Limits
+		expected error
+	}{
+		{name: "not an image", data: []byte("<html>hello</html>"), limits: DefaultLimits, expected: ErrUnsupportedType},
+		{name: "too large", data: data, limits: Limits{MaxBytes: 10, MaxPixels: 1 << 20}, expected: ErrTooLarge},
+		{name: "too many pixels", data: data, limits: Limits{MaxBytes: 1 << 20, MaxPixels: 100}, expected: ErrTooManyPixels},
+		{name: "truncated", data: data[:20], limits: DefaultLimits, expected: ErrInvalidImage},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := Process(testCase.data, testCase.limits)
+			assert.True(t, errors.Is(err, testCase.expected), "got %v", err)
+		})
+	}
+}
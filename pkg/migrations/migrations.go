@@ -0,0 +1,188 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockKey is the pg_advisory_lock key that keeps two instances of the
+// service from migrating the same database at the same time.
+const lockKey = 7209341
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads "<version>_<name>.up.sql" and "<version>_<name>.down.sql" pairs
+// from the sql directory and returns them ordered by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("migrations: %w", err)
+	}
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		parts := fileName.FindStringSubmatch(entry.Name())
+		if parts == nil {
+			return nil, fmt.Errorf("migrations: unexpected file %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(parts[1])
+		body, err := fs.ReadFile(fsys, "sql/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrations: %w", err)
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != parts[2] {
+			return nil, fmt.Errorf("migrations: version %d has two names: %s and %s", version, migration.Name, parts[2])
+		}
+		if parts[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migrations: version %d has no up file", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies every migration newer than the current schema version.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func(conn *sql.Conn, current int) error {
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			log.Printf("migrations: applying %d_%s", migration.Version, migration.Name)
+			if err := apply(ctx, conn, migration.Up,
+				`INSERT INTO schema_version (version, name) VALUES ($1, $2)`, migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("migrations: %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Down rolls back the given number of the most recently applied migrations.
+// A migration without a down file is irreversible: Down refuses to go past
+// it and rolls nothing back then. 0001_init is one, as it adopts tables that
+// existed before migrations did.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.locked(ctx, func(conn *sql.Conn, current int) error {
+		var rollback []Migration
+		for i := len(m.migrations) - 1; i >= 0 && len(rollback) < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > current {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migrations: %d_%s is irreversible", migration.Version, migration.Name)
+			}
+			rollback = append(rollback, migration)
+		}
+		for _, migration := range rollback {
+			log.Printf("migrations: rolling back %d_%s", migration.Version, migration.Name)
+			if err := apply(ctx, conn, migration.Down,
+				`DELETE FROM schema_version WHERE version = $1`, migration.Version); err != nil {
+				return fmt.Errorf("migrations: %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Version returns the latest applied migration, 0 for an empty database.
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	var version int
+	err := m.locked(ctx, func(conn *sql.Conn, current int) error {
+		version = current
+		return nil
+	})
+	return version, err
+}
+
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, current int) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("migrations: %w", err)
+	}
+	defer conn.Close()
+
+	// Advisory locks belong to the session, so lock and unlock have to run
+	// on the same connection as the migrations themselves.
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("migrations: lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+			log.Println("migrations: unlock:", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_version (
+    version    INTEGER PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`); err != nil {
+		return fmt.Errorf("migrations: %w", err)
+	}
+	var current int
+	if err := conn.QueryRowContext(ctx, `SELECT coalesce(max(version), 0) FROM schema_version`).Scan(&current); err != nil {
+		return fmt.Errorf("migrations: %w", err)
+	}
+	return fn(conn, current)
+}
+
+func apply(ctx context.Context, conn *sql.Conn, script string, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,143 @@
+package migrations
+
+import (
+	"context"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	testTable := []struct {
+		name        string
+		files       fstest.MapFS
+		expected    []Migration
+		expectedErr bool
+	}{
+		{
+			name: "OK",
+			files: fstest.MapFS{
+				"sql/0002_second.up.sql":   {Data: []byte("up 2")},
+				"sql/0001_first.up.sql":    {Data: []byte("up 1")},
+				"sql/0001_first.down.sql":  {Data: []byte("down 1")},
+				"sql/0002_second.down.sql": {Data: []byte("down 2")},
+			},
+			expected: []Migration{
+				{Version: 1, Name: "first", Up: "up 1", Down: "down 1"},
+				{Version: 2, Name: "second", Up: "up 2", Down: "down 2"},
+			},
+		},
+		{
+			name: "Missing up",
+			files: fstest.MapFS{
+				"sql/0001_first.down.sql": {Data: []byte("down 1")},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "Unexpected file",
+			files: fstest.MapFS{
+				"sql/first.sql": {Data: []byte("up 1")},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.files)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	got, err := Load(files)
+	assert.NoError(t, err)
+	for i, migration := range got {
+		assert.Equal(t, i+1, migration.Version)
+		if migration.Version == 1 {
+			assert.Empty(t, migration.Down, "0001_init is irreversible")
+		} else {
+			assert.NotEmpty(t, migration.Down)
+		}
+	}
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	m := &Migrator{db: db, migrations: []Migration{
+		{Version: 1, Name: "first", Up: "CREATE TABLE first()"},
+		{Version: 2, Name: "second", Up: "CREATE TABLE second()"},
+	}}
+
+	mock.ExpectExec(`SELECT pg_advisory_lock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_version`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT coalesce\(max\(version\), 0\) FROM schema_version`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectExec(`CREATE TABLE second\(\)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO schema_version`).WithArgs(2, "second").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, m.Up(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	m := &Migrator{db: db, migrations: []Migration{
+		{Version: 1, Name: "first", Up: "CREATE TABLE first()", Down: "DROP TABLE first"},
+		{Version: 2, Name: "second", Up: "CREATE TABLE second()", Down: "DROP TABLE second"},
+	}}
+
+	mock.ExpectExec(`SELECT pg_advisory_lock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_version`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT coalesce\(max\(version\), 0\) FROM schema_version`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DROP TABLE second`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM schema_version`).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, m.Down(context.Background(), 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down_Irreversible(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	m := &Migrator{db: db, migrations: []Migration{
+		{Version: 1, Name: "first", Up: "CREATE TABLE first()"},
+		{Version: 2, Name: "second", Up: "CREATE TABLE second()", Down: "DROP TABLE second"},
+	}}
+
+	mock.ExpectExec(`SELECT pg_advisory_lock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_version`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT coalesce\(max\(version\), 0\) FROM schema_version`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.EqualError(t, m.Down(context.Background(), 2), "migrations: 1_first is irreversible")
+	assert.NoError(t, mock.ExpectationsWereMet(), "second isn't rolled back either")
+}
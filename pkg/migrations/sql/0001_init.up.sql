@@ -0,0 +1,54 @@
+-- Baseline schema expected by the dao package. IF NOT EXISTS lets databases
+-- created before migrations were introduced adopt this version as is.
+CREATE TABLE IF NOT EXISTS delivery_service
+(
+    id           SERIAL PRIMARY KEY,
+    name         TEXT    NOT NULL,
+    email        TEXT    NOT NULL,
+    photo        TEXT    NOT NULL DEFAULT '',
+    description  TEXT    NOT NULL DEFAULT '',
+    phone_number TEXT    NOT NULL DEFAULT '',
+    manager_id   INTEGER NOT NULL DEFAULT 0,
+    status       TEXT    NOT NULL DEFAULT 'active'
+);
+
+CREATE TABLE IF NOT EXISTS couriers
+(
+    id_courier          SERIAL PRIMARY KEY,
+    user_id             INTEGER NOT NULL DEFAULT 0,
+    name                TEXT    NOT NULL DEFAULT '',
+    "ready to go"       BOOLEAN NOT NULL DEFAULT FALSE,
+    phone_number        TEXT    NOT NULL DEFAULT '',
+    email               TEXT    NOT NULL DEFAULT '',
+    rating              INTEGER NOT NULL DEFAULT 0,
+    photo               TEXT    NOT NULL DEFAULT '',
+    surname             TEXT    NOT NULL DEFAULT '',
+    number_of_failures  INTEGER NOT NULL DEFAULT 0,
+    deleted             BOOLEAN NOT NULL DEFAULT FALSE,
+    delivery_service_id INTEGER REFERENCES delivery_service (id)
+);
+
+CREATE TABLE IF NOT EXISTS delivery
+(
+    id                  SERIAL PRIMARY KEY,
+    delivery_service_id INTEGER   NOT NULL REFERENCES delivery_service (id),
+    courier_id          INTEGER REFERENCES couriers (id_courier),
+    delivery_time       TIMESTAMP NOT NULL,
+    customer_address    TEXT      NOT NULL DEFAULT '',
+    status              TEXT      NOT NULL DEFAULT 'ready to delivery',
+    order_date          TIMESTAMP NOT NULL DEFAULT now(),
+    restaurant_address  TEXT      NOT NULL DEFAULT '',
+    restaurant_name     TEXT      NOT NULL DEFAULT '',
+    picked              BOOLEAN   NOT NULL DEFAULT FALSE,
+    id_from_restaurant  INTEGER   NOT NULL DEFAULT 0,
+    customer_name       TEXT      NOT NULL DEFAULT '',
+    customer_phone      TEXT      NOT NULL DEFAULT '',
+    payment_type        INTEGER   NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS couriers_user_id_idx ON couriers (user_id);
+CREATE INDEX IF NOT EXISTS couriers_delivery_service_id_idx ON couriers (delivery_service_id, surname);
+CREATE INDEX IF NOT EXISTS delivery_service_manager_id_idx ON delivery_service (manager_id);
+CREATE INDEX IF NOT EXISTS delivery_courier_status_idx ON delivery (courier_id, status, id);
+CREATE INDEX IF NOT EXISTS delivery_service_status_id_idx ON delivery (delivery_service_id, status, id);
+CREATE INDEX IF NOT EXISTS delivery_service_status_date_idx ON delivery (delivery_service_id, status, order_date, id);
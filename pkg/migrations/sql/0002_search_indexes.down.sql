@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS delivery_search_trgm_idx;
+DROP INDEX IF EXISTS delivery_search_fts_idx;
+DROP INDEX IF EXISTS couriers_search_trgm_idx;
+DROP INDEX IF EXISTS couriers_search_fts_idx;
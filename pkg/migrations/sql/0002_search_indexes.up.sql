@@ -0,0 +1,13 @@
+-- Indexes backing GET /search. The indexed expressions must match
+-- courierDocument and orderDocument in dao/SearchRepository.go.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS couriers_search_fts_idx ON couriers
+    USING gin (to_tsvector('simple', (coalesce(name,'') || ' ' || coalesce(surname,'') || ' ' || coalesce(phone_number,'') || ' ' || coalesce(email,''))));
+CREATE INDEX IF NOT EXISTS couriers_search_trgm_idx ON couriers
+    USING gin ((coalesce(name,'') || ' ' || coalesce(surname,'') || ' ' || coalesce(phone_number,'') || ' ' || coalesce(email,'')) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS delivery_search_fts_idx ON delivery
+    USING gin (to_tsvector('simple', (coalesce(customer_name,'') || ' ' || coalesce(customer_phone,'') || ' ' || coalesce(customer_address,'') || ' ' || coalesce(restaurant_address,'') || ' ' || coalesce(id_from_restaurant::text,''))));
+CREATE INDEX IF NOT EXISTS delivery_search_trgm_idx ON delivery
+    USING gin ((coalesce(customer_name,'') || ' ' || coalesce(customer_phone,'') || ' ' || coalesce(customer_address,'') || ' ' || coalesce(restaurant_address,'') || ' ' || coalesce(id_from_restaurant::text,'')) gin_trgm_ops);
@@ -0,0 +1,4 @@
+ALTER TABLE delivery_service DROP COLUMN IF EXISTS photo_thumbnail;
+ALTER TABLE delivery_service DROP COLUMN IF EXISTS photo_medium;
+ALTER TABLE couriers DROP COLUMN IF EXISTS photo_thumbnail;
+ALTER TABLE couriers DROP COLUMN IF EXISTS photo_medium;
@@ -0,0 +1,5 @@
+-- Resized copies of courier photos and delivery service logos.
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS photo_medium TEXT NOT NULL DEFAULT '';
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS photo_thumbnail TEXT NOT NULL DEFAULT '';
+ALTER TABLE delivery_service ADD COLUMN IF NOT EXISTS photo_medium TEXT NOT NULL DEFAULT '';
+ALTER TABLE delivery_service ADD COLUMN IF NOT EXISTS photo_thumbnail TEXT NOT NULL DEFAULT '';
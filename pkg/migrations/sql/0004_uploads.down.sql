@@ -0,0 +1,2 @@
+ALTER TABLE delivery DROP COLUMN IF EXISTS proof_of_delivery;
+DROP TABLE IF EXISTS uploads;
@@ -0,0 +1,18 @@
+-- Files that clients upload straight to object storage through a presigned
+-- URL. A row is created when the URL is handed out and completed once the
+-- object has been checked and attached to its courier, service or order.
+CREATE TABLE IF NOT EXISTS uploads
+(
+    id           TEXT PRIMARY KEY,
+    kind         TEXT        NOT NULL,
+    target_id    INTEGER     NOT NULL,
+    object_key   TEXT        NOT NULL,
+    content_type TEXT        NOT NULL,
+    user_id      INTEGER     NOT NULL,
+    status       TEXT        NOT NULL DEFAULT 'pending',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ
+);
+
+ALTER TABLE delivery ADD COLUMN IF NOT EXISTS proof_of_delivery TEXT NOT NULL DEFAULT '';
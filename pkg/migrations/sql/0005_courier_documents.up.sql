@@ -0,0 +1,18 @@
+-- Driving licences, ID cards, insurances and medical certificates of couriers.
+-- warned_at remembers that managers were told about the coming expiry, so
+-- the daily job warns once per expiry date.
+CREATE TABLE IF NOT EXISTS courier_documents
+(
+    id          SERIAL PRIMARY KEY,
+    courier_id  INTEGER     NOT NULL REFERENCES couriers (id_courier),
+    type        TEXT        NOT NULL,
+    number      TEXT        NOT NULL,
+    file        TEXT        NOT NULL DEFAULT '',
+    issue_date  DATE        NOT NULL,
+    expiry_date DATE        NOT NULL,
+    warned_at   TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (courier_id, type)
+);
+
+CREATE INDEX IF NOT EXISTS courier_documents_expiry_date_idx ON courier_documents (expiry_date);
@@ -0,0 +1,4 @@
+ALTER TABLE delivery DROP COLUMN IF EXISTS distance_km;
+ALTER TABLE delivery DROP COLUMN IF EXISTS size;
+ALTER TABLE delivery DROP COLUMN IF EXISTS weight_kg;
+DROP TABLE IF EXISTS vehicles;
@@ -0,0 +1,14 @@
+-- The vehicle a courier delivers with, and the load hints of orders that
+-- dispatch checks against it. Zero hints mean "unknown" and fit any vehicle.
+CREATE TABLE IF NOT EXISTS vehicles
+(
+    id          SERIAL PRIMARY KEY,
+    courier_id  INTEGER          NOT NULL UNIQUE REFERENCES couriers (id_courier),
+    type        TEXT             NOT NULL,
+    capacity_kg DOUBLE PRECISION NOT NULL DEFAULT 0,
+    plate       TEXT             NOT NULL DEFAULT ''
+);
+
+ALTER TABLE delivery ADD COLUMN IF NOT EXISTS weight_kg DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE delivery ADD COLUMN IF NOT EXISTS size TEXT NOT NULL DEFAULT '';
+ALTER TABLE delivery ADD COLUMN IF NOT EXISTS distance_km DOUBLE PRECISION NOT NULL DEFAULT 0;
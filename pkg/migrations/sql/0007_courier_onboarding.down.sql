@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS courier_onboarding;
+DROP INDEX IF EXISTS couriers_status_idx;
+ALTER TABLE couriers DROP COLUMN IF EXISTS status_reason;
+ALTER TABLE couriers DROP COLUMN IF EXISTS status;
@@ -0,0 +1,18 @@
+-- Couriers go through onboarding before they can take orders:
+-- applied -> pending_review -> approved | rejected, and a rejected applicant
+-- may submit again. Couriers that existed before onboarding are approved.
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'approved';
+ALTER TABLE couriers ALTER COLUMN status SET DEFAULT 'applied';
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS courier_onboarding
+(
+    id         SERIAL PRIMARY KEY,
+    courier_id INTEGER     NOT NULL REFERENCES couriers (id_courier),
+    status     TEXT        NOT NULL,
+    reason     TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS courier_onboarding_courier_id_idx ON courier_onboarding (courier_id);
+CREATE INDEX IF NOT EXISTS couriers_status_idx ON couriers (status);
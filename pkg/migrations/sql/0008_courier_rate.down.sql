@@ -0,0 +1 @@
+ALTER TABLE delivery_service DROP COLUMN IF EXISTS courier_rate;
@@ -0,0 +1,2 @@
+-- What a delivery service pays its couriers for each completed delivery.
+ALTER TABLE delivery_service ADD COLUMN IF NOT EXISTS courier_rate NUMERIC(10, 2) NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS delivery_version ON delivery;
+DROP TRIGGER IF EXISTS delivery_service_version ON delivery_service;
+DROP TRIGGER IF EXISTS couriers_version ON couriers;
+DROP FUNCTION IF EXISTS bump_version();
+ALTER TABLE delivery DROP COLUMN IF EXISTS version;
+ALTER TABLE delivery_service DROP COLUMN IF EXISTS version;
+ALTER TABLE couriers DROP COLUMN IF EXISTS version;
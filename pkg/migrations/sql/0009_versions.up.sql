@@ -0,0 +1,16 @@
+-- Versions of the rows clients edit, sent as ETags. Every update bumps the
+-- version, so a client holding an older one learns the row has changed.
+ALTER TABLE couriers ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE delivery_service ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE delivery ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER couriers_version BEFORE UPDATE ON couriers FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER delivery_service_version BEFORE UPDATE ON delivery_service FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER delivery_version BEFORE UPDATE ON delivery FOR EACH ROW EXECUTE FUNCTION bump_version();
@@ -0,0 +1,14 @@
+-- Warnings of the daily document job about documents expiring soon. They are
+-- kept for the managers of the courier's delivery service until one of them
+-- acknowledges the warning.
+CREATE TABLE IF NOT EXISTS document_warnings
+(
+    id                  SERIAL PRIMARY KEY,
+    delivery_service_id INTEGER     NOT NULL REFERENCES delivery_service (id),
+    document_id         INTEGER     NOT NULL REFERENCES courier_documents (id) ON DELETE CASCADE,
+    expiry_date         DATE        NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    acknowledged_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS document_warnings_open_idx ON document_warnings (delivery_service_id) WHERE acknowledged_at IS NULL;
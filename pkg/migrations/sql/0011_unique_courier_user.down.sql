@@ -0,0 +1 @@
+DROP INDEX IF EXISTS couriers_user_id_key;
@@ -0,0 +1,5 @@
+-- A user is the courier of one delivery service at most: the tenant of a
+-- Courier is found by their user id. Couriers created before they were
+-- linked to users keep user_id 0 and are left out. Users with several
+-- couriers have to be sorted out by hand before this migration runs.
+CREATE UNIQUE INDEX IF NOT EXISTS couriers_user_id_key ON couriers (user_id) WHERE user_id <> 0;
@@ -0,0 +1,197 @@
+// Package policy decides which user may perform which action. Each action
+// requires a set of permissions; a user holds the permissions of their role
+// plus any the auth service put in the token.
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"strings"
+)
+
+// All is the permission that satisfies every requirement.
+const All = "*"
+
+// Permissions.
+const (
+	ServicesCreate   = "services:create"
+	ServicesRead     = "services:read"
+	ServicesList     = "services:list"
+	ServicesUpdate   = "services:update"
+	CouriersRead     = "couriers:read"
+	CouriersList     = "couriers:list"
+	CouriersRegister = "couriers:register"
+	CouriersUpdate   = "couriers:update"
+	CouriersManage   = "couriers:manage"
+	OrdersRead       = "orders:read"
+	OrdersUpdate     = "orders:update"
+	OrdersManage     = "orders:manage"
+	MetricsRead      = "metrics:read"
+)
+
+// Actions, one for each thing a handler does.
+const (
+	CreateDeliveryService        = "deliveryservice.create"
+	GetDeliveryService           = "deliveryservice.get"
+	ListDeliveryServices         = "deliveryservice.list"
+	UpdateDeliveryService        = "deliveryservice.update"
+	SaveLogo                     = "deliveryservice.logo"
+	ListCouriers                 = "courier.list"
+	GetCourier                   = "courier.get"
+	SaveCourier                  = "courier.create"
+	DeleteCourier                = "courier.delete"
+	UpdateCourier                = "courier.update"
+	SaveCourierPhoto             = "courier.photo"
+	SetCourierReadyToGo          = "courier.ready"
+	SaveCourierDocument          = "courier.documents.save"
+	GetCourierDocuments          = "courier.documents.get"
+	GetExpiringDocuments         = "documents.expiring"
+	GetDocumentWarnings          = "documents.warnings"
+	AcknowledgeDocumentWarning   = "documents.warnings.ack"
+	SaveVehicle                  = "courier.vehicle.save"
+	GetVehicle                   = "courier.vehicle.get"
+	DeleteVehicle                = "courier.vehicle.delete"
+	SubmitCourierApplication     = "courier.application.submit"
+	ReviewCourierApplication     = "courier.application.review"
+	ListCourierApplications      = "courier.application.list"
+	Search                       = "search"
+	ListOrders                   = "order.list"
+	GetOrder                     = "order.get"
+	ChangeOrderStatus            = "order.status"
+	AssignOrder                  = "order.assign"
+	GetOrdersOfServiceForManager = "order.manager"
+	GetEarnings                  = "courier.earnings"
+	CompleteUpload               = "upload.complete"
+	ReadMetrics                  = "debug.vars"
+)
+
+var (
+	ErrForbidden     = apperr.New(apperr.Forbidden, "not enough rights")
+	ErrUnknownAction = errors.New("unknown action")
+)
+
+type Policy struct {
+	// Actions maps each action to the permissions it requires.
+	Actions map[string][]string `json:"actions"`
+	// Roles maps each role to the permissions it grants.
+	Roles map[string][]string `json:"roles"`
+}
+
+// Default is the policy the service ships with.
+func Default() *Policy {
+	return &Policy{
+		Actions: map[string][]string{
+			CreateDeliveryService:        {ServicesCreate},
+			GetDeliveryService:           {ServicesRead},
+			ListDeliveryServices:         {ServicesList},
+			UpdateDeliveryService:        {ServicesUpdate},
+			SaveLogo:                     {ServicesUpdate},
+			ListCouriers:                 {CouriersList},
+			GetCourier:                   {CouriersRead},
+			SaveCourier:                  {CouriersRegister},
+			DeleteCourier:                {CouriersManage},
+			UpdateCourier:                {CouriersUpdate},
+			SaveCourierPhoto:             {CouriersUpdate},
+			SetCourierReadyToGo:          {CouriersUpdate},
+			SaveCourierDocument:          {CouriersUpdate},
+			GetCourierDocuments:          {CouriersRead},
+			GetExpiringDocuments:         {CouriersList},
+			GetDocumentWarnings:          {CouriersList},
+			AcknowledgeDocumentWarning:   {CouriersManage},
+			SaveVehicle:                  {CouriersUpdate},
+			GetVehicle:                   {CouriersRead},
+			DeleteVehicle:                {CouriersUpdate},
+			SubmitCourierApplication:     {CouriersUpdate},
+			ReviewCourierApplication:     {CouriersManage},
+			ListCourierApplications:      {CouriersList},
+			Search:                       {CouriersList},
+			ListOrders:                   {OrdersRead},
+			GetOrder:                     {OrdersRead},
+			ChangeOrderStatus:            {OrdersUpdate},
+			AssignOrder:                  {OrdersUpdate},
+			GetOrdersOfServiceForManager: {OrdersManage},
+			GetEarnings:                  {OrdersRead},
+			// Only the uploader may complete an upload, which creating it
+			// already required the permissions for.
+			CompleteUpload: {},
+			// No role but Superadmin reads the metrics.
+			ReadMetrics: {MetricsRead},
+		},
+		Roles: map[string][]string{
+			"Superadmin": {All},
+			"Courier manager": {ServicesCreate, ServicesRead, ServicesUpdate, CouriersRead, CouriersList, CouriersRegister,
+				CouriersUpdate, CouriersManage, OrdersRead, OrdersUpdate, OrdersManage},
+			"Courier": {ServicesRead, CouriersRead, CouriersRegister, CouriersUpdate, OrdersRead, OrdersUpdate},
+		},
+	}
+}
+
+// Load reads a JSON policy like {"actions": {...}, "roles": {...}}. The
+// actions and roles it lists replace those of the default policy.
+func Load(r io.Reader) (*Policy, error) {
+	var loaded Policy
+	if err := json.NewDecoder(r).Decode(&loaded); err != nil {
+		return nil, fmt.Errorf("policy: %w", err)
+	}
+	p := Default()
+	for action, permissions := range loaded.Actions {
+		p.Actions[action] = permissions
+	}
+	p.WithRoles(loaded.Roles)
+	return p, nil
+}
+
+func LoadFile(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("policy: %w", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// WithRoles replaces the permissions of the given roles, e.g. with those the
+// auth service reports.
+func (p *Policy) WithRoles(roles map[string][]string) *Policy {
+	for role, permissions := range roles {
+		p.Roles[role] = permissions
+	}
+	return p
+}
+
+// Allow checks that a user with the role and the token permissions may
+// perform the action. Roles are matched exactly; unknown actions are denied.
+func (p *Policy) Allow(action, role, permissions string) error {
+	required, ok := p.Actions[action]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownAction, action)
+	}
+	held := make(map[string]bool)
+	for _, permission := range p.Roles[role] {
+		held[permission] = true
+	}
+	for _, permission := range ParsePermissions(permissions) {
+		held[permission] = true
+	}
+	if held[All] {
+		return nil
+	}
+	for _, permission := range required {
+		if !held[permission] {
+			return fmt.Errorf("%w: %s needs %s", ErrForbidden, action, permission)
+		}
+	}
+	return nil
+}
+
+// ParsePermissions splits the permissions of a token, separated by commas
+// or spaces.
+func ParsePermissions(permissions string) []string {
+	return strings.FieldsFunc(permissions, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
@@ -0,0 +1,49 @@
+package policy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestPolicy_Allow(t *testing.T) {
+	p := Default()
+
+	testTable := []struct {
+		name        string
+		action      string
+		role        string
+		permissions string
+		expectedErr error
+	}{
+		{name: "Manager reviews", action: ReviewCourierApplication, role: "Courier manager"},
+		{name: "Courier doesn't review", action: ReviewCourierApplication, role: "Courier", expectedErr: ErrForbidden},
+		{name: "Role is matched exactly", action: GetCourier, role: "Courier man", expectedErr: ErrForbidden},
+		{name: "Superadmin does everything", action: ListDeliveryServices, role: "Superadmin"},
+		{name: "Permission from the token", action: ListDeliveryServices, role: "Courier manager", permissions: "reports:read, services:list"},
+		{name: "Unknown action", action: "courier.fire", role: "Superadmin", expectedErr: ErrUnknownAction},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := p.Allow(testCase.action, testCase.role, testCase.permissions)
+			if testCase.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	p, err := Load(strings.NewReader(`{"actions": {"courier.get": ["couriers:read", "couriers:list"]}, "roles": {"Dispatcher": ["orders:read", "orders:update"]}}`))
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, p.Allow(GetCourier, "Courier", ""), ErrForbidden)
+	assert.NoError(t, p.Allow(GetCourier, "Courier manager", ""))
+	assert.NoError(t, p.Allow(AssignOrder, "Dispatcher", ""))
+	assert.NoError(t, p.Allow(ListOrders, "Courier", ""))
+
+	_, err = Load(strings.NewReader(`{"roles": ["Courier"]}`))
+	assert.Error(t, err)
+}
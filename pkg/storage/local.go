@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore keeps files on the local filesystem. The service serves them
+// itself under the public URL, see controller.GetFile.
+type LocalStore struct {
+	root      string
+	publicURL string
+}
+
+func NewLocalStore(root, publicURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("storage: %w", err)
+	}
+	return &LocalStore{root: root, publicURL: publicURL}, nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	// Write to a temporary file first so readers never see half a file.
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) ([]byte, string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("storage: %w", err)
+	}
+	return data, http.DetectContentType(data), nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("storage: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) URL(key string) string {
+	return publicURL(s.publicURL, key)
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"sync"
+)
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+}
+
+// MemoryStore keeps files in memory. It is meant for tests and local runs.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	objects   map[string]memoryObject
+	publicURL string
+}
+
+func NewMemoryStore(publicURL string) *MemoryStore {
+	return &MemoryStore{objects: make(map[string]memoryObject), publicURL: publicURL}
+}
+
+func (s *MemoryStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = memoryObject{data: append([]byte(nil), data...), contentType: contentType}
+	return nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, "", ErrNotFound
+	}
+	return append([]byte(nil), object.data...), object.contentType, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *MemoryStore) URL(key string) string {
+	return publicURL(s.publicURL, key)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/minio/minio-go"
+	"io/ioutil"
+	"time"
+)
+
+// S3Store works with any S3 compatible storage, DigitalOcean Spaces included.
+type S3Store struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+func NewS3Store(cfg Config) (*S3Store, error) {
+	client, err := minio.New(cfg.Endpoint, cfg.AccessKey, cfg.SecretKey, cfg.UseSSL)
+	if err != nil {
+		return nil, fmt.Errorf("storage: %w", err)
+	}
+	return &S3Store{client: client, bucket: cfg.Bucket, publicURL: cfg.PublicURL}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, err := s.client.PutObjectWithContext(ctx, s.bucket, key, bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{ContentType: contentType, UserMetadata: map[string]string{"x-amz-acl": "public-read"}})
+	if err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, string, error) {
+	object, err := s.client.GetObjectWithContext(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("storage: %w", err)
+	}
+	defer object.Close()
+	info, err := object.Stat()
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, "", ErrNotFound
+		}
+		return nil, "", fmt.Errorf("storage: %w", err)
+	}
+	data, err := ioutil.ReadAll(object)
+	if err != nil {
+		return nil, "", fmt.Errorf("storage: %w", err)
+	}
+	return data, info.ContentType, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(s.bucket, key); err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	return nil
+}
+
+func (s *S3Store) URL(key string) string {
+	return publicURL(s.publicURL, key)
+}
+
+func (s *S3Store) PresignPut(ctx context.Context, key string, expires time.Duration) (string, error) {
+	u, err := s.client.PresignedPutObject(s.bucket, key, expires)
+	if err != nil {
+		return "", fmt.Errorf("storage: %w", err)
+	}
+	return u.String(), nil
+}
+
+func (s *S3Store) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	info, err := s.client.StatObject(s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return ObjectInfo{}, ErrNotFound
+		}
+		return ObjectInfo{}, fmt.Errorf("storage: %w", err)
+	}
+	return ObjectInfo{Size: info.Size, ContentType: info.ContentType}, nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = apperr.New(apperr.NotFound, "object not found")
+
+// BlobStore keeps uploaded files (courier photos, logos) and knows the public
+// URL each of them is served from.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// Presigner is implemented by stores that clients can upload to directly,
+// without sending the file through the service.
+type Presigner interface {
+	PresignPut(ctx context.Context, key string, expires time.Duration) (string, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+}
+
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+type Config struct {
+	Backend   string
+	Endpoint  string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+	PublicURL string
+	LocalDir  string
+}
+
+// ConfigFromEnv reads the storage settings. Without any of them set the
+// service keeps using the DigitalOcean Space it has always used.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Backend:   getEnv("STORAGE_BACKEND", "s3"),
+		Endpoint:  getEnv("STORAGE_ENDPOINT", "fra1.digitaloceanspaces.com"),
+		Bucket:    getEnv("STORAGE_BUCKET", "storage-like-s3"),
+		AccessKey: os.Getenv("ACCESS_KEY"),
+		SecretKey: os.Getenv("SECRET_KEY"),
+		UseSSL:    os.Getenv("STORAGE_USE_SSL") != "false",
+		PublicURL: os.Getenv("STORAGE_PUBLIC_URL"),
+		LocalDir:  getEnv("STORAGE_LOCAL_DIR", "uploads"),
+	}
+	if cfg.PublicURL == "" {
+		switch cfg.Backend {
+		case "s3":
+			cfg.PublicURL = fmt.Sprintf("https://%s.%s", cfg.Bucket, cfg.Endpoint)
+		default:
+			cfg.PublicURL = "/files"
+		}
+	}
+	return cfg
+}
+
+func New(cfg Config) (BlobStore, error) {
+	switch cfg.Backend {
+	case "s3":
+		return NewS3Store(cfg)
+	case "local":
+		return NewLocalStore(cfg.LocalDir, cfg.PublicURL)
+	case "memory":
+		return NewMemoryStore(cfg.PublicURL), nil
+	}
+	return nil, fmt.Errorf("storage: unknown backend %q", cfg.Backend)
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func publicURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
+
+// cleanKey rejects keys that could escape the storage root.
+func cleanKey(key string) (string, error) {
+	key = strings.TrimLeft(key, "/")
+	if key == "" {
+		return "", ErrNotFound
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("storage: invalid key %q", key)
+		}
+	}
+	return key, nil
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBlobStores(t *testing.T) {
+	local, err := NewLocalStore(t.TempDir(), "http://localhost:81/files/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]BlobStore{
+		"local":  local,
+		"memory": NewMemoryStore("http://localhost:81/files/"),
+	}
+	ctx := context.Background()
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, store.Put(ctx, "courier_photo/1", png, "image/png"))
+
+			data, contentType, err := store.Get(ctx, "/courier_photo/1")
+			assert.NoError(t, err)
+			assert.Equal(t, png, data)
+			assert.Equal(t, "image/png", contentType)
+			assert.Equal(t, "http://localhost:81/files/courier_photo/1", store.URL("courier_photo/1"))
+
+			assert.NoError(t, store.Delete(ctx, "courier_photo/1"))
+			_, _, err = store.Get(ctx, "courier_photo/1")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			assert.Error(t, store.Put(ctx, "../etc/passwd", png, "image/png"))
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("STORAGE_BACKEND", "")
+	t.Setenv("STORAGE_PUBLIC_URL", "")
+	t.Setenv("STORAGE_BUCKET", "photos")
+	t.Setenv("STORAGE_ENDPOINT", "ams3.digitaloceanspaces.com")
+	cfg := ConfigFromEnv()
+	assert.Equal(t, "s3", cfg.Backend)
+	assert.Equal(t, "https://photos.ams3.digitaloceanspaces.com", cfg.PublicURL)
+
+	t.Setenv("STORAGE_BACKEND", "local")
+	assert.Equal(t, "/files", ConfigFromEnv().PublicURL)
+}
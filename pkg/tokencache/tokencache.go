@@ -0,0 +1,236 @@
+// Package tokencache keeps the users of validated access tokens for a short
+// while, so that a request doesn't cost a call to the auth service.
+package tokencache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"log"
+	"os"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTTL  = time.Minute
+	DefaultSize = 10000
+)
+
+type Config struct {
+	// TTL is how long a validated token is trusted without asking the auth
+	// service again. Zero or less disables the cache.
+	TTL time.Duration
+	// Size bounds the number of tokens kept; the least recently used ones
+	// are evicted first.
+	Size int
+}
+
+// ConfigFromEnv reads TOKEN_CACHE_TTL (a duration, 1m by default, 0 to
+// disable) and TOKEN_CACHE_SIZE (10000 by default).
+func ConfigFromEnv() Config {
+	cfg := Config{TTL: DefaultTTL, Size: DefaultSize}
+	if ttl, err := time.ParseDuration(os.Getenv("TOKEN_CACHE_TTL")); err == nil {
+		cfg.TTL = ttl
+	}
+	if size, err := strconv.Atoi(os.Getenv("TOKEN_CACHE_SIZE")); err == nil && size > 0 {
+		cfg.Size = size
+	}
+	return cfg
+}
+
+// Stats are the counters of a cache. Shared counts lookups that waited for
+// the same token being validated by another request instead of asking the
+// auth service themselves.
+type Stats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Shared        uint64 `json:"shared"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+	Size          int    `json:"size"`
+}
+
+// ErrFetchPanicked is what lookups that waited for a validation get when it
+// panicked.
+var ErrFetchPanicked = errors.New("tokencache: token validation panicked")
+
+// Fetch validates a token with the auth service. The cache calls it with a
+// context of its own, which no lookup cancels, so it has to bound the call
+// itself.
+type Fetch func(ctx context.Context, token string) (*authProto.UserRole, error)
+
+type entry struct {
+	token   string
+	user    *authProto.UserRole
+	expires time.Time
+}
+
+type call struct {
+	done chan struct{}
+	user *authProto.UserRole
+	err  error
+	// seq orders the call against InvalidateUser: a call started before the
+	// user was invalidated may carry the user's old role.
+	seq uint64
+	// forgotten is set when the token is invalidated while it is validated,
+	// so that the result isn't cached.
+	forgotten bool
+}
+
+// Cache is a bounded TTL cache of token → user. It is safe for concurrent
+// use; concurrent lookups of the same token share one call to the auth
+// service.
+type Cache struct {
+	cfg   Config
+	now   func() time.Time
+	mu    sync.Mutex
+	lru   *list.List
+	items map[string]*list.Element
+	users map[int32]map[string]struct{}
+	calls map[string]*call
+	// seq numbers the calls. The user of a call is only known once it
+	// returns, so InvalidateUser remembers the seq of the next call in
+	// invalidated, for as long as calls are in flight, and a call of the
+	// user numbered below it isn't cached.
+	seq         uint64
+	invalidated map[int32]uint64
+	stats       Stats
+}
+
+func New(cfg Config) *Cache {
+	if cfg.Size <= 0 {
+		cfg.Size = DefaultSize
+	}
+	return &Cache{
+		cfg:         cfg,
+		now:         time.Now,
+		lru:         list.New(),
+		items:       make(map[string]*list.Element),
+		users:       make(map[int32]map[string]struct{}),
+		calls:       make(map[string]*call),
+		invalidated: make(map[int32]uint64),
+	}
+}
+
+// Get returns the user of the token, from the cache or from fetch. Failed
+// validations aren't cached. The auth service is asked apart from the
+// lookups, so one that gives up when its context is done doesn't fail the
+// others waiting for the same token.
+func (c *Cache) Get(ctx context.Context, token string, fetch Fetch) (*authProto.UserRole, error) {
+	if c.cfg.TTL <= 0 {
+		return fetch(ctx, token)
+	}
+	c.mu.Lock()
+	if el, ok := c.items[token]; ok {
+		e := el.Value.(*entry)
+		if c.now().Before(e.expires) {
+			c.lru.MoveToFront(el)
+			c.stats.Hits++
+			c.mu.Unlock()
+			return e.user, nil
+		}
+		c.remove(el)
+	}
+	cl, ok := c.calls[token]
+	if ok {
+		c.stats.Shared++
+	} else {
+		c.stats.Misses++
+		cl = &call{done: make(chan struct{}), seq: c.seq, err: ErrFetchPanicked}
+		c.seq++
+		c.calls[token] = cl
+		go c.fetch(token, cl, fetch)
+	}
+	c.mu.Unlock()
+	select {
+	case <-cl.done:
+		return cl.user, cl.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Cache) fetch(token string, cl *call, fetch Fetch) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("tokencache: validating a token panicked: %v", p)
+		}
+		c.mu.Lock()
+		delete(c.calls, token)
+		if cl.err == nil && !cl.forgotten && cl.seq >= c.invalidated[cl.user.GetUserId()] {
+			c.add(token, cl.user)
+		}
+		if len(c.calls) == 0 && len(c.invalidated) > 0 {
+			c.invalidated = make(map[int32]uint64)
+		}
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+	cl.user, cl.err = fetch(context.Background(), token)
+}
+
+// Invalidate drops the token, e.g. when the user logs out.
+func (c *Cache) Invalidate(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cl, ok := c.calls[token]; ok {
+		cl.forgotten = true
+	}
+	if el, ok := c.items[token]; ok {
+		c.remove(el)
+		c.stats.Invalidations++
+	}
+}
+
+// InvalidateUser drops every token of the user, e.g. when their role changes
+// or they log out everywhere. Tokens of the user being validated meanwhile
+// aren't cached either.
+func (c *Cache) InvalidateUser(userId int32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.calls) > 0 {
+		c.invalidated[userId] = c.seq
+	}
+	for token := range c.users[userId] {
+		if el, ok := c.items[token]; ok {
+			c.remove(el)
+			c.stats.Invalidations++
+		}
+	}
+}
+
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = c.lru.Len()
+	return stats
+}
+
+func (c *Cache) add(token string, user *authProto.UserRole) {
+	if el, ok := c.items[token]; ok {
+		c.remove(el)
+	}
+	c.items[token] = c.lru.PushFront(&entry{token: token, user: user, expires: c.now().Add(c.cfg.TTL)})
+	if c.users[user.GetUserId()] == nil {
+		c.users[user.GetUserId()] = make(map[string]struct{})
+	}
+	c.users[user.GetUserId()][token] = struct{}{}
+	for c.lru.Len() > c.cfg.Size {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*entry)
+	delete(c.items, e.token)
+	tokens := c.users[e.user.GetUserId()]
+	delete(tokens, e.token)
+	if len(tokens) == 0 {
+		delete(c.users, e.user.GetUserId())
+	}
+}
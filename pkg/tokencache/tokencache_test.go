@@ -0,0 +1,225 @@
+package tokencache
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func countingFetch(calls *int32) Fetch {
+	return func(ctx context.Context, token string) (*authProto.UserRole, error) {
+		atomic.AddInt32(calls, 1)
+		return &authProto.UserRole{UserId: int32(len(token)), Role: "Courier"}, nil
+	}
+}
+
+func TestCache_HitsUntilExpired(t *testing.T) {
+	var calls int32
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := New(Config{TTL: time.Minute, Size: 10})
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		user, err := c.Get(context.Background(), "abc", countingFetch(&calls))
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), user.GetUserId())
+	}
+	assert.Equal(t, int32(1), calls)
+
+	now = now.Add(time.Minute)
+	_, err := c.Get(context.Background(), "abc", countingFetch(&calls))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls)
+	assert.Equal(t, Stats{Hits: 2, Misses: 2, Size: 1}, c.Stats())
+}
+
+func TestCache_DoesNotCacheErrors(t *testing.T) {
+	c := New(Config{TTL: time.Minute})
+	var calls int32
+	fail := func(ctx context.Context, token string) (*authProto.UserRole, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("invalid token")
+	}
+	_, err := c.Get(context.Background(), "abc", fail)
+	assert.Error(t, err)
+	_, err = c.Get(context.Background(), "abc", fail)
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestCache_SharesConcurrentLookups(t *testing.T) {
+	c := New(Config{TTL: time.Minute})
+	var calls int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context, token string) (*authProto.UserRole, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return &authProto.UserRole{UserId: 1}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := c.Get(context.Background(), "abc", fetch)
+			assert.NoError(t, err)
+			assert.Equal(t, int32(1), user.GetUserId())
+		}()
+	}
+	for c.Stats().Misses+c.Stats().Shared < 10 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls)
+	assert.Equal(t, uint64(9), c.Stats().Shared)
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	var calls int32
+	c := New(Config{TTL: time.Minute, Size: 2})
+	for _, token := range []string{"a", "bb", "a", "ccc"} {
+		_, err := c.Get(context.Background(), token, countingFetch(&calls))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, uint64(1), c.Stats().Evictions)
+
+	_, _ = c.Get(context.Background(), "a", countingFetch(&calls))
+	_, _ = c.Get(context.Background(), "bb", countingFetch(&calls))
+	assert.Equal(t, int32(4), calls)
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	var calls int32
+	c := New(Config{TTL: time.Minute})
+	for _, token := range []string{"abc", "xyz", "a"} {
+		_, _ = c.Get(context.Background(), token, countingFetch(&calls))
+	}
+
+	c.Invalidate("a")
+	c.InvalidateUser(3)
+	assert.Equal(t, Stats{Misses: 3, Invalidations: 3}, c.Stats())
+
+	_, _ = c.Get(context.Background(), "abc", countingFetch(&calls))
+	assert.Equal(t, int32(4), calls)
+}
+
+func TestCache_InvalidateUserWhileValidated(t *testing.T) {
+	c := New(Config{TTL: time.Minute})
+	var calls int32
+	started, release := make(chan struct{}), make(chan struct{})
+	fetch := func(ctx context.Context, token string) (*authProto.UserRole, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-release
+			return &authProto.UserRole{UserId: 7, Role: "Courier manager"}, nil
+		}
+		return &authProto.UserRole{UserId: 7, Role: "Courier"}, nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		user, err := c.Get(context.Background(), "abc", fetch)
+		assert.NoError(t, err)
+		assert.Equal(t, "Courier manager", user.GetRole())
+	}()
+	<-started
+	c.InvalidateUser(7)
+	close(release)
+	<-done
+
+	user, err := c.Get(context.Background(), "abc", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, "Courier", user.GetRole(), "the role read before the invalidation isn't cached")
+	assert.Equal(t, int32(2), calls)
+
+	_, _ = c.Get(context.Background(), "abc", fetch)
+	assert.Equal(t, int32(2), calls, "a validation started after the invalidation is cached")
+}
+
+func TestCache_FirstLookupCanceled(t *testing.T) {
+	c := New(Config{TTL: time.Minute})
+	started, release := make(chan struct{}), make(chan struct{})
+	fetch := func(ctx context.Context, token string) (*authProto.UserRole, error) {
+		close(started)
+		select {
+		case <-release:
+			return &authProto.UserRole{UserId: 3, Role: "Courier"}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := c.Get(ctx, "abc", fetch)
+		first <- err
+	}()
+	<-started
+	waited := make(chan *authProto.UserRole)
+	go func() {
+		user, err := c.Get(context.Background(), "abc", countingFetch(new(int32)))
+		assert.NoError(t, err)
+		waited <- user
+	}()
+	for c.Stats().Shared == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+	close(release)
+
+	assert.Equal(t, int32(3), (<-waited).GetUserId(), "the waiter still gets the user")
+	assert.Equal(t, 1, c.Stats().Size)
+}
+
+func TestCache_FetchPanics(t *testing.T) {
+	c := New(Config{TTL: time.Minute})
+	started, release := make(chan struct{}), make(chan struct{})
+	fetch := func(ctx context.Context, token string) (*authProto.UserRole, error) {
+		close(started)
+		<-release
+		panic("boom")
+	}
+
+	first := make(chan error)
+	go func() {
+		_, err := c.Get(context.Background(), "abc", fetch)
+		first <- err
+	}()
+	<-started
+	waited := make(chan error)
+	go func() {
+		_, err := c.Get(context.Background(), "abc", countingFetch(new(int32)))
+		waited <- err
+	}()
+	for c.Stats().Shared == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	assert.ErrorIs(t, <-first, ErrFetchPanicked)
+	assert.ErrorIs(t, <-waited, ErrFetchPanicked)
+	var calls int32
+	user, err := c.Get(context.Background(), "abc", countingFetch(&calls))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), user.GetUserId())
+	assert.Equal(t, int32(1), calls, "the token is validated again")
+}
+
+func TestCache_Disabled(t *testing.T) {
+	var calls int32
+	c := New(Config{})
+	_, _ = c.Get(context.Background(), "abc", countingFetch(&calls))
+	_, _ = c.Get(context.Background(), "abc", countingFetch(&calls))
+	assert.Equal(t, int32(2), calls)
+}
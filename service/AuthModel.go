@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"log"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC/grpcClient"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/tokencache"
+	"time"
+)
+
+const (
+	RoleCourier        = "Courier"
+	RoleCourierManager = "Courier manager"
+)
+
+// authTimeout bounds a call to the auth service made while a request waits.
+const authTimeout = 5 * time.Second
+
+// compensationTimeout bounds undoing a change the auth service didn't follow.
+const compensationTimeout = 5 * time.Second
+
+var (
+	ErrUserRequired   = apperr.New(apperr.Validation, "user_id is required")
+	ErrRoleNotGranted = apperr.New(apperr.BadGateway, "auth service didn't grant the role")
+	ErrUserHasRole    = apperr.New(apperr.Conflict, "user already has another role")
+	// ErrAuthUnavailable means the auth service couldn't be asked, so the
+	// token is neither valid nor invalid.
+	ErrAuthUnavailable = grpcClient.ErrUnavailable
+)
+
+// ParseToken returns the user of a valid access token. Validated tokens are
+// cached for a while, and concurrent requests with the same token share one
+// call to the auth service.
+func (s *CourierService) ParseToken(ctx context.Context, token string) (*authProto.UserRole, error) {
+	return s.tokens.Get(ctx, token, func(ctx context.Context, token string) (*authProto.UserRole, error) {
+		ctx, cancel := context.WithTimeout(ctx, authTimeout)
+		defer cancel()
+		return s.grpcCli.GetUserWithRights(ctx, &authProto.AccessToken{AccessToken: token})
+	})
+}
+
+// InvalidateToken makes the next request with the token ask the auth service
+// again.
+func (s *CourierService) InvalidateToken(token string) {
+	s.tokens.Invalidate(token)
+}
+
+// InvalidateUserTokens forgets every cached token of the user.
+func (s *CourierService) InvalidateUserTokens(userId int) {
+	s.tokens.InvalidateUser(int32(userId))
+}
+
+func (s *CourierService) TokenCacheStats() tokencache.Stats {
+	return s.tokens.Stats()
+}
+
+// AuthHealth reports the state of the connection to the auth service.
+func (s *CourierService) AuthHealth() grpcClient.Health {
+	return s.grpcCli.Health()
+}
+
+// bindRole grants the role to the user in the auth service.
+func (s *CourierService) bindRole(ctx context.Context, userId int, role string) error {
+	ctx, cancel := context.WithTimeout(ctx, authTimeout)
+	defer cancel()
+	res, err := s.grpcCli.BindUserAndRole(ctx, &authProto.User{UserId: int32(userId), Role: role})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrRoleNotGranted, err)
+	}
+	if !res.GetResult() {
+		return fmt.Errorf("%w: binding of user %d to %q refused", ErrRoleNotGranted, userId, role)
+	}
+	// Cached tokens still carry the old role.
+	s.tokens.InvalidateUser(int32(userId))
+	return nil
+}
+
+// GetRolePermissions asks the auth service for its roles and the permissions
+// each one grants. The roles come as a JSON object like
+// {"Courier": ["orders:read", "orders:update"]}.
+func (s *CourierService) GetRolePermissions(ctx context.Context) (map[string][]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, authTimeout)
+	defer cancel()
+	res, err := s.grpcCli.GetAllRoles(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("Error in AuthService: %w", err)
+	}
+	var roles map[string][]string
+	if err := json.Unmarshal([]byte(res.GetRoles()), &roles); err != nil {
+		return nil, fmt.Errorf("Error in AuthService: roles are not a JSON object of permission lists: %s", err)
+	}
+	return roles, nil
+}
+
+// compensate undoes a local change after the auth service refused the role.
+// A failed compensation leaves a row without its role behind, so it is logged
+// loudly for an operator to clean up. It doesn't run on the context of the
+// request: a request that went away is often why the role wasn't granted.
+func compensate(what string, undo func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), compensationTimeout)
+	defer cancel()
+	if err := undo(ctx); err != nil {
+		log.Printf("COMPENSATION FAILED: %s is left without its role in the auth service: %s", what, err)
+	}
+}
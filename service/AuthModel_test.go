@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC/grpcClient"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"testing"
+)
+
+type fakeAuth struct {
+	authProto.AuthClient
+	bound  []*authProto.User
+	result bool
+	err    error
+}
+
+func (f *fakeAuth) BindUserAndRole(ctx context.Context, in *authProto.User, opts ...grpc.CallOption) (*authProto.ResultBinding, error) {
+	f.bound = append(f.bound, in)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &authProto.ResultBinding{Result: f.result}, nil
+}
+
+func expectNoCourier(mock sqlmock.Sqlmock, userId int) {
+	mock.ExpectQuery(`Select id_courier,(.+) from couriers where user_id = \$1`).
+		WithArgs(userId).
+		WillReturnRows(sqlmock.NewRows([]string{"id_courier"}))
+	mock.ExpectQuery(`SELECT id, (.+) FROM delivery_service Where manager_id=\$1`).
+		WithArgs(userId).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+func TestCourierService_SaveCourier(t *testing.T) {
+	testTable := []struct {
+		name        string
+		auth        *fakeAuth
+		expectedErr error
+	}{
+		{name: "OK", auth: &fakeAuth{result: true}},
+		{name: "Binding refused", auth: &fakeAuth{result: false}, expectedErr: ErrRoleNotGranted},
+		{name: "Auth service down", auth: &fakeAuth{err: errors.New("connection refused")}, expectedErr: ErrRoleNotGranted},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			s := NewProjectService(*dao.NewRepository(db, dao.Config{}), grpcClient.New(testCase.auth), nil)
+
+			expectNoCourier(mock, 9)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO "couriers"`).WillReturnRows(sqlmock.NewRows([]string{"id_courier"}).AddRow(5))
+			mock.ExpectExec(`INSERT INTO courier_onboarding`).WithArgs(5, CourierApplied).WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+			if testCase.expectedErr != nil {
+				mock.ExpectBegin()
+				mock.ExpectExec(`DELETE FROM courier_onboarding WHERE courier_id = \$1`).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM couriers WHERE id_courier = \$1`).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+
+			courier, err := s.SaveCourier(context.Background(), &dao.Courier{UserId: 9, CourierName: "Ivan", Surname: "Petrov"})
+
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+				assert.Nil(t, courier)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint16(5), courier.Id)
+			}
+			assert.Equal(t, []*authProto.User{{UserId: 9, Role: RoleCourier}}, testCase.auth.bound)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCourierService_SaveCourier_NoUser(t *testing.T) {
+	auth := &fakeAuth{result: true}
+	s := NewProjectService(dao.Repository{}, grpcClient.New(auth), nil)
+
+	_, err := s.SaveCourier(context.Background(), &dao.Courier{CourierName: "Ivan"})
+
+	assert.ErrorIs(t, err, ErrUserRequired)
+	assert.Empty(t, auth.bound)
+}
+
+func TestCourierService_SaveCourier_UserTaken(t *testing.T) {
+	testTable := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		expectedErr  error
+	}{
+		{
+			name: "Already a courier",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`Select id_courier,(.+) from couriers where user_id = \$1`).
+					WithArgs(9).
+					WillReturnRows(sqlmock.NewRows([]string{"id_courier", "name", "phone_number", "photo", "photo_medium", "photo_thumbnail", "surname",
+						"deleted", "email", "delivery_service_id", "status", "status_reason", "version"}).
+						AddRow(4, "Ivan", "", "", "", "", "Petrov", false, "", 1, CourierApproved, "", 1))
+			},
+			expectedErr: ErrCourierExists,
+		},
+		{
+			name: "Manager of a delivery service",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`Select id_courier,(.+) from couriers where user_id = \$1`).
+					WithArgs(9).
+					WillReturnRows(sqlmock.NewRows([]string{"id_courier"}))
+				mock.ExpectQuery(`SELECT id, (.+) FROM delivery_service Where manager_id=\$1`).
+					WithArgs(9).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "photo", "photo_medium", "photo_thumbnail", "description",
+						"phone_number", "manager_id", "status", "courier_rate", "version"}).
+						AddRow(3, "Fast", "", "", "", "", "", "", 9, "active", 0, 1))
+			},
+			expectedErr: ErrUserHasRole,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			auth := &fakeAuth{result: true}
+			s := NewProjectService(*dao.NewRepository(db, dao.Config{}), grpcClient.New(auth), nil)
+			testCase.mockBehavior(mock)
+
+			// A manager registers a user that already has a role.
+			_, err = s.SaveCourier(context.Background(), &dao.Courier{UserId: 9, CourierName: "Ivan", DeliveryServiceId: 2})
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.Empty(t, auth.bound, "the user's role is left alone")
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
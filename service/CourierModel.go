@@ -1,148 +1,211 @@
 package service
 
 import (
-	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
-	"github.com/minio/minio-go"
 	"log"
-	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC/grpcClient"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/storage"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/tokencache"
+)
 
-	"strconv"
-	"strings"
+var (
+	// ErrCourierChanged is a write made against a version of the courier
+	// that has changed since.
+	ErrCourierChanged = apperr.New(apperr.PreconditionFailed, "courier was changed since it was read")
+	ErrCourierExists  = apperr.New(apperr.Conflict, "user already has a courier")
 )
 
 type CourierService struct {
 	repo    dao.Repository
 	grpcCli *grpcClient.GRPCClient
+	storage storage.BlobStore
+	tokens  *tokencache.Cache
 }
 
-func NewProjectService(repo dao.Repository, grpcCli *grpcClient.GRPCClient) *CourierService {
+func NewProjectService(repo dao.Repository, grpcCli *grpcClient.GRPCClient, store storage.BlobStore) *CourierService {
 	return &CourierService{
 		repo:    repo,
 		grpcCli: grpcCli,
+		storage: store,
+		tokens:  tokencache.New(tokencache.ConfigFromEnv()),
 	}
 }
 
-func (s *CourierService) GetCouriers() ([]dao.SmallInfo, error) {
-	get, err := s.repo.GetCouriersFromDB()
-	if get == nil {
-		return []dao.SmallInfo{}, fmt.Errorf("Error in CourierService: %s", err)
-	}
+// GetCouriers lists the couriers of the delivery service, or of all delivery
+// services when idService is 0.
+func (s *CourierService) GetCouriers(ctx context.Context, idService int) ([]dao.SmallInfo, error) {
+	get, err := s.repo.GetCouriersFromDB(ctx, idService)
 	if err != nil {
-		return nil, fmt.Errorf("Error with database: %s", err)
+		return nil, fmt.Errorf("Error with database: %w", err)
+	}
+	if get == nil {
+		return []dao.SmallInfo{}, fmt.Errorf("Error in CourierService: %w", ErrCourierNotFound)
 	}
 	return get, nil
 }
 
-func (s *CourierService) GetCourier(id int) (dao.Courier, error) {
-	get, err := s.repo.GetCourierFromDB(id)
-	if (get == dao.Courier{}) {
-		return dao.Courier{}, fmt.Errorf("Error in CourierService: %s", err)
+func (s *CourierService) GetCourier(ctx context.Context, id int) (dao.Courier, error) {
+	get, err := s.repo.GetCourierFromDB(ctx, id)
+	if err != nil {
+		return dao.Courier{}, fmt.Errorf("Error with database: %w", err)
+	}
+	if get.Id == 0 {
+		return dao.Courier{}, fmt.Errorf("Error in CourierService: %w", ErrCourierNotFound)
 	}
 	if id == 0 {
-		err := errors.New("no id")
+		err := apperr.New(apperr.Validation, "no id")
 		log.Println("id cannot be zero")
-		return dao.Courier{}, fmt.Errorf("Error in CourierService: %s", err)
+		return dao.Courier{}, fmt.Errorf("Error in CourierService: %w", err)
 	}
 	if get.Deleted == true {
-		err := errors.New("account deleted")
+		err := apperr.New(apperr.NotFound, "account deleted")
 		log.Println("account deleted")
-		return dao.Courier{}, fmt.Errorf("Error in CourierService: %s", err)
+		return dao.Courier{}, fmt.Errorf("Error in CourierService: %w", err)
+	}
+	get.Onboarding, err = s.repo.GetOnboardingOfCourierFromDB(ctx, int(get.Id))
+	if err != nil {
+		return dao.Courier{}, fmt.Errorf("Error in CourierService: %w", err)
 	}
 	return get, nil
 }
 
-func (s *CourierService) SaveCourier(courier *dao.Courier) (*dao.Courier, error) {
-	err := s.repo.SaveCourierInDB(courier)
+// SaveCourier registers an applicant and grants the user the Courier role in
+// the auth service; the courier takes orders once the application is
+// approved. A user has one courier at most, and a user who manages a
+// delivery service can't be made a courier. If the role can't be granted the
+// courier is removed again.
+func (s *CourierService) SaveCourier(ctx context.Context, courier *dao.Courier) (*dao.Courier, error) {
+	if courier.UserId <= 0 {
+		return nil, fmt.Errorf("Error in CourierService: %w", ErrUserRequired)
+	}
+	existing, err := s.repo.GetCourierFromDB(ctx, courier.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("Error in CourierService: %w", err)
+	}
+	if existing.Id != 0 {
+		return nil, fmt.Errorf("Error in CourierService: %w", ErrCourierExists)
+	}
+	managed, err := s.repo.GetDeliveryServiceByIdFromDB(ctx, courier.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("Error in CourierService: %w", err)
+	}
+	if managed.Id != 0 {
+		return nil, fmt.Errorf("Error in CourierService: user %d manages a delivery service: %w", courier.UserId, ErrUserHasRole)
+	}
+	courier.Status = CourierApplied
+	courier.StatusReason = ""
+	courier.ReadyToGo = false
+	err = s.repo.SaveCourierInDB(ctx, courier)
+	if errors.Is(err, dao.ErrUserHasCourier) {
+		return nil, fmt.Errorf("Error in CourierService: %w", ErrCourierExists)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
+	if err := s.bindRole(ctx, courier.UserId, RoleCourier); err != nil {
+		log.Println(err)
+		compensate(fmt.Sprintf("courier %d", courier.Id), func(ctx context.Context) error {
+			return s.repo.DeleteCourierFromDB(ctx, int(courier.Id))
+		})
+		return nil, fmt.Errorf("Error in CourierService: %w", err)
+	}
 	return courier, nil
 }
 
-func (s *CourierService) UpdateCourier(id uint16, status bool) (uint16, error) {
-	courierId, err := s.repo.CourierRep.UpdateCourierInDB(id, status)
+func (s *CourierService) UpdateCourier(ctx context.Context, id uint16, status bool) (uint16, error) {
+	courierId, err := s.repo.CourierRep.UpdateCourierInDB(ctx, id, status)
 	if err != nil {
-		return 0, fmt.Errorf("Error with database: %s", err)
+		return 0, fmt.Errorf("Error with database: %w", err)
 	}
 	return courierId, nil
 }
 
-func (s *CourierService) NewUpdateCourier(courier dao.Courier) error {
-	err := s.repo.UpdateCourierDB(courier)
+func (s *CourierService) NewUpdateCourier(ctx context.Context, courier dao.Courier) error {
+	err := s.repo.UpdateCourierDB(ctx, courier)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in CourierService: %w", ErrCourierNotFound)
+	}
+	if errors.Is(err, dao.ErrVersionMismatch) {
+		return fmt.Errorf("Error in CourierService: %w", ErrCourierChanged)
+	}
 	if err != nil {
-		return fmt.Errorf("Error with database: %s", err)
+		return fmt.Errorf("Error with database: %w", err)
 	}
 	return nil
 }
 
-func (s *CourierService) SaveCourierPhoto(cover []byte, id int) error {
-	client, err := InitClientDO()
+// PatchCourier applies a merge patch to the courier. Surname, phone number
+// and email can be cleared; name, delivery service and deleted can't.
+func (s *CourierService) PatchCourier(ctx context.Context, id int, patch dao.CourierPatch) (dao.Courier, error) {
+	var fields []apperr.FieldError
+	if patch.CourierName.Set && patch.CourierName.Value == "" {
+		fields = append(fields, apperr.FieldError{Field: "courier_name", Message: "can't be empty"})
+	}
+	if patch.DeliveryServiceId.Set && patch.DeliveryServiceId.Value <= 0 {
+		fields = append(fields, apperr.FieldError{Field: "delivery_service_id", Message: "couriers always belong to a delivery service"})
+	}
+	if patch.Deleted.Null {
+		fields = append(fields, apperr.FieldError{Field: "deleted", Message: "can't be null"})
+	}
+	if len(fields) > 0 {
+		return dao.Courier{}, fmt.Errorf("Error in CourierService: %w", apperr.Invalid(fields...))
+	}
+	courier, err := s.repo.PatchCourierInDB(ctx, id, patch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return dao.Courier{}, fmt.Errorf("Error in CourierService: %w", ErrCourierNotFound)
+	}
+	if errors.Is(err, dao.ErrVersionMismatch) {
+		return dao.Courier{}, fmt.Errorf("Error in CourierService: %w", ErrCourierChanged)
+	}
+	if err != nil {
+		return dao.Courier{}, fmt.Errorf("Error with database: %w", err)
+	}
+	return courier, nil
+}
+
+func (s *CourierService) SaveCourierPhoto(ctx context.Context, cover []byte, id int) error {
+	urls, err := s.saveImage(ctx, courierPhotoKey(id), cover)
 	if err != nil {
 		log.Println(err)
-		return err
-	}
-	_, err1 := client.PutObject("storage-like-s3", fmt.Sprintf("courier_photo/%s", strconv.Itoa(id)),
-		bytes.NewReader(cover), int64(len(cover)), minio.PutObjectOptions{ContentType: "image/jpeg", UserMetadata: map[string]string{"x-amz-acl": "public-read"}})
-	if err1 != nil {
-		log.Println(err1)
-		return err1
+		return fmt.Errorf("Error in CourierService: %w", err)
 	}
+	return s.setCourierPhoto(ctx, id, urls)
+}
+
+// setCourierPhoto points the courier at an image saveImage has stored.
+func (s *CourierService) setCourierPhoto(ctx context.Context, id int, urls imageURLs) error {
 	var courier dao.Courier
 	courier.Id = uint16(id)
-	courier.Photo = "https://storage-like-s3.fra1.digitaloceanspaces.com/courier_photo/" + strconv.Itoa(id)
+	courier.Photo = urls.Original
+	courier.PhotoMedium = urls.Medium
+	courier.PhotoThumbnail = urls.Thumbnail
 
-	if err := s.repo.UpdateCourierDB(courier); err != nil {
+	if err := s.repo.UpdateCourierDB(ctx, courier); err != nil {
 		log.Println(err)
-		return fmt.Errorf("Error in DeliveryService: %s", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("Error in CourierService: %w", ErrCourierNotFound)
+		}
+		return fmt.Errorf("Error in DeliveryService: %w", err)
 	}
 
-	log.Println("Uploaded logo with link https://storage-like-s3.fra1.digitaloceanspaces.com/courier_photo/" + strconv.Itoa(id))
+	log.Println("Uploaded photo with link " + courier.Photo)
 	return nil
 }
 
-func (s *CourierService) GetCouriersOfCourierService(limit, page, idService int) ([]dao.Courier, error) {
+func (s *CourierService) GetCouriersOfCourierService(ctx context.Context, limit, page, idService int) ([]dao.Courier, dao.Pagination, error) {
 	var Couriers = []dao.Courier{}
-	Couriers, totalCount := s.repo.GetCouriersOfCourierServiceFromDB(limit, page, idService)
-	LimitOfPages := (totalCount / limit) + 1
-	if LimitOfPages < page {
-		err := errors.New("no page")
-		log.Println("no more pages")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
-	}
-	return Couriers, nil
-}
-
-func (s *CourierService) ParseToken(token string) (*authProto.UserRole, error) {
-	return s.grpcCli.GetUserWithRights(context.Background(), &authProto.AccessToken{AccessToken: token})
-}
-
-func (s *CourierService) CheckRole(neededRoles []string, givenRole string) error {
-	neededRolesString := strings.Join(neededRoles, ",")
-	if !strings.Contains(neededRolesString, givenRole) {
-		return fmt.Errorf("not enough rights")
+	Couriers, totalCount, err := s.repo.GetCouriersOfCourierServiceFromDB(ctx, limit, page, idService)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in CourierService: %w", dbError(ctx, err))
 	}
-	return nil
-}
-
-func (s *CourierService) CheckRights(neededPerms []string, givenPerms string) error {
-	if neededPerms != nil {
-		ok := true
-		for _, perm := range neededPerms {
-			if !strings.Contains(givenPerms, perm) {
-				ok = false
-				return fmt.Errorf("not enough rights")
-			} else {
-				continue
-			}
-		}
-		if ok == true {
-			return nil
-		}
+	pagination, err := paginate(limit, page, totalCount)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
 	}
-	return nil
+	return Couriers, pagination, nil
 }
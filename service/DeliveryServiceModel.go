@@ -1,60 +1,79 @@
 package service
 
 import (
-	"bytes"
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
-	"github.com/minio/minio-go"
 	"log"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
-	"strconv"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
 )
 
-func (s *CourierService) CreateDeliveryService(DeliveryService dao.DeliveryService) (int, error) {
-	id, err := s.repo.SaveDeliveryServiceInDB(&DeliveryService)
+var (
+	ErrDeliveryServiceNotFound = apperr.New(apperr.NotFound, "delivery service not found")
+	// ErrDeliveryServiceChanged is a write made against a version of the
+	// delivery service that has changed since.
+	ErrDeliveryServiceChanged = apperr.New(apperr.PreconditionFailed, "delivery service was changed since it was read")
+)
+
+// CreateDeliveryService grants the manager, if there is one, the Courier
+// manager role in the auth service. If the role can't be granted the delivery
+// service is removed again.
+func (s *CourierService) CreateDeliveryService(ctx context.Context, DeliveryService dao.DeliveryService) (int, error) {
+	id, err := s.repo.SaveDeliveryServiceInDB(ctx, &DeliveryService)
 	if err != nil {
 		log.Println(err)
-		return 0, fmt.Errorf("Error in DeliveryServiceService: %s", err)
+		return 0, fmt.Errorf("Error in DeliveryServiceService: %w", err)
+	}
+	if DeliveryService.ManagerId > 0 {
+		if err := s.bindRole(ctx, DeliveryService.ManagerId, RoleCourierManager); err != nil {
+			log.Println(err)
+			compensate(fmt.Sprintf("delivery service %d", id), func(ctx context.Context) error {
+				return s.repo.DeleteDeliveryServiceFromDB(ctx, id)
+			})
+			return 0, fmt.Errorf("Error in DeliveryServiceService: %w", err)
+		}
 	}
 	return id, nil
 }
 
-func (s *CourierService) GetDeliveryServiceById(Id int) (*dao.DeliveryService, error) {
+func (s *CourierService) GetDeliveryServiceById(ctx context.Context, Id int) (*dao.DeliveryService, error) {
 	var service *dao.DeliveryService
-	service, err := s.repo.GetDeliveryServiceByIdFromDB(Id)
+	service, err := s.repo.GetDeliveryServiceByIdFromDB(ctx, Id)
 	if err != nil {
 		log.Println(err)
-		return nil, fmt.Errorf("Error in DeliveryService: %s", err)
+		return nil, fmt.Errorf("Error in DeliveryService: %w", err)
 	}
-	service.NumOfCouriers, err = s.repo.GetNumberCouriersByServiceFromDB(Id)
+	service.NumOfCouriers, err = s.repo.GetNumberCouriersByServiceFromDB(ctx, Id)
 	if err != nil {
 		log.Println(err)
-		return nil, fmt.Errorf("Error in DeliveryService: %s", err)
+		return nil, fmt.Errorf("Error in DeliveryService: %w", err)
 	}
 	if service.Id == 0 {
-		err = errors.New("not found")
+		err = apperr.New(apperr.NotFound, "not found")
 		log.Println(err)
-		return nil, fmt.Errorf("Error in DeliveryService: %s", err)
+		return nil, fmt.Errorf("Error in DeliveryService: %w", err)
 	}
 	if service.Status == "inactive" {
-		err := errors.New("account deleted")
+		err := apperr.New(apperr.NotFound, "account deleted")
 		log.Println("account deleted")
-		return nil, fmt.Errorf("Error in DeliveryService: %s", err)
+		return nil, fmt.Errorf("Error in DeliveryService: %w", err)
 	}
 	return service, nil
 }
 
-func (s *CourierService) GetAllDeliveryServices() ([]dao.DeliveryService, error) {
+func (s *CourierService) GetAllDeliveryServices(ctx context.Context) ([]dao.DeliveryService, error) {
 	var Services = []dao.DeliveryService{}
-	Services, err := s.repo.GetAllDeliveryServicesFromDB()
+	Services, err := s.repo.GetAllDeliveryServicesFromDB(ctx)
 	if err != nil {
 		log.Println(err)
-		return []dao.DeliveryService{}, fmt.Errorf("Error in DeliveryService: %s", err)
+		return []dao.DeliveryService{}, fmt.Errorf("Error in DeliveryService: %w", err)
 	}
-	Couriers, err := s.repo.GetCouriersWithServiceFromDB()
+	Couriers, err := s.repo.GetCouriersWithServiceFromDB(ctx)
 	if err != nil {
 		log.Println(err)
-		return []dao.DeliveryService{}, fmt.Errorf("Error in DeliveryService: %s", err)
+		return []dao.DeliveryService{}, fmt.Errorf("Error in DeliveryService: %w", err)
 	}
 	for i, service := range Services {
 		count := 0
@@ -68,36 +87,75 @@ func (s *CourierService) GetAllDeliveryServices() ([]dao.DeliveryService, error)
 	return Services, nil
 }
 
-func (s *CourierService) UpdateDeliveryService(service dao.DeliveryService) error {
-	if err := s.repo.UpdateDeliveryServiceInDB(service); err != nil {
+func (s *CourierService) UpdateDeliveryService(ctx context.Context, service dao.DeliveryService) error {
+	err := s.repo.UpdateDeliveryServiceInDB(ctx, service)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in DeliveryService: %w", ErrDeliveryServiceNotFound)
+	}
+	if errors.Is(err, dao.ErrVersionMismatch) {
+		return fmt.Errorf("Error in DeliveryService: %w", ErrDeliveryServiceChanged)
+	}
+	if err != nil {
 		log.Println(err)
-		return fmt.Errorf("Error in DeliveryService: %s", err)
+		return fmt.Errorf("Error in DeliveryService: %w", err)
 	}
 	return nil
 }
-func (s *CourierService) SaveLogoFile(cover []byte, id int) error {
-	client, err := InitClientDO()
+
+// PatchDeliveryService applies a merge patch to the delivery service.
+// Description, phone number and courier rate can be cleared; name, email and
+// status can't.
+func (s *CourierService) PatchDeliveryService(ctx context.Context, id int, patch dao.DeliveryServicePatch) (dao.DeliveryService, error) {
+	var fields []apperr.FieldError
+	for _, field := range []struct {
+		name  string
+		value dao.PatchString
+	}{{"name", patch.Name}, {"email", patch.Email}, {"status", patch.Status}} {
+		if field.value.Set && field.value.Value == "" {
+			fields = append(fields, apperr.FieldError{Field: field.name, Message: "can't be empty"})
+		}
+	}
+	if len(fields) > 0 {
+		return dao.DeliveryService{}, fmt.Errorf("Error in DeliveryService: %w", apperr.Invalid(fields...))
+	}
+	service, err := s.repo.PatchDeliveryServiceInDB(ctx, id, patch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return dao.DeliveryService{}, fmt.Errorf("Error in DeliveryService: %w", ErrDeliveryServiceNotFound)
+	}
+	if errors.Is(err, dao.ErrVersionMismatch) {
+		return dao.DeliveryService{}, fmt.Errorf("Error in DeliveryService: %w", ErrDeliveryServiceChanged)
+	}
 	if err != nil {
-		log.Println(err)
-		return err
+		return dao.DeliveryService{}, fmt.Errorf("Error with database: %w", err)
 	}
+	return service, nil
+}
 
-	_, err1 := client.PutObject("storage-like-s3", fmt.Sprintf("logo_img/%s", strconv.Itoa(id)),
-		bytes.NewReader(cover), int64(len(cover)), minio.PutObjectOptions{ContentType: "image/jpeg", UserMetadata: map[string]string{"x-amz-acl": "public-read"}})
-	if err1 != nil {
-		log.Println(err1)
-		return err1
+func (s *CourierService) SaveLogoFile(ctx context.Context, cover []byte, id int) error {
+	urls, err := s.saveImage(ctx, logoKey(id), cover)
+	if err != nil {
+		log.Println(err)
+		return fmt.Errorf("Error in DeliveryService: %w", err)
 	}
+	return s.setLogo(ctx, id, urls)
+}
 
+// setLogo points the delivery service at an image saveImage has stored.
+func (s *CourierService) setLogo(ctx context.Context, id int, urls imageURLs) error {
 	var service dao.DeliveryService
 	service.Id = id
-	service.Photo = "https://storage-like-s3.fra1.digitaloceanspaces.com/logo_img/" + strconv.Itoa(id)
+	service.Photo = urls.Original
+	service.PhotoMedium = urls.Medium
+	service.PhotoThumbnail = urls.Thumbnail
 
-	if err := s.repo.UpdateDeliveryServiceInDB(service); err != nil {
+	if err := s.repo.UpdateDeliveryServiceInDB(ctx, service); err != nil {
 		log.Println(err)
-		return fmt.Errorf("Error in DeliveryService: %s", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("Error in DeliveryService: %w", ErrDeliveryServiceNotFound)
+		}
+		return fmt.Errorf("Error in DeliveryService: %w", err)
 	}
 
-	log.Println("Uploaded logo with link https://storage-like-s3.fra1.digitaloceanspaces.com/logo_img/" + strconv.Itoa(id))
+	log.Println("Uploaded logo with link " + service.Photo)
 	return nil
 }
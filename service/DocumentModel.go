@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"strings"
+)
+
+const (
+	DocumentDrivingLicence     = "driving_licence"
+	DocumentIdCard             = "id_card"
+	DocumentVehicleInsurance   = "vehicle_insurance"
+	DocumentMedicalCertificate = "medical_certificate"
+)
+
+// documentTypes lists the documents a courier can have and whether an
+// expired one stops the courier from taking orders.
+var documentTypes = map[string]bool{
+	DocumentDrivingLicence:     true,
+	DocumentIdCard:             true,
+	DocumentVehicleInsurance:   true,
+	DocumentMedicalCertificate: true,
+}
+
+var (
+	ErrInvalidDocument         = apperr.New(apperr.Validation, "invalid document")
+	ErrCourierDocumentsExpired = apperr.New(apperr.Conflict, "courier has expired mandatory documents")
+	ErrDocumentWarningNotFound = apperr.New(apperr.NotFound, "document warning not found")
+)
+
+func mandatoryDocumentTypes() []string {
+	var types []string
+	for documentType, mandatory := range documentTypes {
+		if mandatory {
+			types = append(types, documentType)
+		}
+	}
+	return types
+}
+
+func (s *CourierService) SaveCourierDocument(ctx context.Context, document dao.CourierDocument) (int, error) {
+	document.Number = strings.TrimSpace(document.Number)
+	if _, ok := documentTypes[document.Type]; !ok {
+		return 0, fmt.Errorf("Error in DocumentService: %w", apperr.Errorf("%w: unknown type %q", ErrInvalidDocument, document.Type))
+	}
+	if document.Number == "" || document.IssueDate.IsZero() || document.ExpiryDate.IsZero() {
+		return 0, fmt.Errorf("Error in DocumentService: %w", apperr.Errorf("%w: number, issue_date and expiry_date are required", ErrInvalidDocument))
+	}
+	if !document.ExpiryDate.After(document.IssueDate.Time) {
+		return 0, fmt.Errorf("Error in DocumentService: %w", apperr.Errorf("%w: expiry_date must be after issue_date", ErrInvalidDocument))
+	}
+	// Scans are attached through uploads only, so a document can't point at
+	// somebody else's file.
+	document.File = ""
+	id, err := s.repo.SaveCourierDocumentInDB(ctx, &document)
+	if err != nil {
+		return 0, fmt.Errorf("Error in DocumentService: %w", err)
+	}
+	return id, nil
+}
+
+func (s *CourierService) GetCourierDocuments(ctx context.Context, courierId int) ([]dao.CourierDocument, error) {
+	documents, err := s.repo.GetCourierDocumentsFromDB(ctx, courierId)
+	if err != nil {
+		return nil, fmt.Errorf("Error in DocumentService: %w", err)
+	}
+	for i := range documents {
+		documents[i].File = documentFile(documents[i])
+	}
+	return documents, nil
+}
+
+// GetExpiringDocuments lists the documents of the delivery service that have
+// expired or expire within the given number of days.
+func (s *CourierService) GetExpiringDocuments(ctx context.Context, idService, days int) ([]dao.ExpiringDocument, error) {
+	documents, err := s.repo.GetExpiringDocumentsFromDB(ctx, idService, dao.Today().AddDays(days))
+	if err != nil {
+		return nil, fmt.Errorf("Error in DocumentService: %w", err)
+	}
+	for i := range documents {
+		documents[i].File = documentFile(documents[i].CourierDocument)
+	}
+	return documents, nil
+}
+
+// GetDocumentWarnings lists the expiry warnings of the delivery service its
+// managers haven't acknowledged yet.
+func (s *CourierService) GetDocumentWarnings(ctx context.Context, idService int) ([]dao.DocumentWarning, error) {
+	warnings, err := s.repo.GetDocumentWarningsFromDB(ctx, idService)
+	if err != nil {
+		return nil, fmt.Errorf("Error in DocumentService: %w", err)
+	}
+	for i := range warnings {
+		warnings[i].Document.File = documentFile(warnings[i].Document.CourierDocument)
+	}
+	return warnings, nil
+}
+
+// documentFile is where the scan of the document is served, "" when it has
+// none.
+func documentFile(document dao.CourierDocument) string {
+	if document.File == "" {
+		return ""
+	}
+	return DocumentFilePath(document.CourierId, document.Type)
+}
+
+func (s *CourierService) AcknowledgeDocumentWarning(ctx context.Context, idService, id int) error {
+	err := s.repo.AcknowledgeDocumentWarningInDB(ctx, idService, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in DocumentService: %w", ErrDocumentWarningNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("Error in DocumentService: %w", err)
+	}
+	return nil
+}
+
+// checkCourierDocuments fails with ErrCourierDocumentsExpired when one of the
+// courier's mandatory documents has expired. Missing documents don't block
+// a courier: they are the manager's call.
+func (s *CourierService) checkCourierDocuments(ctx context.Context, courierId int) error {
+	expired, err := s.repo.CountExpiredDocumentsOfCourierFromDB(ctx, courierId, mandatoryDocumentTypes(), dao.Today())
+	if err != nil {
+		return fmt.Errorf("Error in DocumentService: %w", err)
+	}
+	if expired > 0 {
+		return fmt.Errorf("Error in DocumentService: %w", ErrCourierDocumentsExpired)
+	}
+	return nil
+}
+
+// SetCourierReadyToGo checks the courier and changes its availability in
+// one transaction that holds the courier.
+func (s *CourierService) SetCourierReadyToGo(ctx context.Context, courierId int, ready bool) error {
+	return s.repo.InTx(ctx, func(ctx context.Context) error {
+		if ready {
+			if err := s.lockCourier(ctx, courierId); err != nil {
+				return err
+			}
+			if err := s.checkCourierApproved(ctx, courierId); err != nil {
+				log.Println(err)
+				return err
+			}
+			if err := s.checkCourierDocuments(ctx, courierId); err != nil {
+				log.Println(err)
+				return err
+			}
+		}
+		if err := s.repo.SetCourierReadyToGoInDB(ctx, courierId, ready); err != nil {
+			return fmt.Errorf("Error in CourierService: %w", err)
+		}
+		return nil
+	})
+}
+
+// CheckDocumentExpiry is the body of the scheduled document job. It takes
+// couriers with expired mandatory documents off "ready to go" and leaves the
+// managers of each delivery service a warning, once per document, about
+// documents expiring within days. The warnings wait at GetDocumentWarnings
+// until a manager acknowledges them.
+func (s *CourierService) CheckDocumentExpiry(ctx context.Context, days int) error {
+	stopped, err := s.repo.StopCouriersWithExpiredDocumentsInDB(ctx, mandatoryDocumentTypes(), dao.Today())
+	if err != nil {
+		return fmt.Errorf("Error in DocumentService: %w", err)
+	}
+	for _, id := range stopped {
+		log.Printf("documents: courier %d is no longer ready to go: a mandatory document has expired", id)
+	}
+	documents, err := s.repo.ClaimDocumentsToWarnInDB(ctx, dao.Today().AddDays(days))
+	if err != nil {
+		return fmt.Errorf("Error in DocumentService: %w", err)
+	}
+	for _, document := range documents {
+		log.Printf("documents: warned delivery service %d: %s %s of courier %d (%s %s) expires on %s",
+			document.DeliveryServiceId, document.Type, document.Number, document.CourierId,
+			document.CourierName, document.CourierSurname, document.ExpiryDate)
+	}
+	return nil
+}
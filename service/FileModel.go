@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/storage"
+	"strconv"
+	"strings"
+)
+
+// Courier photos and logos are public. Proofs of delivery and scans of
+// courier documents aren't: they stay under the key of their upload, which
+// clients never see, and are served at ProofOfDeliveryPath and
+// DocumentFilePath to callers who may see the order or the courier.
+
+var ErrFileNotFound = apperr.New(apperr.NotFound, "file not found")
+
+// publicFilePrefixes start the keys GetFile serves, those of courierPhotoKey
+// and logoKey.
+var publicFilePrefixes = []string{"courier_photo/", "logo_img/"}
+
+// ProofOfDeliveryPath is where the proof of delivery of the order is served.
+func ProofOfDeliveryPath(orderId int) string {
+	return "/order/" + strconv.Itoa(orderId) + "/proof_of_delivery"
+}
+
+// DocumentFilePath is where the scan of the courier's document is served.
+func DocumentFilePath(courierId int, documentType string) string {
+	return "/courier/" + strconv.Itoa(courierId) + "/documents/" + documentType + "/file"
+}
+
+// GetFile returns a public file: a courier photo or a logo.
+func (s *CourierService) GetFile(ctx context.Context, key string) ([]byte, string, error) {
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+	for _, prefix := range publicFilePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return s.storage.Get(ctx, key)
+		}
+	}
+	return nil, "", storage.ErrNotFound
+}
+
+func (s *CourierService) GetProofOfDelivery(ctx context.Context, orderId int) ([]byte, string, error) {
+	stored, err := s.repo.GetProofOfDeliveryFromDB(ctx, orderId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", fmt.Errorf("Error in FileService: %w", ErrOrderNotFound)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("Error in FileService: %w", err)
+	}
+	return s.getPrivateFile(ctx, stored)
+}
+
+func (s *CourierService) GetDocumentFile(ctx context.Context, courierId int, documentType string) ([]byte, string, error) {
+	stored, err := s.repo.GetDocumentFileFromDB(ctx, courierId, documentType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", fmt.Errorf("Error in FileService: %w", ErrDocumentNotFound)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("Error in FileService: %w", err)
+	}
+	return s.getPrivateFile(ctx, stored)
+}
+
+// getPrivateFile reads a file by what the database keeps for it: its key,
+// or the public URL files attached before they became private were kept as.
+func (s *CourierService) getPrivateFile(ctx context.Context, stored string) ([]byte, string, error) {
+	if stored == "" {
+		return nil, "", fmt.Errorf("Error in FileService: %w", ErrFileNotFound)
+	}
+	data, contentType, err := s.storage.Get(ctx, strings.TrimPrefix(stored, s.storage.URL("")))
+	if err != nil {
+		return nil, "", fmt.Errorf("Error in FileService: %w", err)
+	}
+	return data, contentType, nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/imaging"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/storage"
+	"strconv"
+)
+
+type imageURLs struct {
+	Original  string
+	Medium    string
+	Thumbnail string
+}
+
+// saveImage validates an uploaded image and stores the cleaned original under
+// key and the resized variants next to it, as key-medium and key-thumbnail.
+func (s *CourierService) saveImage(ctx context.Context, key string, upload []byte) (imageURLs, error) {
+	processed, err := imaging.Process(upload, imaging.DefaultLimits)
+	if err != nil {
+		return imageURLs{}, err
+	}
+	files := []struct {
+		key   string
+		image imaging.Image
+	}{
+		{key, processed.Original},
+		{key + "-medium", processed.Medium},
+		{key + "-thumbnail", processed.Thumbnail},
+	}
+	for _, file := range files {
+		if err := s.storage.Put(ctx, file.key, file.image.Data, file.image.ContentType); err != nil {
+			return imageURLs{}, fmt.Errorf("saving %s: %w", file.key, err)
+		}
+	}
+	return imageURLs{
+		Original:  s.storage.URL(key),
+		Medium:    s.storage.URL(key + "-medium"),
+		Thumbnail: s.storage.URL(key + "-thumbnail"),
+	}, nil
+}
+
+// deleteImage removes what saveImage stored under key.
+func (s *CourierService) deleteImage(ctx context.Context, key string) {
+	for _, variant := range []string{key, key + "-medium", key + "-thumbnail"} {
+		if err := s.storage.Delete(ctx, variant); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Println(err)
+		}
+	}
+}
+
+func courierPhotoKey(id int) string {
+	return "courier_photo/" + strconv.Itoa(id)
+}
+
+func logoKey(id int) string {
+	return "logo_img/" + strconv.Itoa(id)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"strings"
+)
+
+// Onboarding statuses of a courier. An applicant fills in the profile and
+// documents, submits them for review, and a courier manager approves or
+// rejects the application. Only approved couriers take orders.
+const (
+	CourierApplied       = "applied"
+	CourierPendingReview = "pending_review"
+	CourierApproved      = "approved"
+	CourierRejected      = "rejected"
+)
+
+var (
+	ErrApplicationIncomplete = apperr.New(apperr.Unprocessable, "application is incomplete")
+	ErrOnboardingStatus      = apperr.New(apperr.Conflict, "not allowed in the courier's onboarding status")
+	ErrInvalidReview         = apperr.New(apperr.Validation, "invalid review")
+	ErrCourierNotApproved    = apperr.New(apperr.Conflict, "courier is not approved")
+	ErrCourierNotFound       = apperr.New(apperr.NotFound, "courier not found")
+)
+
+// applicationDocuments are the documents every applicant provides before
+// review; couriers with a motor vehicle also need a driving licence and an
+// insurance.
+func applicationDocuments(vehicle *dao.Vehicle) []string {
+	types := []string{DocumentIdCard, DocumentMedicalCertificate}
+	if vehicle != nil && vehicleTypes[vehicle.Type].needsPlate {
+		types = append(types, DocumentDrivingLicence, DocumentVehicleInsurance)
+	}
+	return types
+}
+
+// SubmitCourierApplication sends an applied or rejected courier to review
+// once the profile and the documents are complete.
+func (s *CourierService) SubmitCourierApplication(ctx context.Context, courierId int) error {
+	if err := s.checkApplication(ctx, courierId); err != nil {
+		log.Println(err)
+		return err
+	}
+	return s.setCourierStatus(ctx, courierId, []string{CourierApplied, CourierRejected}, CourierPendingReview, "")
+}
+
+func (s *CourierService) checkApplication(ctx context.Context, courierId int) error {
+	documents, err := s.repo.GetCourierDocumentsFromDB(ctx, courierId)
+	if err != nil {
+		return fmt.Errorf("Error in OnboardingService: %w", err)
+	}
+	vehicle, err := s.repo.GetVehicleOfCourierFromDB(ctx, courierId)
+	if err != nil {
+		return fmt.Errorf("Error in OnboardingService: %w", err)
+	}
+	valid := make(map[string]bool)
+	today := dao.Today()
+	for _, document := range documents {
+		valid[document.Type] = !document.ExpiryDate.Before(today.Time)
+	}
+	var missing []string
+	for _, documentType := range applicationDocuments(vehicle) {
+		if !valid[documentType] {
+			missing = append(missing, documentType)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Error in OnboardingService: %w", apperr.Errorf("%w: missing or expired %s", ErrApplicationIncomplete, strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+// ReviewCourierApplication approves or rejects a courier pending review. A
+// rejection needs a reason, which the applicant sees.
+func (s *CourierService) ReviewCourierApplication(ctx context.Context, courierId int, approve bool, reason string) error {
+	reason = strings.TrimSpace(reason)
+	status := CourierApproved
+	if !approve {
+		status = CourierRejected
+		if reason == "" {
+			return fmt.Errorf("Error in OnboardingService: %w", apperr.Errorf("%w: a rejection needs a reason", ErrInvalidReview))
+		}
+	}
+	return s.setCourierStatus(ctx, courierId, []string{CourierPendingReview}, status, reason)
+}
+
+func (s *CourierService) setCourierStatus(ctx context.Context, courierId int, from []string, status, reason string) error {
+	ok, err := s.repo.SetCourierStatusInDB(ctx, courierId, from, status, reason)
+	if err != nil {
+		return fmt.Errorf("Error in OnboardingService: %w", err)
+	}
+	if ok {
+		return nil
+	}
+	current, err := s.repo.GetCourierStatusFromDB(ctx, courierId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in OnboardingService: %w", ErrCourierNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("Error in OnboardingService: %w", err)
+	}
+	return fmt.Errorf("Error in OnboardingService: %w", apperr.Errorf("%w: courier is %s", ErrOnboardingStatus, current))
+}
+
+// GetCourierApplications lists the couriers of the delivery service waiting
+// for review.
+func (s *CourierService) GetCourierApplications(ctx context.Context, idService int) ([]dao.Courier, error) {
+	couriers, err := s.repo.GetCouriersByStatusFromDB(ctx, idService, CourierPendingReview)
+	if err != nil {
+		return nil, fmt.Errorf("Error in OnboardingService: %w", err)
+	}
+	if couriers == nil {
+		couriers = []dao.Courier{}
+	}
+	return couriers, nil
+}
+
+// lockCourier holds the courier until the end of the unit of work ctx runs
+// in.
+func (s *CourierService) lockCourier(ctx context.Context, courierId int) error {
+	err := s.repo.LockCourierInDB(ctx, courierId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in OnboardingService: %w", ErrCourierNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("Error in OnboardingService: %w", err)
+	}
+	return nil
+}
+
+// checkCourierApproved keeps applicants out of dispatch.
+func (s *CourierService) checkCourierApproved(ctx context.Context, courierId int) error {
+	status, err := s.repo.GetCourierStatusFromDB(ctx, courierId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in OnboardingService: %w", ErrCourierNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("Error in OnboardingService: %w", err)
+	}
+	if status != CourierApproved {
+		return fmt.Errorf("Error in OnboardingService: %w", apperr.Errorf("%w: courier is %s", ErrCourierNotApproved, status))
+	}
+	return nil
+}
@@ -1,209 +1,421 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"log"
 	courierProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPC"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"strings"
 )
 
-func (s *CourierService) GetOrders(id int) ([]dao.Order, error) {
-	get, err := s.repo.GetActiveOrdersFromDB(id)
-	if get == nil {
-		return []dao.Order{}, fmt.Errorf("Error in OrderService: %s", err)
-	}
+// ErrOrderChanged is a write made against a version of the order that has
+// changed since.
+var ErrOrderChanged = apperr.New(apperr.PreconditionFailed, "order was changed since it was read")
+
+var (
+	ErrInvalidOrderStatus    = apperr.New(apperr.Validation, "status must be ready to delivery or completed")
+	ErrCourierOfOtherService = apperr.New(apperr.Conflict, "courier belongs to another delivery service than the order")
+)
+
+// orderStatuses lists the statuses an order can be moved to.
+var orderStatuses = map[string]bool{
+	"ready to delivery": true,
+	"completed":         true,
+}
+
+// IsOrderStatus tells whether an order can be moved to the status.
+func IsOrderStatus(status string) bool {
+	return orderStatuses[status]
+}
+
+func (s *CourierService) GetOrders(ctx context.Context, id int) ([]dao.Order, error) {
+	get, err := s.repo.GetActiveOrdersFromDB(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("Error with database: %s", err)
+		return nil, fmt.Errorf("Error with database: %w", dbError(ctx, err))
+	}
+	if get == nil {
+		return []dao.Order{}, fmt.Errorf("Error in OrderService: %w", ErrOrderNotFound)
 	}
 	if id == 0 {
-		err := errors.New("no id")
+		err := apperr.New(apperr.Validation, "no id")
 		log.Println("id cannot be zero")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+		return nil, fmt.Errorf("Error in OrderService: %w", err)
 	}
 	return get, nil
 }
 
-func (s *CourierService) GetOrder(id int) (dao.Order, error) {
-	get, err := s.repo.GetActiveOrderFromDB(id)
-	if (get == dao.Order{}) {
-		return dao.Order{}, fmt.Errorf("Error in OrderService: %s", err)
+func (s *CourierService) GetOrder(ctx context.Context, id int) (dao.Order, error) {
+	get, err := s.repo.GetActiveOrderFromDB(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return dao.Order{}, fmt.Errorf("Error in OrderService: %w", ErrOrderNotFound)
 	}
 	if err != nil {
-		return dao.Order{}, fmt.Errorf("Error with database: %s", err)
+		return dao.Order{}, fmt.Errorf("Error with database: %w", dbError(ctx, err))
 	}
 	if id == 0 {
-		err := errors.New("no id")
+		err := apperr.New(apperr.Validation, "no id")
 		log.Println("id cannot be zero")
-		return dao.Order{}, fmt.Errorf("Error in OrderService: %s", err)
+		return dao.Order{}, fmt.Errorf("Error in OrderService: %w", err)
 	}
 	return get, nil
 }
 
-func (s *CourierService) GetOrderForChange(id int) (dao.Order, error) {
-	get, err := s.repo.GetOrderFromDB(id)
-	if (get == dao.Order{}) {
-		return dao.Order{}, fmt.Errorf("Error in OrderService: %s", err)
+func (s *CourierService) GetOrderForChange(ctx context.Context, id int) (dao.Order, error) {
+	get, err := s.repo.GetOrderFromDB(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return dao.Order{}, fmt.Errorf("Error in OrderService: %w", ErrOrderNotFound)
 	}
 	if err != nil {
-		return dao.Order{}, fmt.Errorf("Error with database: %s", err)
+		return dao.Order{}, fmt.Errorf("Error with database: %w", dbError(ctx, err))
 	}
 	if id == 0 {
-		err := errors.New("no id")
+		err := apperr.New(apperr.Validation, "no id")
 		log.Println("id cannot be zero")
-		return dao.Order{}, fmt.Errorf("Error in OrderService: %s", err)
+		return dao.Order{}, fmt.Errorf("Error in OrderService: %w", err)
 	}
 	return get, nil
 }
 
-func (s *CourierService) ChangeOrderStatus(text string, id uint16) (uint16, error) {
-	_, err := s.GetOrderForChange(int(id))
+// ChangeOrderStatus changes the status of the order if it still has the
+// given version; version 0 changes any version.
+func (s *CourierService) ChangeOrderStatus(ctx context.Context, text string, id uint16, version int) (uint16, error) {
+	if !IsOrderStatus(text) {
+		return 0, fmt.Errorf("Error in OrderService: %w", apperr.Errorf("%w, got %q", ErrInvalidOrderStatus, text))
+	}
+	_, err := s.GetOrderForChange(ctx, int(id))
 	if err != nil {
-		return 0, fmt.Errorf("Error in OrderService: %s", err)
+		return 0, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	orderId, err := s.repo.ChangeOrderStatusInDB(ctx, text, id, version)
+	if errors.Is(err, dao.ErrVersionMismatch) {
+		return 0, fmt.Errorf("Error in OrderService: %w", ErrOrderChanged)
 	}
-	orderId, err := s.repo.ChangeOrderStatusInDB(text, id)
 	if err != nil {
-		return 0, fmt.Errorf("Error with database: %s", err)
+		return 0, fmt.Errorf("Error with database: %w", err)
 	}
 	return orderId, nil
 }
 
-func (s *CourierService) GetCourierCompletedOrders(limit, page, idCourier int) ([]dao.DetailedOrder, error) {
+func (s *CourierService) GetCourierCompletedOrders(ctx context.Context, limit, page, idCourier int) ([]dao.DetailedOrder, dao.Pagination, error) {
 	var Order = []dao.DetailedOrder{}
 
 	if limit <= 0 || page <= 0 {
-		err := errors.New("no page or limit")
+		err := apperr.New(apperr.Validation, "no page or limit")
 		log.Println("no more pages or limit")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
 	}
-	Order, totalCount := s.repo.GetCourierCompletedOrdersWithPage_fromDB(limit, page, idCourier)
-	LimitOfPages := (totalCount / limit) + 1
-	if LimitOfPages < page {
-		err := errors.New("no page")
-		log.Println("no more pages")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+	Order, totalCount, err := s.repo.GetCourierCompletedOrdersWithPage_fromDB(ctx, limit, page, idCourier)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", dbError(ctx, err))
 	}
-	fmt.Println(Order)
-	return Order, nil
+	pagination, err := paginate(limit, page, totalCount)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	return Order, pagination, nil
 }
 
-func (s *CourierService) GetAllOrdersOfCourierService(limit, page, idService int) ([]dao.DetailedOrder, error) {
+func (s *CourierService) GetAllOrdersOfCourierService(ctx context.Context, limit, page, idService int) ([]dao.DetailedOrder, dao.Pagination, error) {
 	var Order = []dao.DetailedOrder{}
 	if limit <= 0 || page <= 0 {
-		err := errors.New("no page or limit")
+		err := apperr.New(apperr.Validation, "no page or limit")
 		log.Println("no more pages or limit")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
 	}
-	Order, totalCount := s.repo.GetAllOrdersOfCourierServiceWithPageFromDB(limit, page, idService)
-	LimitOfPages := (totalCount / limit) + 1
-	if LimitOfPages < page {
-		err := errors.New("no page")
-		log.Println("no more pages")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+	Order, totalCount, err := s.repo.GetAllOrdersOfCourierServiceWithPageFromDB(ctx, limit, page, idService)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", dbError(ctx, err))
 	}
-	fmt.Println(Order)
-	return Order, nil
+	pagination, err := paginate(limit, page, totalCount)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	return Order, pagination, nil
 }
 
-func (s *CourierService) GetCourierCompletedOrdersByMonth(limit, page, idService, Month, Year int) ([]dao.Order, error) {
+func (s *CourierService) GetCourierCompletedOrdersByMonth(ctx context.Context, limit, page, idService, Month, Year int) ([]dao.Order, dao.Pagination, error) {
 	var Order = []dao.Order{}
 	if limit <= 0 || page <= 0 {
-		err := errors.New("no page or limit")
+		err := apperr.New(apperr.Validation, "no page or limit")
 		log.Println("no more pages or limit")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
 	}
-	Order, totalCount := s.repo.GetCourierCompletedOrdersByMouthWithPageFromDB(limit, page, idService, Month, Year)
-	LimitOfPages := (totalCount / limit) + 1
-	if LimitOfPages < page {
-		err := errors.New("no page")
-		log.Println("no more pages")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+	Order, totalCount, err := s.repo.GetCourierCompletedOrdersByMouthWithPageFromDB(ctx, limit, page, idService, Month, Year)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", dbError(ctx, err))
+	}
+	pagination, err := paginate(limit, page, totalCount)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
 	}
 	if Month >= 13 || Month < 1 {
-		err := errors.New("enter correct month")
+		err := apperr.New(apperr.Validation, "enter correct month")
 		log.Println("enter correct month")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
 	}
 
-	return Order, nil
+	return Order, pagination, nil
 }
 
-func (s *CourierService) AssigningOrderToCourier(order dao.Order) error {
-	if err := s.repo.AssigningOrderToCourierInDB(order); err != nil {
+// AssigningOrderToCourier checks the courier and assigns the order in one
+// transaction that holds the courier, so it can't lose its approval or move
+// to another delivery service in between.
+func (s *CourierService) AssigningOrderToCourier(ctx context.Context, order dao.Order) error {
+	err := s.repo.InTx(ctx, func(ctx context.Context) error {
+		if order.IdCourier != 0 {
+			if err := s.lockCourier(ctx, order.IdCourier); err != nil {
+				return err
+			}
+			if err := s.checkSameDeliveryService(ctx, order.IdCourier, order.Id); err != nil {
+				return err
+			}
+			if err := s.checkCourierCanTakeOrder(ctx, order.IdCourier, order.Id); err != nil {
+				log.Println(err)
+				return err
+			}
+		}
+		return s.repo.AssigningOrderToCourierInDB(ctx, order)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in OrderService: %w", ErrOrderNotFound)
+	}
+	if errors.Is(err, dao.ErrVersionMismatch) {
+		return fmt.Errorf("Error in OrderService: %w", ErrOrderChanged)
+	}
+	if err != nil {
 		log.Println(err)
-		return fmt.Errorf("Error in OrderService: %s", err)
+		return fmt.Errorf("Error in OrderService: %w", err)
 	}
 	return nil
 }
 
-func (s *CourierService) GetDetailedOrderById(Id int) (*dao.AllInfoAboutOrder, error) {
+// checkSameDeliveryService keeps couriers to the orders of their own
+// delivery service.
+func (s *CourierService) checkSameDeliveryService(ctx context.Context, courierId, orderId int) error {
+	orderService, _, err := s.repo.GetOrderOwnerFromDB(ctx, orderId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in OrderService: %w", ErrOrderNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("Error in OrderService: %w", err)
+	}
+	courierService, err := s.repo.GetDeliveryServiceOfCourierFromDB(ctx, courierId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in OrderService: %w", ErrCourierNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("Error in OrderService: %w", err)
+	}
+	if courierService != orderService {
+		return fmt.Errorf("Error in OrderService: courier %d of delivery service %d, order %d of delivery service %d: %w",
+			courierId, courierService, orderId, orderService, ErrCourierOfOtherService)
+	}
+	return nil
+}
+
+func (s *CourierService) GetDetailedOrderById(ctx context.Context, Id int) (*dao.AllInfoAboutOrder, error) {
 	var Order *dao.AllInfoAboutOrder
-	Order, err := s.repo.GetDetailedOrderByIdFromDB(Id)
+	Order, err := s.repo.GetDetailedOrderByIdFromDB(ctx, Id)
 	if err != nil {
 		log.Println(err)
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+		return nil, fmt.Errorf("Error in OrderService: %w", err)
 	}
 	if Order.IdOrder == 0 {
-		err = errors.New("not found")
-		log.Println(err)
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+		log.Println(ErrOrderNotFound)
+		return nil, fmt.Errorf("Error in OrderService: %w", ErrOrderNotFound)
+	}
+	if Order.ProofOfDelivery != "" {
+		Order.ProofOfDelivery = ProofOfDeliveryPath(Order.IdOrder)
 	}
 	return Order, nil
 }
 
-func (s *CourierService) CreateOrder(order *courierProto.OrderCourierServer) (*emptypb.Empty, error) {
-	return s.repo.OrderRep.CreateOrder(order)
+func (s *CourierService) CreateOrder(ctx context.Context, order *courierProto.OrderCourierServer) (*emptypb.Empty, error) {
+	order.Size = strings.ToLower(strings.TrimSpace(order.Size))
+	if err := validateOrderLoad(dao.OrderLoad{WeightKg: order.WeightKg, Size: order.Size, DistanceKm: order.DistanceKm}); err != nil {
+		log.Println(err)
+		return nil, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	return s.repo.OrderRep.CreateOrder(ctx, order)
 }
 
-func (s *CourierService) GetServices(in *emptypb.Empty) (*courierProto.ServicesResponse, error) {
-	return s.repo.OrderRep.GetServices(in)
+func (s *CourierService) GetServices(ctx context.Context, in *emptypb.Empty) (*courierProto.ServicesResponse, error) {
+	return s.repo.OrderRep.GetServices(ctx, in)
 }
-func (s *CourierService) GetCompletedOrdersOfCourierService(limit, page, idService int) ([]dao.Order, error) {
+func (s *CourierService) GetCompletedOrdersOfCourierService(ctx context.Context, limit, page, idService int) ([]dao.Order, dao.Pagination, error) {
 	var Order = []dao.Order{}
-	Order, totalCount := s.repo.GetCompletedOrdersOfCourierServiceFromDB(limit, page, idService)
-	LimitOfPages := (totalCount / limit) + 1
-	if LimitOfPages < page {
-		err := errors.New("no page")
-		log.Println("no more pages")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+	Order, totalCount, err := s.repo.GetCompletedOrdersOfCourierServiceFromDB(ctx, limit, page, idService)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", dbError(ctx, err))
 	}
-	return Order, nil
+	pagination, err := paginate(limit, page, totalCount)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	return Order, pagination, nil
 }
 
-func (s *CourierService) GetCompletedOrdersOfCourierServiceByDate(limit, page, idService int) ([]dao.Order, error) {
+func (s *CourierService) GetCompletedOrdersOfCourierServiceByDate(ctx context.Context, limit, page, idService int) ([]dao.Order, dao.Pagination, error) {
 	var Order = []dao.Order{}
-	Order, totalCount := s.repo.GetCompletedOrdersOfCourierServiceByDateFromDB(limit, page, idService)
-	LimitOfPages := (totalCount / limit) + 1
-	if LimitOfPages < page {
-		err := errors.New("no page")
-		log.Println("no more pages")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+	Order, totalCount, err := s.repo.GetCompletedOrdersOfCourierServiceByDateFromDB(ctx, limit, page, idService)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", dbError(ctx, err))
 	}
-	return Order, nil
+	pagination, err := paginate(limit, page, totalCount)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	return Order, pagination, nil
 }
 
-func (s *CourierService) GetCompletedOrdersOfCourierServiceByCourierId(limit, page, idService int) ([]dao.Order, error) {
+func (s *CourierService) GetCompletedOrdersOfCourierServiceByCourierId(ctx context.Context, limit, page, idService int) ([]dao.Order, dao.Pagination, error) {
 	var Order = []dao.Order{}
-	Order, totalCount := s.repo.GetCompletedOrdersOfCourierServiceByCourierIdFromDB(limit, page, idService)
-	LimitOfPages := (totalCount / limit) + 1
-	if LimitOfPages < page {
-		err := errors.New("no page")
-		log.Println("no more pages")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
+	Order, totalCount, err := s.repo.GetCompletedOrdersOfCourierServiceByCourierIdFromDB(ctx, limit, page, idService)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", dbError(ctx, err))
 	}
-	return Order, nil
+	pagination, err := paginate(limit, page, totalCount)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	return Order, pagination, nil
 }
 
-func (s *CourierService) GetOrdersOfCourierServiceForManager(limit, page, idService int) ([]dao.DetailedOrder, error) {
+func (s *CourierService) GetOrdersOfCourierServiceForManager(ctx context.Context, limit, page, idService int) ([]dao.DetailedOrder, dao.Pagination, error) {
 	var Order = []dao.DetailedOrder{}
-	Order, totalCount := s.repo.GetOrdersOfCourierServiceForManagerFromDB(limit, page, idService)
-	LimitOfPages := (totalCount / limit) + 1
-	if LimitOfPages < page {
-		err := errors.New("no page")
-		log.Println("no more pages")
-		return nil, fmt.Errorf("Error in OrderService: %s", err)
-	}
-	fmt.Println(Order)
-	return Order, nil
+	Order, totalCount, err := s.repo.GetOrdersOfCourierServiceForManagerFromDB(ctx, limit, page, idService)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", dbError(ctx, err))
+	}
+	pagination, err := paginate(limit, page, totalCount)
+	if err != nil {
+		return nil, dao.Pagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	return Order, pagination, nil
+}
+
+func (s *CourierService) GetCourierCompletedOrdersAfterCursor(ctx context.Context, limit int, after string, idCourier int) ([]dao.DetailedOrder, dao.CursorPagination, error) {
+	cursor, err := decodeCursor(limit, after)
+	if err != nil {
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, err := s.repo.GetCourierCompletedOrdersAfterCursorFromDB(ctx, limit+1, cursor, idCourier)
+	if err != nil {
+		log.Println(err)
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, pagination := detailedOrdersPage(Orders, limit)
+	return Orders, pagination, nil
+}
+
+func (s *CourierService) GetAllOrdersOfCourierServiceAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.DetailedOrder, dao.CursorPagination, error) {
+	cursor, err := decodeCursor(limit, after)
+	if err != nil {
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, err := s.repo.GetAllOrdersOfCourierServiceAfterCursorFromDB(ctx, limit+1, cursor, idService)
+	if err != nil {
+		log.Println(err)
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, pagination := detailedOrdersPage(Orders, limit)
+	return Orders, pagination, nil
+}
+
+func (s *CourierService) GetCourierCompletedOrdersByMonthAfterCursor(ctx context.Context, limit int, after string, idCourier, Month, Year int) ([]dao.Order, dao.CursorPagination, error) {
+	cursor, err := decodeCursor(limit, after)
+	if err != nil {
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	if Month >= 13 || Month < 1 {
+		err := apperr.New(apperr.Validation, "enter correct month")
+		log.Println("enter correct month")
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, err := s.repo.GetCourierCompletedOrdersByMouthAfterCursorFromDB(ctx, limit+1, cursor, idCourier, Month, Year)
+	if err != nil {
+		log.Println(err)
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, pagination := ordersPage(Orders, limit)
+	return Orders, pagination, nil
+}
+
+func (s *CourierService) GetCompletedOrdersOfCourierServiceAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.Order, dao.CursorPagination, error) {
+	cursor, err := decodeCursor(limit, after)
+	if err != nil {
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, err := s.repo.GetCompletedOrdersOfCourierServiceAfterCursorFromDB(ctx, limit+1, cursor, idService)
+	if err != nil {
+		log.Println(err)
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, pagination := ordersPage(Orders, limit)
+	return Orders, pagination, nil
+}
+
+func (s *CourierService) GetCompletedOrdersOfCourierServiceByDateAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.Order, dao.CursorPagination, error) {
+	cursor, err := decodeCursor(limit, after)
+	if err != nil {
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, err := s.repo.GetCompletedOrdersOfCourierServiceByDateAfterCursorFromDB(ctx, limit+1, cursor, idService)
+	if err != nil {
+		log.Println(err)
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, pagination := ordersPage(Orders, limit)
+	return Orders, pagination, nil
+}
+
+func (s *CourierService) GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.Order, dao.CursorPagination, error) {
+	cursor, err := decodeCursor(limit, after)
+	if err != nil {
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, err := s.repo.GetCompletedOrdersOfCourierServiceByCourierIdAfterCursorFromDB(ctx, limit+1, cursor, idService)
+	if err != nil {
+		log.Println(err)
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, pagination := ordersPage(Orders, limit)
+	return Orders, pagination, nil
+}
+
+func (s *CourierService) GetOrdersOfCourierServiceForManagerAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.DetailedOrder, dao.CursorPagination, error) {
+	cursor, err := decodeCursor(limit, after)
+	if err != nil {
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, err := s.repo.GetOrdersOfCourierServiceForManagerAfterCursorFromDB(ctx, limit+1, cursor, idService)
+	if err != nil {
+		log.Println(err)
+		return nil, dao.CursorPagination{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	Orders, pagination := detailedOrdersPage(Orders, limit)
+	return Orders, pagination, nil
+}
+
+// GetCourierEarnings sums up what the courier earned for the deliveries
+// completed in the month.
+func (s *CourierService) GetCourierEarnings(ctx context.Context, idCourier, month, year int) (dao.Earnings, error) {
+	if month >= 13 || month < 1 {
+		err := apperr.New(apperr.Validation, "enter correct month")
+		log.Println("enter correct month")
+		return dao.Earnings{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	earnings, err := s.repo.GetEarningsOfCourierFromDB(ctx, idCourier, month, year)
+	if errors.Is(err, sql.ErrNoRows) {
+		return dao.Earnings{}, fmt.Errorf("Error in OrderService: %w", ErrCourierNotFound)
+	}
+	if err != nil {
+		return dao.Earnings{}, fmt.Errorf("Error in OrderService: %w", err)
+	}
+	return earnings, nil
 }
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"testing"
+)
+
+func TestCourierService_GetCourierCompletedOrders_DatabaseTimeout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := NewProjectService(*dao.NewRepository(db, dao.Config{}), nil, nil)
+
+	mock.ExpectQuery(`SELECT delivery.order_date`).WillReturnError(context.DeadlineExceeded)
+
+	_, _, err = s.GetCourierCompletedOrders(context.Background(), 10, 1, 4)
+
+	assert.Equal(t, apperr.Unavailable, apperr.KindOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourierService_GetOrder_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := NewProjectService(*dao.NewRepository(db, dao.Config{}), nil, nil)
+
+	mock.ExpectQuery(`from delivery where id = \$1`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_service_id"}))
+
+	_, err = s.GetOrder(context.Background(), 3)
+
+	assert.ErrorIs(t, err, ErrOrderNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourierService_ChangeOrderStatus_UnknownStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := NewProjectService(*dao.NewRepository(db, dao.Config{}), nil, nil)
+
+	_, err = s.ChangeOrderStatus(context.Background(), "lost", 12, 0)
+
+	assert.ErrorIs(t, err, ErrInvalidOrderStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourierService_AssigningOrderToCourier_OtherDeliveryService(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := NewProjectService(*dao.NewRepository(db, dao.Config{}), nil, nil)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id_courier FROM couriers WHERE id_courier = \$1 FOR UPDATE`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id_courier"}).AddRow(5))
+	mock.ExpectQuery(`SELECT delivery_service_id, courier_id FROM delivery WHERE id = \$1`).WithArgs(12).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_service_id", "courier_id"}).AddRow(1, nil))
+	mock.ExpectQuery(`SELECT delivery_service_id FROM couriers WHERE id_courier = \$1`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_service_id"}).AddRow(2))
+	mock.ExpectRollback()
+
+	err = s.AssigningOrderToCourier(context.Background(), dao.Order{Id: 12, IdCourier: 5})
+
+	assert.ErrorIs(t, err, ErrCourierOfOtherService)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
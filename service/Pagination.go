@@ -0,0 +1,44 @@
+package service
+
+import (
+	"log"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+)
+
+func paginate(limit, page, totalCount int) (dao.Pagination, error) {
+	LimitOfPages := (totalCount / limit) + 1
+	if LimitOfPages < page {
+		log.Println("no more pages")
+		return dao.Pagination{}, apperr.New(apperr.Validation, "no page")
+	}
+	return dao.NewPagination(limit, page, totalCount), nil
+}
+
+func decodeCursor(limit int, after string) (dao.Cursor, error) {
+	if limit <= 0 {
+		log.Println("no limit")
+		return dao.Cursor{}, apperr.New(apperr.Validation, "no limit")
+	}
+	return dao.DecodeCursor(after)
+}
+
+// ordersPage trims the extra row requested from the database and, if it was
+// there, builds the cursor of the next page from the last returned order.
+func ordersPage(orders []dao.Order, limit int) ([]dao.Order, dao.CursorPagination) {
+	pagination := dao.CursorPagination{Limit: limit}
+	if len(orders) > limit {
+		orders = orders[:limit]
+		pagination.NextCursor = dao.CursorFromOrder(orders[limit-1]).Encode()
+	}
+	return orders, pagination
+}
+
+func detailedOrdersPage(orders []dao.DetailedOrder, limit int) ([]dao.DetailedOrder, dao.CursorPagination) {
+	pagination := dao.CursorPagination{Limit: limit}
+	if len(orders) > limit {
+		orders = orders[:limit]
+		pagination.NextCursor = dao.CursorFromDetailedOrder(orders[limit-1]).Encode()
+	}
+	return orders, pagination
+}
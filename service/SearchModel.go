@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"strings"
+)
+
+func (s *CourierService) Search(ctx context.Context, idService int, query string, limit int) (*dao.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		err := apperr.New(apperr.Validation, "empty query")
+		log.Println(err)
+		return nil, fmt.Errorf("Error in SearchService: %w", err)
+	}
+	couriers, err := s.repo.SearchCouriersInDB(ctx, idService, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("Error in SearchService: %w", err)
+	}
+	orders, err := s.repo.SearchOrdersInDB(ctx, idService, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("Error in SearchService: %w", err)
+	}
+	return &dao.SearchResult{Couriers: couriers, Orders: orders}, nil
+}
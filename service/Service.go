@@ -1,57 +1,114 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"google.golang.org/protobuf/types/known/emptypb"
 	courierProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPC"
 	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC/grpcClient"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/policy"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/storage"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/tokencache"
 )
 
 //go:generate mockgen -source=Service.go -destination=mocks/mock.go
 
 type AllProjectApp interface {
-	GetOrder(id int) (dao.Order, error)
-	GetOrders(id int) ([]dao.Order, error)
-	ChangeOrderStatus(text string, id uint16) (uint16, error)
-	GetOrderForChange(id int) (dao.Order, error)
-	GetCourierCompletedOrders(limit, page, idCourier int) ([]dao.DetailedOrder, error)
-	GetAllOrdersOfCourierService(limit, page, idService int) ([]dao.DetailedOrder, error)
-	GetCourierCompletedOrdersByMonth(limit, page, idService, Month, Year int) ([]dao.Order, error)
-	AssigningOrderToCourier(order dao.Order) error
-	GetDetailedOrderById(Id int) (*dao.AllInfoAboutOrder, error)
-	CreateOrder(order *courierProto.OrderCourierServer) (*emptypb.Empty, error)
-	GetServices(in *emptypb.Empty) (*courierProto.ServicesResponse, error)
-	GetCompletedOrdersOfCourierService(limit, page, idService int) ([]dao.Order, error)
-	GetCompletedOrdersOfCourierServiceByDate(limit, page, idService int) ([]dao.Order, error)
-	GetCompletedOrdersOfCourierServiceByCourierId(limit, page, idService int) ([]dao.Order, error)
-	GetOrdersOfCourierServiceForManager(limit, page, idService int) ([]dao.DetailedOrder, error)
-
-	GetCouriers() ([]dao.SmallInfo, error)
-	GetCourier(id int) (dao.Courier, error)
-	SaveCourier(courier *dao.Courier) (*dao.Courier, error)
-	UpdateCourier(id uint16, status bool) (uint16, error)
-	NewUpdateCourier(courier dao.Courier) error
-	SaveCourierPhoto(cover []byte, id int) error
-	GetCouriersOfCourierService(limit, page, idService int) ([]dao.Courier, error)
-
-	CreateDeliveryService(DeliveryService dao.DeliveryService) (int, error)
-	GetDeliveryServiceById(Id int) (*dao.DeliveryService, error)
-	GetAllDeliveryServices() ([]dao.DeliveryService, error)
-	UpdateDeliveryService(service dao.DeliveryService) error
-	SaveLogoFile(cover []byte, id int) error
-
-	ParseToken(token string) (*authProto.UserRole, error)
-	CheckRole(neededRoles []string, givenRole string) error
-	CheckRights(neededPerms []string, givenPerms string) error
+	GetOrder(ctx context.Context, id int) (dao.Order, error)
+	GetOrders(ctx context.Context, id int) ([]dao.Order, error)
+	ChangeOrderStatus(ctx context.Context, text string, id uint16, version int) (uint16, error)
+	GetOrderForChange(ctx context.Context, id int) (dao.Order, error)
+	GetCourierCompletedOrders(ctx context.Context, limit, page, idCourier int) ([]dao.DetailedOrder, dao.Pagination, error)
+	GetAllOrdersOfCourierService(ctx context.Context, limit, page, idService int) ([]dao.DetailedOrder, dao.Pagination, error)
+	GetCourierCompletedOrdersByMonth(ctx context.Context, limit, page, idService, Month, Year int) ([]dao.Order, dao.Pagination, error)
+	AssigningOrderToCourier(ctx context.Context, order dao.Order) error
+	GetDetailedOrderById(ctx context.Context, Id int) (*dao.AllInfoAboutOrder, error)
+	CreateOrder(ctx context.Context, order *courierProto.OrderCourierServer) (*emptypb.Empty, error)
+	GetServices(ctx context.Context, in *emptypb.Empty) (*courierProto.ServicesResponse, error)
+	GetCompletedOrdersOfCourierService(ctx context.Context, limit, page, idService int) ([]dao.Order, dao.Pagination, error)
+	GetCompletedOrdersOfCourierServiceByDate(ctx context.Context, limit, page, idService int) ([]dao.Order, dao.Pagination, error)
+	GetCompletedOrdersOfCourierServiceByCourierId(ctx context.Context, limit, page, idService int) ([]dao.Order, dao.Pagination, error)
+	GetOrdersOfCourierServiceForManager(ctx context.Context, limit, page, idService int) ([]dao.DetailedOrder, dao.Pagination, error)
+	GetCourierCompletedOrdersAfterCursor(ctx context.Context, limit int, after string, idCourier int) ([]dao.DetailedOrder, dao.CursorPagination, error)
+	GetAllOrdersOfCourierServiceAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.DetailedOrder, dao.CursorPagination, error)
+	GetCourierCompletedOrdersByMonthAfterCursor(ctx context.Context, limit int, after string, idCourier, Month, Year int) ([]dao.Order, dao.CursorPagination, error)
+	GetCourierEarnings(ctx context.Context, idCourier, month, year int) (dao.Earnings, error)
+	GetCompletedOrdersOfCourierServiceAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.Order, dao.CursorPagination, error)
+	GetCompletedOrdersOfCourierServiceByDateAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.Order, dao.CursorPagination, error)
+	GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.Order, dao.CursorPagination, error)
+	GetOrdersOfCourierServiceForManagerAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.DetailedOrder, dao.CursorPagination, error)
+
+	GetCouriers(ctx context.Context, idService int) ([]dao.SmallInfo, error)
+	GetCourier(ctx context.Context, id int) (dao.Courier, error)
+	SaveCourier(ctx context.Context, courier *dao.Courier) (*dao.Courier, error)
+	UpdateCourier(ctx context.Context, id uint16, status bool) (uint16, error)
+	NewUpdateCourier(ctx context.Context, courier dao.Courier) error
+	PatchCourier(ctx context.Context, id int, patch dao.CourierPatch) (dao.Courier, error)
+	SaveCourierPhoto(ctx context.Context, cover []byte, id int) error
+	GetCouriersOfCourierService(ctx context.Context, limit, page, idService int) ([]dao.Courier, dao.Pagination, error)
+	SetCourierReadyToGo(ctx context.Context, courierId int, ready bool) error
+	SubmitCourierApplication(ctx context.Context, courierId int) error
+	ReviewCourierApplication(ctx context.Context, courierId int, approve bool, reason string) error
+	GetCourierApplications(ctx context.Context, idService int) ([]dao.Courier, error)
+
+	SaveCourierDocument(ctx context.Context, document dao.CourierDocument) (int, error)
+	GetCourierDocuments(ctx context.Context, courierId int) ([]dao.CourierDocument, error)
+	GetExpiringDocuments(ctx context.Context, idService, days int) ([]dao.ExpiringDocument, error)
+	GetDocumentWarnings(ctx context.Context, idService int) ([]dao.DocumentWarning, error)
+	AcknowledgeDocumentWarning(ctx context.Context, idService, id int) error
+	CheckDocumentExpiry(ctx context.Context, days int) error
+
+	SaveVehicle(ctx context.Context, vehicle dao.Vehicle) (int, error)
+	GetVehicleOfCourier(ctx context.Context, courierId int) (*dao.Vehicle, error)
+	DeleteVehicleOfCourier(ctx context.Context, courierId int) error
+
+	CreateDeliveryService(ctx context.Context, DeliveryService dao.DeliveryService) (int, error)
+	GetDeliveryServiceById(ctx context.Context, Id int) (*dao.DeliveryService, error)
+	GetAllDeliveryServices(ctx context.Context) ([]dao.DeliveryService, error)
+	UpdateDeliveryService(ctx context.Context, service dao.DeliveryService) error
+	PatchDeliveryService(ctx context.Context, id int, patch dao.DeliveryServicePatch) (dao.DeliveryService, error)
+	SaveLogoFile(ctx context.Context, cover []byte, id int) error
+	GetFile(ctx context.Context, key string) ([]byte, string, error)
+	GetProofOfDelivery(ctx context.Context, orderId int) ([]byte, string, error)
+	GetDocumentFile(ctx context.Context, courierId int, documentType string) ([]byte, string, error)
+	CreateUpload(ctx context.Context, upload dao.Upload, userId int) (*dao.PresignedUpload, error)
+	CompleteUpload(ctx context.Context, id string, userId int, role string) (string, error)
+
+	Search(ctx context.Context, idService int, query string, limit int) (*dao.SearchResult, error)
+	ResolveCaller(ctx context.Context, userId int, role string) (dao.Caller, error)
+	CheckCourierAccess(ctx context.Context, caller dao.Caller, courierId int) error
+	CheckOrderAccess(ctx context.Context, caller dao.Caller, orderId int) error
+	CheckDocumentAccess(ctx context.Context, caller dao.Caller, documentId int) error
+
+	ParseToken(ctx context.Context, token string) (*authProto.UserRole, error)
+	InvalidateToken(token string)
+	InvalidateUserTokens(userId int)
+	TokenCacheStats() tokencache.Stats
+	AuthHealth() grpcClient.Health
+	GetRolePermissions(ctx context.Context) (map[string][]string, error)
 }
 
 type Service struct {
 	AllProjectApp
+	// Policy authorises the requests; the handlers use the default policy
+	// when it is nil.
+	Policy *policy.Policy
 }
 
-func NewService(rep *dao.Repository, grpcCli *grpcClient.GRPCClient) *Service {
+func NewService(rep *dao.Repository, grpcCli *grpcClient.GRPCClient, store storage.BlobStore) *Service {
 	return &Service{
-		NewProjectService(*rep, grpcCli),
+		AllProjectApp: NewProjectService(*rep, grpcCli, store),
+	}
+}
+
+// dbError gives a failed database call its kind: a call that ran out of time
+// or whose request went away is Unavailable, anything else Internal.
+func dbError(ctx context.Context, err error) error {
+	if ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return apperr.Wrap(apperr.Unavailable, "database didn't answer in time", err)
 	}
+	return apperr.Wrap(apperr.Internal, "database error", err)
 }
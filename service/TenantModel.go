@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+)
+
+var (
+	ErrOtherTenant      = apperr.New(apperr.Forbidden, "belongs to another delivery service")
+	ErrNoTenant         = apperr.New(apperr.Forbidden, "user has no delivery service")
+	ErrNoCourier        = apperr.New(apperr.NotFound, "user has no courier profile")
+	ErrOrderNotFound    = apperr.New(apperr.NotFound, "order not found")
+	ErrDocumentNotFound = apperr.New(apperr.NotFound, "document not found")
+)
+
+// ResolveCaller finds the delivery service of a courier manager and the
+// courier record of a courier from the user id of the token. A user without
+// them gets zero ids, which give access to nothing.
+func (s *CourierService) ResolveCaller(ctx context.Context, userId int, role string) (dao.Caller, error) {
+	caller := dao.Caller{UserId: userId, Role: role}
+	switch role {
+	case RoleCourierManager:
+		service, err := s.repo.GetDeliveryServiceByIdFromDB(ctx, userId)
+		if err != nil {
+			return dao.Caller{}, fmt.Errorf("Error in TenantService: %w", err)
+		}
+		caller.DeliveryServiceId = service.Id
+	case RoleCourier:
+		courier, err := s.repo.GetCourierFromDB(ctx, userId)
+		if err != nil {
+			return dao.Caller{}, fmt.Errorf("Error in TenantService: %w", err)
+		}
+		caller.CourierId = int(courier.Id)
+		caller.DeliveryServiceId = int(courier.DeliveryServiceId)
+	}
+	return caller, nil
+}
+
+// CheckCourierAccess rejects callers that may not work with the courier.
+func (s *CourierService) CheckCourierAccess(ctx context.Context, caller dao.Caller, courierId int) error {
+	if caller.IsSuperadmin() {
+		return nil
+	}
+	idService, err := s.repo.GetDeliveryServiceOfCourierFromDB(ctx, courierId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in TenantService: %w", ErrCourierNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("Error in TenantService: %w", err)
+	}
+	if !caller.CanAccessCourier(courierId, idService) {
+		return fmt.Errorf("Error in TenantService: %w", apperr.Errorf("courier %d %w", courierId, ErrOtherTenant))
+	}
+	return nil
+}
+
+// CheckOrderAccess rejects callers that may not work with the order.
+func (s *CourierService) CheckOrderAccess(ctx context.Context, caller dao.Caller, orderId int) error {
+	if caller.IsSuperadmin() {
+		return nil
+	}
+	idService, idCourier, err := s.repo.GetOrderOwnerFromDB(ctx, orderId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in TenantService: %w", ErrOrderNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("Error in TenantService: %w", err)
+	}
+	if !caller.CanAccessOrder(idService, idCourier) {
+		return fmt.Errorf("Error in TenantService: %w", apperr.Errorf("order %d %w", orderId, ErrOtherTenant))
+	}
+	return nil
+}
+
+// CheckDocumentAccess rejects callers that may not work with the courier
+// the document belongs to.
+func (s *CourierService) CheckDocumentAccess(ctx context.Context, caller dao.Caller, documentId int) error {
+	if caller.IsSuperadmin() {
+		return nil
+	}
+	courierId, err := s.repo.GetCourierOfDocumentFromDB(ctx, documentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("Error in TenantService: %w", ErrDocumentNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("Error in TenantService: %w", err)
+	}
+	return s.CheckCourierAccess(ctx, caller, courierId)
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/imaging"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/storage"
+	"time"
+)
+
+// Upload kinds say what a directly uploaded file is attached to.
+const (
+	UploadCourierPhoto    = "courier_photo"
+	UploadLogo            = "logo"
+	UploadProofOfDelivery = "proof_of_delivery"
+	UploadCourierDocument = "courier_document"
+)
+
+const (
+	uploadURLExpiry = 15 * time.Minute
+	// uploadCompletionGrace lets a client whose PUT started just before the
+	// URL expired still complete the upload.
+	uploadCompletionGrace = time.Hour
+)
+
+var (
+	ErrUnknownUploadKind       = apperr.New(apperr.Validation, "unknown upload kind")
+	ErrDirectUploadUnsupported = apperr.New(apperr.NotImplemented, "storage does not support direct uploads")
+	ErrUploadNotFound          = apperr.New(apperr.NotFound, "upload not found")
+	ErrUploadExpired           = apperr.New(apperr.Gone, "upload has expired")
+	ErrUploadNotReceived       = apperr.New(apperr.Conflict, "file has not been uploaded yet")
+	ErrUploadCompleted         = apperr.New(apperr.Conflict, "upload is already completed")
+	ErrUploadRejected          = apperr.New(apperr.Unprocessable, "uploaded file was rejected")
+)
+
+type uploadKind struct {
+	contentTypes []string
+	maxSize      int64
+}
+
+var uploadKinds = map[string]uploadKind{
+	UploadCourierPhoto:    {[]string{"image/jpeg", "image/png", "image/gif"}, int64(imaging.DefaultLimits.MaxBytes)},
+	UploadLogo:            {[]string{"image/jpeg", "image/png", "image/gif"}, int64(imaging.DefaultLimits.MaxBytes)},
+	UploadProofOfDelivery: {[]string{"image/jpeg", "image/png", "application/pdf"}, 25 << 20},
+	UploadCourierDocument: {[]string{"image/jpeg", "image/png", "application/pdf"}, 25 << 20},
+}
+
+func (k uploadKind) accepts(contentType string) bool {
+	for _, accepted := range k.contentTypes {
+		if accepted == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateUpload registers a pending upload and presigns a PUT URL the client
+// sends the file to, bypassing the service.
+func (s *CourierService) CreateUpload(ctx context.Context, upload dao.Upload, userId int) (*dao.PresignedUpload, error) {
+	kind, ok := uploadKinds[upload.Kind]
+	if !ok {
+		return nil, fmt.Errorf("Error in UploadService: %w", apperr.Errorf("%w: %q", ErrUnknownUploadKind, upload.Kind))
+	}
+	if !kind.accepts(upload.ContentType) {
+		return nil, fmt.Errorf("Error in UploadService: %w", apperr.Errorf("%w: %s", imaging.ErrUnsupportedType, upload.ContentType))
+	}
+	presigner, ok := s.storage.(storage.Presigner)
+	if !ok {
+		return nil, fmt.Errorf("Error in UploadService: %w", ErrDirectUploadUnsupported)
+	}
+	id, err := newUploadId()
+	if err != nil {
+		return nil, fmt.Errorf("Error in UploadService: %w", err)
+	}
+	upload.Id = id
+	upload.ObjectKey = "uploads/" + upload.Kind + "/" + id
+	upload.UserId = userId
+	upload.Status = "pending"
+	upload.ExpiresAt = time.Now().Add(uploadURLExpiry)
+
+	url, err := presigner.PresignPut(ctx, upload.ObjectKey, uploadURLExpiry)
+	if err != nil {
+		log.Println(err)
+		return nil, fmt.Errorf("Error in UploadService: %w", err)
+	}
+	if err := s.repo.SaveUploadInDB(ctx, &upload); err != nil {
+		return nil, fmt.Errorf("Error in UploadService: %w", err)
+	}
+	return &dao.PresignedUpload{Upload: upload, URL: url, Method: "PUT"}, nil
+}
+
+// CompleteUpload checks that the file of a pending upload has arrived and
+// attaches it to its target. It returns where the attached file is served.
+func (s *CourierService) CompleteUpload(ctx context.Context, id string, userId int, role string) (string, error) {
+	upload, err := s.repo.GetUploadFromDB(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("Error in UploadService: %w", ErrUploadNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Error in UploadService: %w", err)
+	}
+	// Somebody else's upload id is reported as unknown rather than forbidden.
+	if upload.UserId != userId && role != "Superadmin" {
+		return "", fmt.Errorf("Error in UploadService: %w", ErrUploadNotFound)
+	}
+	if upload.Status != "pending" {
+		return "", fmt.Errorf("Error in UploadService: %w", ErrUploadCompleted)
+	}
+	if time.Now().After(upload.ExpiresAt.Add(uploadCompletionGrace)) {
+		return "", fmt.Errorf("Error in UploadService: %w", ErrUploadExpired)
+	}
+	presigner, ok := s.storage.(storage.Presigner)
+	if !ok {
+		return "", fmt.Errorf("Error in UploadService: %w", ErrDirectUploadUnsupported)
+	}
+
+	info, err := presigner.Stat(ctx, upload.ObjectKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return "", fmt.Errorf("Error in UploadService: %w", ErrUploadNotReceived)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Error in UploadService: %w", err)
+	}
+	kind := uploadKinds[upload.Kind]
+	if info.Size == 0 || info.Size > kind.maxSize || info.ContentType != upload.ContentType {
+		s.removeUploadedObject(ctx, upload.ObjectKey)
+		return "", fmt.Errorf("Error in UploadService: %w", apperr.Errorf("%w: %d bytes of %s, expected at most %d bytes of %s",
+			ErrUploadRejected, info.Size, info.ContentType, kind.maxSize, upload.ContentType))
+	}
+
+	// Photos and logos go through the same processing as uploads sent to
+	// the service, so their metadata is stripped and variants made. That
+	// happens before the transaction, under a key of their own, so a failed
+	// completion removes them without touching the current image.
+	var image imageURLs
+	imageKey := ""
+	switch upload.Kind {
+	case UploadCourierPhoto:
+		imageKey = courierPhotoKey(upload.TargetId) + "-" + upload.Id
+	case UploadLogo:
+		imageKey = logoKey(upload.TargetId) + "-" + upload.Id
+	}
+	if imageKey != "" {
+		data, _, err := s.storage.Get(ctx, upload.ObjectKey)
+		if err != nil {
+			return "", fmt.Errorf("Error in UploadService: %w", err)
+		}
+		image, err = s.saveImage(ctx, imageKey, data)
+		if err != nil {
+			log.Println(err)
+			return "", fmt.Errorf("Error in UploadService: %w", err)
+		}
+	}
+
+	// The upload is claimed first, in the transaction that attaches it, so
+	// of concurrent completions only one attaches the file and a failed one
+	// leaves the upload pending with its file for a retry.
+	var url string
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		completed, err := s.repo.CompleteUploadInDB(ctx, upload.Id)
+		if err != nil {
+			return fmt.Errorf("Error in UploadService: %w", err)
+		}
+		if !completed {
+			return fmt.Errorf("Error in UploadService: %w", ErrUploadCompleted)
+		}
+		url, err = s.attachUpload(ctx, upload, image)
+		return err
+	})
+	if err != nil {
+		if imageKey != "" {
+			s.deleteImage(ctx, imageKey)
+		}
+		return "", err
+	}
+	if imageKey != "" {
+		s.removeUploadedObject(ctx, upload.ObjectKey)
+	}
+	return url, nil
+}
+
+// attachUpload attaches the file of the upload to its target and returns
+// where it is served. Photos and logos are attached as the image stored from
+// them; proofs of delivery and documents keep their uploaded object.
+func (s *CourierService) attachUpload(ctx context.Context, upload *dao.Upload, image imageURLs) (string, error) {
+	switch upload.Kind {
+	case UploadCourierPhoto:
+		if err := s.setCourierPhoto(ctx, upload.TargetId, image); err != nil {
+			return "", err
+		}
+		return image.Original, nil
+	case UploadLogo:
+		if err := s.setLogo(ctx, upload.TargetId, image); err != nil {
+			return "", err
+		}
+		return image.Original, nil
+	case UploadProofOfDelivery:
+		if err := s.repo.SetProofOfDeliveryInDB(ctx, upload.TargetId, upload.ObjectKey); err != nil {
+			return "", fmt.Errorf("Error in UploadService: %w", err)
+		}
+		return ProofOfDeliveryPath(upload.TargetId), nil
+	case UploadCourierDocument:
+		courierId, documentType, err := s.repo.SetDocumentFileInDB(ctx, upload.TargetId, upload.ObjectKey)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("Error in UploadService: %w", ErrDocumentNotFound)
+		}
+		if err != nil {
+			return "", fmt.Errorf("Error in UploadService: %w", err)
+		}
+		return DocumentFilePath(courierId, documentType), nil
+	}
+	return "", fmt.Errorf("Error in UploadService: %w", apperr.Errorf("%w: %q", ErrUnknownUploadKind, upload.Kind))
+}
+
+func (s *CourierService) removeUploadedObject(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Println(err)
+	}
+}
+
+func newUploadId() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/png"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/storage"
+	"testing"
+	"time"
+)
+
+// directStore is a memory store clients could upload to directly.
+type directStore struct {
+	*storage.MemoryStore
+}
+
+func (s directStore) PresignPut(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return s.URL(key), nil
+}
+
+func (s directStore) Stat(ctx context.Context, key string) (storage.ObjectInfo, error) {
+	data, contentType, err := s.Get(ctx, key)
+	return storage.ObjectInfo{Size: int64(len(data)), ContentType: contentType}, err
+}
+
+func testPNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func expectUpload(mock sqlmock.Sqlmock, kind, key, contentType string) {
+	mock.ExpectQuery(`SELECT id, kind, target_id, object_key, content_type, user_id, status, expires_at\s+FROM uploads WHERE id = \$1`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "target_id", "object_key", "content_type", "user_id", "status", "expires_at"}).
+			AddRow("u1", kind, 3, key, contentType, 9, "pending", time.Now().Add(time.Minute)))
+}
+
+func TestCourierService_CompleteUpload(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	store := directStore{storage.NewMemoryStore("http://files")}
+	s := NewProjectService(*dao.NewRepository(db, dao.Config{}), nil, store)
+	key := "uploads/proof_of_delivery/u1"
+	assert.NoError(t, store.Put(context.Background(), key, []byte("%PDF"), "application/pdf"))
+
+	expectUpload(mock, UploadProofOfDelivery, key, "application/pdf")
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE uploads SET status = 'completed'`).WithArgs("u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE delivery SET proof_of_delivery = \$1 WHERE id = \$2`).
+		WithArgs(key, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	url, err := s.CompleteUpload(context.Background(), "u1", 9, "Courier")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/order/3/proof_of_delivery", url)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourierService_GetFile_Private(t *testing.T) {
+	store := storage.NewMemoryStore("http://files")
+	s := NewProjectService(dao.Repository{}, nil, store)
+	assert.NoError(t, store.Put(context.Background(), "uploads/proof_of_delivery/u1", []byte("%PDF"), "application/pdf"))
+	assert.NoError(t, store.Put(context.Background(), "courier_photo/3", testPNG(t), "image/png"))
+
+	_, _, err := s.GetFile(context.Background(), "/uploads/proof_of_delivery/u1")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, _, err = s.GetFile(context.Background(), "/courier_photo/../uploads/proof_of_delivery/u1")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, contentType, err := s.GetFile(context.Background(), "/courier_photo/3")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+}
+
+func TestCourierService_GetProofOfDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	store := storage.NewMemoryStore("http://files")
+	s := NewProjectService(*dao.NewRepository(db, dao.Config{}), nil, store)
+	assert.NoError(t, store.Put(context.Background(), "uploads/proof_of_delivery/u1", []byte("%PDF"), "application/pdf"))
+
+	query := `SELECT coalesce\(proof_of_delivery, ''\) FROM delivery WHERE id = \$1`
+	mock.ExpectQuery(query).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"proof_of_delivery"}).AddRow("uploads/proof_of_delivery/u1"))
+	mock.ExpectQuery(query).WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"proof_of_delivery"}).AddRow("http://files/uploads/proof_of_delivery/u1"))
+	mock.ExpectQuery(query).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"proof_of_delivery"}).AddRow(""))
+
+	data, _, err := s.GetProofOfDelivery(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "%PDF", string(data))
+	data, _, err = s.GetProofOfDelivery(context.Background(), 4)
+	assert.NoError(t, err, "a proof kept as a URL before proofs became private")
+	assert.Equal(t, "%PDF", string(data))
+	_, _, err = s.GetProofOfDelivery(context.Background(), 5)
+	assert.ErrorIs(t, err, ErrFileNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourierService_CompleteUpload_CompletedConcurrently(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	store := directStore{storage.NewMemoryStore("http://files")}
+	s := NewProjectService(*dao.NewRepository(db, dao.Config{}), nil, store)
+	key := "uploads/courier_photo/u1"
+	assert.NoError(t, store.Put(context.Background(), key, testPNG(t), "image/png"))
+
+	expectUpload(mock, UploadCourierPhoto, key, "image/png")
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE uploads SET status = 'completed'`).WithArgs("u1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = s.CompleteUpload(context.Background(), "u1", 9, "Courier")
+
+	assert.ErrorIs(t, err, ErrUploadCompleted)
+	_, _, err = store.Get(context.Background(), key)
+	assert.NoError(t, err, "the other completion still reads the object")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourierService_CompleteUpload_AttachFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	store := directStore{storage.NewMemoryStore("http://files")}
+	s := NewProjectService(*dao.NewRepository(db, dao.Config{}), nil, store)
+	key := "uploads/courier_photo/u1"
+	assert.NoError(t, store.Put(context.Background(), key, testPNG(t), "image/png"))
+
+	failed := errors.New("connection reset")
+	expectUpload(mock, UploadCourierPhoto, key, "image/png")
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE uploads SET status = 'completed'`).WithArgs("u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id_courier, (.+) FROM couriers Where id_courier=\$1 FOR UPDATE`).WithArgs(3).WillReturnError(failed)
+	mock.ExpectRollback()
+
+	_, err = s.CompleteUpload(context.Background(), "u1", 9, "Courier")
+
+	assert.ErrorIs(t, err, failed)
+	_, _, err = store.Get(context.Background(), key)
+	assert.NoError(t, err, "a retry still finds the object")
+	for _, variant := range []string{"courier_photo/3-u1", "courier_photo/3-u1-medium", "courier_photo/3-u1-thumbnail"} {
+		_, _, err = store.Get(context.Background(), variant)
+		assert.ErrorIs(t, err, storage.ErrNotFound, variant)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourierService_CompleteUpload_Photo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	store := directStore{storage.NewMemoryStore("http://files")}
+	s := NewProjectService(*dao.NewRepository(db, dao.Config{}), nil, store)
+	key := "uploads/courier_photo/u1"
+	assert.NoError(t, store.Put(context.Background(), key, testPNG(t), "image/png"))
+
+	expectUpload(mock, UploadCourierPhoto, key, "image/png")
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE uploads SET status = 'completed'`).WithArgs("u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id_courier, (.+) FROM couriers Where id_courier=\$1 FOR UPDATE`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id_courier", "name", "surname", "delivery_service_id", "email", "photo", "photo_medium", "photo_thumbnail", "phone_number", "version"}).
+			AddRow(3, "Ivan", "Petrov", 1, "", "", "", "", "", 1))
+	mock.ExpectExec(`UPDATE couriers SET name=\$1`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	url, err := s.CompleteUpload(context.Background(), "u1", 9, "Courier")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http://files/courier_photo/3-u1", url)
+	_, _, err = store.Get(context.Background(), key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, _, err = store.Get(context.Background(), "courier_photo/3-u1-thumbnail")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"strings"
+)
+
+const (
+	VehicleFoot    = "foot"
+	VehicleBike    = "bike"
+	VehicleScooter = "scooter"
+	VehicleCar     = "car"
+)
+
+// Order sizes, from a sandwich bag to a catering order.
+var orderSizes = map[string]int{"small": 1, "medium": 2, "large": 3, "xl": 4}
+
+type vehicleType struct {
+	// capacityKg is used when the courier didn't give the vehicle's own.
+	capacityKg float64
+	// maxDistanceKm of 0 means no limit.
+	maxDistanceKm float64
+	maxSize       string
+	needsPlate    bool
+}
+
+var vehicleTypes = map[string]vehicleType{
+	VehicleFoot:    {capacityKg: 5, maxDistanceKm: 3, maxSize: "medium"},
+	VehicleBike:    {capacityKg: 15, maxDistanceKm: 10, maxSize: "medium"},
+	VehicleScooter: {capacityKg: 30, maxDistanceKm: 30, maxSize: "large", needsPlate: true},
+	VehicleCar:     {capacityKg: 300, maxSize: "xl", needsPlate: true},
+}
+
+var (
+	ErrInvalidVehicle  = apperr.New(apperr.Validation, "invalid vehicle")
+	ErrVehicleMismatch = apperr.New(apperr.Conflict, "order doesn't fit the courier's vehicle")
+	ErrInvalidOrder    = apperr.New(apperr.Validation, "invalid order")
+)
+
+func (s *CourierService) SaveVehicle(ctx context.Context, vehicle dao.Vehicle) (int, error) {
+	vehicle.Type = strings.ToLower(strings.TrimSpace(vehicle.Type))
+	vehicle.Plate = strings.ToUpper(strings.TrimSpace(vehicle.Plate))
+	kind, ok := vehicleTypes[vehicle.Type]
+	if !ok {
+		return 0, fmt.Errorf("Error in VehicleService: %w", apperr.Errorf("%w: type must be foot, bike, scooter or car", ErrInvalidVehicle))
+	}
+	if vehicle.CapacityKg < 0 {
+		return 0, fmt.Errorf("Error in VehicleService: %w", apperr.Errorf("%w: capacity_kg can't be negative", ErrInvalidVehicle))
+	}
+	if kind.needsPlate && vehicle.Plate == "" {
+		return 0, fmt.Errorf("Error in VehicleService: %w", apperr.Errorf("%w: a %s needs a plate", ErrInvalidVehicle, vehicle.Type))
+	}
+	id, err := s.repo.SaveVehicleInDB(ctx, &vehicle)
+	if err != nil {
+		return 0, fmt.Errorf("Error in VehicleService: %w", err)
+	}
+	return id, nil
+}
+
+// GetVehicleOfCourier returns nil when the courier has no vehicle registered.
+func (s *CourierService) GetVehicleOfCourier(ctx context.Context, courierId int) (*dao.Vehicle, error) {
+	vehicle, err := s.repo.GetVehicleOfCourierFromDB(ctx, courierId)
+	if err != nil {
+		return nil, fmt.Errorf("Error in VehicleService: %w", err)
+	}
+	return vehicle, nil
+}
+
+func (s *CourierService) DeleteVehicleOfCourier(ctx context.Context, courierId int) error {
+	if err := s.repo.DeleteVehicleOfCourierFromDB(ctx, courierId); err != nil {
+		return fmt.Errorf("Error in VehicleService: %w", err)
+	}
+	return nil
+}
+
+// vehicleFits checks an order's load against a vehicle. A courier without a
+// registered vehicle is taken to be on foot; unknown hints fit any vehicle.
+func vehicleFits(vehicle *dao.Vehicle, load dao.OrderLoad) error {
+	if vehicle == nil {
+		vehicle = &dao.Vehicle{Type: VehicleFoot}
+	}
+	kind := vehicleTypes[vehicle.Type]
+	capacity := vehicle.CapacityKg
+	if capacity == 0 {
+		capacity = kind.capacityKg
+	}
+	switch {
+	case load.WeightKg > capacity:
+		return apperr.Errorf("%w: %.1f kg is more than the %s carries (%.1f kg)", ErrVehicleMismatch, load.WeightKg, vehicle.Type, capacity)
+	case kind.maxDistanceKm > 0 && load.DistanceKm > kind.maxDistanceKm:
+		return apperr.Errorf("%w: %.1f km is too far for a %s courier (%.0f km at most)", ErrVehicleMismatch, load.DistanceKm, vehicle.Type, kind.maxDistanceKm)
+	case orderSizes[load.Size] > orderSizes[kind.maxSize]:
+		return apperr.Errorf("%w: a %s order doesn't fit a %s", ErrVehicleMismatch, load.Size, vehicle.Type)
+	}
+	return nil
+}
+
+// checkCourierCanTakeOrder is the gate every assignment goes through: the
+// courier must be approved, the mandatory documents valid and the vehicle
+// must fit.
+func (s *CourierService) checkCourierCanTakeOrder(ctx context.Context, courierId, orderId int) error {
+	if err := s.checkCourierApproved(ctx, courierId); err != nil {
+		return err
+	}
+	if err := s.checkCourierDocuments(ctx, courierId); err != nil {
+		return err
+	}
+	load, err := s.repo.GetOrderLoadFromDB(ctx, orderId)
+	if err != nil {
+		return fmt.Errorf("Error in OrderService: %w", err)
+	}
+	vehicle, err := s.repo.GetVehicleOfCourierFromDB(ctx, courierId)
+	if err != nil {
+		return fmt.Errorf("Error in VehicleService: %w", err)
+	}
+	if err := vehicleFits(vehicle, load); err != nil {
+		return fmt.Errorf("Error in VehicleService: %w", err)
+	}
+	return nil
+}
+
+func validateOrderLoad(load dao.OrderLoad) error {
+	if load.WeightKg < 0 || load.DistanceKm < 0 {
+		return apperr.Errorf("%w: weight and distance can't be negative", ErrInvalidOrder)
+	}
+	if _, ok := orderSizes[load.Size]; load.Size != "" && !ok {
+		return apperr.Errorf("%w: size must be small, medium, large or xl, got %q", ErrInvalidOrder, load.Size)
+	}
+	return nil
+}
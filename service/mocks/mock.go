@@ -5,13 +5,16 @@
 package mock_service
 
 import (
+	context "context"
 	reflect "reflect"
 
+	gomock "github.com/golang/mock/gomock"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	courierProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPC"
 	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	grpcClient "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC/grpcClient"
 	dao "stlab.itechart-group.com/go/food_delivery/courier_service/dao"
-	gomock "github.com/golang/mock/gomock"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	tokencache "stlab.itechart-group.com/go/food_delivery/courier_service/pkg/tokencache"
 )
 
 // MockAllProjectApp is a mock of AllProjectApp interface.
@@ -37,445 +40,988 @@ func (m *MockAllProjectApp) EXPECT() *MockAllProjectAppMockRecorder {
 	return m.recorder
 }
 
+// AcknowledgeDocumentWarning mocks base method.
+func (m *MockAllProjectApp) AcknowledgeDocumentWarning(ctx context.Context, idService, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcknowledgeDocumentWarning", ctx, idService, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcknowledgeDocumentWarning indicates an expected call of AcknowledgeDocumentWarning.
+func (mr *MockAllProjectAppMockRecorder) AcknowledgeDocumentWarning(ctx, idService, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcknowledgeDocumentWarning", reflect.TypeOf((*MockAllProjectApp)(nil).AcknowledgeDocumentWarning), ctx, idService, id)
+}
+
 // AssigningOrderToCourier mocks base method.
-func (m *MockAllProjectApp) AssigningOrderToCourier(order dao.Order) error {
+func (m *MockAllProjectApp) AssigningOrderToCourier(ctx context.Context, order dao.Order) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AssigningOrderToCourier", order)
+	ret := m.ctrl.Call(m, "AssigningOrderToCourier", ctx, order)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AssigningOrderToCourier indicates an expected call of AssigningOrderToCourier.
-func (mr *MockAllProjectAppMockRecorder) AssigningOrderToCourier(order interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) AssigningOrderToCourier(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssigningOrderToCourier", reflect.TypeOf((*MockAllProjectApp)(nil).AssigningOrderToCourier), ctx, order)
+}
+
+// AuthHealth mocks base method.
+func (m *MockAllProjectApp) AuthHealth() grpcClient.Health {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthHealth")
+	ret0, _ := ret[0].(grpcClient.Health)
+	return ret0
+}
+
+// AuthHealth indicates an expected call of AuthHealth.
+func (mr *MockAllProjectAppMockRecorder) AuthHealth() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssigningOrderToCourier", reflect.TypeOf((*MockAllProjectApp)(nil).AssigningOrderToCourier), order)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthHealth", reflect.TypeOf((*MockAllProjectApp)(nil).AuthHealth))
 }
 
 // ChangeOrderStatus mocks base method.
-func (m *MockAllProjectApp) ChangeOrderStatus(text string, id uint16) (uint16, error) {
+func (m *MockAllProjectApp) ChangeOrderStatus(ctx context.Context, text string, id uint16, version int) (uint16, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ChangeOrderStatus", text, id)
+	ret := m.ctrl.Call(m, "ChangeOrderStatus", ctx, text, id, version)
 	ret0, _ := ret[0].(uint16)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ChangeOrderStatus indicates an expected call of ChangeOrderStatus.
-func (mr *MockAllProjectAppMockRecorder) ChangeOrderStatus(text, id interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) ChangeOrderStatus(ctx, text, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeOrderStatus", reflect.TypeOf((*MockAllProjectApp)(nil).ChangeOrderStatus), text, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeOrderStatus", reflect.TypeOf((*MockAllProjectApp)(nil).ChangeOrderStatus), ctx, text, id, version)
 }
 
-// CheckRights mocks base method.
-func (m *MockAllProjectApp) CheckRights(neededPerms []string, givenPerms string) error {
+// CheckCourierAccess mocks base method.
+func (m *MockAllProjectApp) CheckCourierAccess(ctx context.Context, caller dao.Caller, courierId int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckRights", neededPerms, givenPerms)
+	ret := m.ctrl.Call(m, "CheckCourierAccess", ctx, caller, courierId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CheckRights indicates an expected call of CheckRights.
-func (mr *MockAllProjectAppMockRecorder) CheckRights(neededPerms, givenPerms interface{}) *gomock.Call {
+// CheckCourierAccess indicates an expected call of CheckCourierAccess.
+func (mr *MockAllProjectAppMockRecorder) CheckCourierAccess(ctx, caller, courierId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRights", reflect.TypeOf((*MockAllProjectApp)(nil).CheckRights), neededPerms, givenPerms)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckCourierAccess", reflect.TypeOf((*MockAllProjectApp)(nil).CheckCourierAccess), ctx, caller, courierId)
 }
 
-// CheckRole mocks base method.
-func (m *MockAllProjectApp) CheckRole(neededRoles []string, givenRole string) error {
+// CheckDocumentAccess mocks base method.
+func (m *MockAllProjectApp) CheckDocumentAccess(ctx context.Context, caller dao.Caller, documentId int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckRole", neededRoles, givenRole)
+	ret := m.ctrl.Call(m, "CheckDocumentAccess", ctx, caller, documentId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CheckRole indicates an expected call of CheckRole.
-func (mr *MockAllProjectAppMockRecorder) CheckRole(neededRoles, givenRole interface{}) *gomock.Call {
+// CheckDocumentAccess indicates an expected call of CheckDocumentAccess.
+func (mr *MockAllProjectAppMockRecorder) CheckDocumentAccess(ctx, caller, documentId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRole", reflect.TypeOf((*MockAllProjectApp)(nil).CheckRole), neededRoles, givenRole)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDocumentAccess", reflect.TypeOf((*MockAllProjectApp)(nil).CheckDocumentAccess), ctx, caller, documentId)
+}
+
+// CheckDocumentExpiry mocks base method.
+func (m *MockAllProjectApp) CheckDocumentExpiry(ctx context.Context, days int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckDocumentExpiry", ctx, days)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckDocumentExpiry indicates an expected call of CheckDocumentExpiry.
+func (mr *MockAllProjectAppMockRecorder) CheckDocumentExpiry(ctx, days interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDocumentExpiry", reflect.TypeOf((*MockAllProjectApp)(nil).CheckDocumentExpiry), ctx, days)
+}
+
+// CheckOrderAccess mocks base method.
+func (m *MockAllProjectApp) CheckOrderAccess(ctx context.Context, caller dao.Caller, orderId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckOrderAccess", ctx, caller, orderId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckOrderAccess indicates an expected call of CheckOrderAccess.
+func (mr *MockAllProjectAppMockRecorder) CheckOrderAccess(ctx, caller, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckOrderAccess", reflect.TypeOf((*MockAllProjectApp)(nil).CheckOrderAccess), ctx, caller, orderId)
+}
+
+// CompleteUpload mocks base method.
+func (m *MockAllProjectApp) CompleteUpload(ctx context.Context, id string, userId int, role string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteUpload", ctx, id, userId, role)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteUpload indicates an expected call of CompleteUpload.
+func (mr *MockAllProjectAppMockRecorder) CompleteUpload(ctx, id, userId, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteUpload", reflect.TypeOf((*MockAllProjectApp)(nil).CompleteUpload), ctx, id, userId, role)
 }
 
 // CreateDeliveryService mocks base method.
-func (m *MockAllProjectApp) CreateDeliveryService(DeliveryService dao.DeliveryService) (int, error) {
+func (m *MockAllProjectApp) CreateDeliveryService(ctx context.Context, DeliveryService dao.DeliveryService) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateDeliveryService", DeliveryService)
+	ret := m.ctrl.Call(m, "CreateDeliveryService", ctx, DeliveryService)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateDeliveryService indicates an expected call of CreateDeliveryService.
-func (mr *MockAllProjectAppMockRecorder) CreateDeliveryService(DeliveryService interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) CreateDeliveryService(ctx, DeliveryService interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeliveryService", reflect.TypeOf((*MockAllProjectApp)(nil).CreateDeliveryService), DeliveryService)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeliveryService", reflect.TypeOf((*MockAllProjectApp)(nil).CreateDeliveryService), ctx, DeliveryService)
 }
 
 // CreateOrder mocks base method.
-func (m *MockAllProjectApp) CreateOrder(order *courierProto.OrderCourierServer) (*emptypb.Empty, error) {
+func (m *MockAllProjectApp) CreateOrder(ctx context.Context, order *courierProto.OrderCourierServer) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrder", order)
+	ret := m.ctrl.Call(m, "CreateOrder", ctx, order)
 	ret0, _ := ret[0].(*emptypb.Empty)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateOrder indicates an expected call of CreateOrder.
-func (mr *MockAllProjectAppMockRecorder) CreateOrder(order interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) CreateOrder(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockAllProjectApp)(nil).CreateOrder), ctx, order)
+}
+
+// CreateUpload mocks base method.
+func (m *MockAllProjectApp) CreateUpload(ctx context.Context, upload dao.Upload, userId int) (*dao.PresignedUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUpload", ctx, upload, userId)
+	ret0, _ := ret[0].(*dao.PresignedUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUpload indicates an expected call of CreateUpload.
+func (mr *MockAllProjectAppMockRecorder) CreateUpload(ctx, upload, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUpload", reflect.TypeOf((*MockAllProjectApp)(nil).CreateUpload), ctx, upload, userId)
+}
+
+// DeleteVehicleOfCourier mocks base method.
+func (m *MockAllProjectApp) DeleteVehicleOfCourier(ctx context.Context, courierId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVehicleOfCourier", ctx, courierId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVehicleOfCourier indicates an expected call of DeleteVehicleOfCourier.
+func (mr *MockAllProjectAppMockRecorder) DeleteVehicleOfCourier(ctx, courierId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockAllProjectApp)(nil).CreateOrder), order)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVehicleOfCourier", reflect.TypeOf((*MockAllProjectApp)(nil).DeleteVehicleOfCourier), ctx, courierId)
 }
 
 // GetAllDeliveryServices mocks base method.
-func (m *MockAllProjectApp) GetAllDeliveryServices() ([]dao.DeliveryService, error) {
+func (m *MockAllProjectApp) GetAllDeliveryServices(ctx context.Context) ([]dao.DeliveryService, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllDeliveryServices")
+	ret := m.ctrl.Call(m, "GetAllDeliveryServices", ctx)
 	ret0, _ := ret[0].([]dao.DeliveryService)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllDeliveryServices indicates an expected call of GetAllDeliveryServices.
-func (mr *MockAllProjectAppMockRecorder) GetAllDeliveryServices() *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetAllDeliveryServices(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllDeliveryServices", reflect.TypeOf((*MockAllProjectApp)(nil).GetAllDeliveryServices))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllDeliveryServices", reflect.TypeOf((*MockAllProjectApp)(nil).GetAllDeliveryServices), ctx)
 }
 
 // GetAllOrdersOfCourierService mocks base method.
-func (m *MockAllProjectApp) GetAllOrdersOfCourierService(limit, page, idService int) ([]dao.DetailedOrder, error) {
+func (m *MockAllProjectApp) GetAllOrdersOfCourierService(ctx context.Context, limit, page, idService int) ([]dao.DetailedOrder, dao.Pagination, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllOrdersOfCourierService", limit, page, idService)
+	ret := m.ctrl.Call(m, "GetAllOrdersOfCourierService", ctx, limit, page, idService)
 	ret0, _ := ret[0].([]dao.DetailedOrder)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(dao.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetAllOrdersOfCourierService indicates an expected call of GetAllOrdersOfCourierService.
-func (mr *MockAllProjectAppMockRecorder) GetAllOrdersOfCourierService(limit, page, idService interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetAllOrdersOfCourierService(ctx, limit, page, idService interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllOrdersOfCourierService", reflect.TypeOf((*MockAllProjectApp)(nil).GetAllOrdersOfCourierService), ctx, limit, page, idService)
+}
+
+// GetAllOrdersOfCourierServiceAfterCursor mocks base method.
+func (m *MockAllProjectApp) GetAllOrdersOfCourierServiceAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.DetailedOrder, dao.CursorPagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllOrdersOfCourierServiceAfterCursor", ctx, limit, after, idService)
+	ret0, _ := ret[0].([]dao.DetailedOrder)
+	ret1, _ := ret[1].(dao.CursorPagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllOrdersOfCourierServiceAfterCursor indicates an expected call of GetAllOrdersOfCourierServiceAfterCursor.
+func (mr *MockAllProjectAppMockRecorder) GetAllOrdersOfCourierServiceAfterCursor(ctx, limit, after, idService interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllOrdersOfCourierService", reflect.TypeOf((*MockAllProjectApp)(nil).GetAllOrdersOfCourierService), limit, page, idService)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllOrdersOfCourierServiceAfterCursor", reflect.TypeOf((*MockAllProjectApp)(nil).GetAllOrdersOfCourierServiceAfterCursor), ctx, limit, after, idService)
 }
 
 // GetCompletedOrdersOfCourierService mocks base method.
-func (m *MockAllProjectApp) GetCompletedOrdersOfCourierService(limit, page, idService int) ([]dao.Order, error) {
+func (m *MockAllProjectApp) GetCompletedOrdersOfCourierService(ctx context.Context, limit, page, idService int) ([]dao.Order, dao.Pagination, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCompletedOrdersOfCourierService", limit, page, idService)
+	ret := m.ctrl.Call(m, "GetCompletedOrdersOfCourierService", ctx, limit, page, idService)
 	ret0, _ := ret[0].([]dao.Order)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(dao.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetCompletedOrdersOfCourierService indicates an expected call of GetCompletedOrdersOfCourierService.
-func (mr *MockAllProjectAppMockRecorder) GetCompletedOrdersOfCourierService(limit, page, idService interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetCompletedOrdersOfCourierService(ctx, limit, page, idService interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedOrdersOfCourierService", reflect.TypeOf((*MockAllProjectApp)(nil).GetCompletedOrdersOfCourierService), limit, page, idService)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedOrdersOfCourierService", reflect.TypeOf((*MockAllProjectApp)(nil).GetCompletedOrdersOfCourierService), ctx, limit, page, idService)
+}
+
+// GetCompletedOrdersOfCourierServiceAfterCursor mocks base method.
+func (m *MockAllProjectApp) GetCompletedOrdersOfCourierServiceAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.Order, dao.CursorPagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompletedOrdersOfCourierServiceAfterCursor", ctx, limit, after, idService)
+	ret0, _ := ret[0].([]dao.Order)
+	ret1, _ := ret[1].(dao.CursorPagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCompletedOrdersOfCourierServiceAfterCursor indicates an expected call of GetCompletedOrdersOfCourierServiceAfterCursor.
+func (mr *MockAllProjectAppMockRecorder) GetCompletedOrdersOfCourierServiceAfterCursor(ctx, limit, after, idService interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedOrdersOfCourierServiceAfterCursor", reflect.TypeOf((*MockAllProjectApp)(nil).GetCompletedOrdersOfCourierServiceAfterCursor), ctx, limit, after, idService)
 }
 
 // GetCompletedOrdersOfCourierServiceByCourierId mocks base method.
-func (m *MockAllProjectApp) GetCompletedOrdersOfCourierServiceByCourierId(limit, page, idService int) ([]dao.Order, error) {
+func (m *MockAllProjectApp) GetCompletedOrdersOfCourierServiceByCourierId(ctx context.Context, limit, page, idService int) ([]dao.Order, dao.Pagination, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCompletedOrdersOfCourierServiceByCourierId", limit, page, idService)
+	ret := m.ctrl.Call(m, "GetCompletedOrdersOfCourierServiceByCourierId", ctx, limit, page, idService)
 	ret0, _ := ret[0].([]dao.Order)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(dao.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetCompletedOrdersOfCourierServiceByCourierId indicates an expected call of GetCompletedOrdersOfCourierServiceByCourierId.
-func (mr *MockAllProjectAppMockRecorder) GetCompletedOrdersOfCourierServiceByCourierId(limit, page, idService interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetCompletedOrdersOfCourierServiceByCourierId(ctx, limit, page, idService interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedOrdersOfCourierServiceByCourierId", reflect.TypeOf((*MockAllProjectApp)(nil).GetCompletedOrdersOfCourierServiceByCourierId), ctx, limit, page, idService)
+}
+
+// GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor mocks base method.
+func (m *MockAllProjectApp) GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.Order, dao.CursorPagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor", ctx, limit, after, idService)
+	ret0, _ := ret[0].([]dao.Order)
+	ret1, _ := ret[1].(dao.CursorPagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor indicates an expected call of GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor.
+func (mr *MockAllProjectAppMockRecorder) GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor(ctx, limit, after, idService interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedOrdersOfCourierServiceByCourierId", reflect.TypeOf((*MockAllProjectApp)(nil).GetCompletedOrdersOfCourierServiceByCourierId), limit, page, idService)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor", reflect.TypeOf((*MockAllProjectApp)(nil).GetCompletedOrdersOfCourierServiceByCourierIdAfterCursor), ctx, limit, after, idService)
 }
 
 // GetCompletedOrdersOfCourierServiceByDate mocks base method.
-func (m *MockAllProjectApp) GetCompletedOrdersOfCourierServiceByDate(limit, page, idService int) ([]dao.Order, error) {
+func (m *MockAllProjectApp) GetCompletedOrdersOfCourierServiceByDate(ctx context.Context, limit, page, idService int) ([]dao.Order, dao.Pagination, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCompletedOrdersOfCourierServiceByDate", limit, page, idService)
+	ret := m.ctrl.Call(m, "GetCompletedOrdersOfCourierServiceByDate", ctx, limit, page, idService)
 	ret0, _ := ret[0].([]dao.Order)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(dao.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetCompletedOrdersOfCourierServiceByDate indicates an expected call of GetCompletedOrdersOfCourierServiceByDate.
-func (mr *MockAllProjectAppMockRecorder) GetCompletedOrdersOfCourierServiceByDate(limit, page, idService interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetCompletedOrdersOfCourierServiceByDate(ctx, limit, page, idService interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedOrdersOfCourierServiceByDate", reflect.TypeOf((*MockAllProjectApp)(nil).GetCompletedOrdersOfCourierServiceByDate), ctx, limit, page, idService)
+}
+
+// GetCompletedOrdersOfCourierServiceByDateAfterCursor mocks base method.
+func (m *MockAllProjectApp) GetCompletedOrdersOfCourierServiceByDateAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.Order, dao.CursorPagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompletedOrdersOfCourierServiceByDateAfterCursor", ctx, limit, after, idService)
+	ret0, _ := ret[0].([]dao.Order)
+	ret1, _ := ret[1].(dao.CursorPagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCompletedOrdersOfCourierServiceByDateAfterCursor indicates an expected call of GetCompletedOrdersOfCourierServiceByDateAfterCursor.
+func (mr *MockAllProjectAppMockRecorder) GetCompletedOrdersOfCourierServiceByDateAfterCursor(ctx, limit, after, idService interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedOrdersOfCourierServiceByDate", reflect.TypeOf((*MockAllProjectApp)(nil).GetCompletedOrdersOfCourierServiceByDate), limit, page, idService)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedOrdersOfCourierServiceByDateAfterCursor", reflect.TypeOf((*MockAllProjectApp)(nil).GetCompletedOrdersOfCourierServiceByDateAfterCursor), ctx, limit, after, idService)
 }
 
 // GetCourier mocks base method.
-func (m *MockAllProjectApp) GetCourier(id int) (dao.Courier, error) {
+func (m *MockAllProjectApp) GetCourier(ctx context.Context, id int) (dao.Courier, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCourier", id)
+	ret := m.ctrl.Call(m, "GetCourier", ctx, id)
 	ret0, _ := ret[0].(dao.Courier)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCourier indicates an expected call of GetCourier.
-func (mr *MockAllProjectAppMockRecorder) GetCourier(id interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetCourier(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourier", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourier), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourier", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourier), ctx, id)
 }
 
-// GetCourierCompletedOrders mocks base method.
-func (m *MockAllProjectApp) GetCourierCompletedOrders(limit, page, idCourier int) ([]dao.DetailedOrder, error) {
+// GetCourierApplications mocks base method.
+func (m *MockAllProjectApp) GetCourierApplications(ctx context.Context, idService int) ([]dao.Courier, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCourierCompletedOrders", limit, page, idCourier)
-	ret0, _ := ret[0].([]dao.DetailedOrder)
+	ret := m.ctrl.Call(m, "GetCourierApplications", ctx, idService)
+	ret0, _ := ret[0].([]dao.Courier)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetCourierApplications indicates an expected call of GetCourierApplications.
+func (mr *MockAllProjectAppMockRecorder) GetCourierApplications(ctx, idService interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourierApplications", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourierApplications), ctx, idService)
+}
+
+// GetCourierCompletedOrders mocks base method.
+func (m *MockAllProjectApp) GetCourierCompletedOrders(ctx context.Context, limit, page, idCourier int) ([]dao.DetailedOrder, dao.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourierCompletedOrders", ctx, limit, page, idCourier)
+	ret0, _ := ret[0].([]dao.DetailedOrder)
+	ret1, _ := ret[1].(dao.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // GetCourierCompletedOrders indicates an expected call of GetCourierCompletedOrders.
-func (mr *MockAllProjectAppMockRecorder) GetCourierCompletedOrders(limit, page, idCourier interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetCourierCompletedOrders(ctx, limit, page, idCourier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourierCompletedOrders", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourierCompletedOrders), ctx, limit, page, idCourier)
+}
+
+// GetCourierCompletedOrdersAfterCursor mocks base method.
+func (m *MockAllProjectApp) GetCourierCompletedOrdersAfterCursor(ctx context.Context, limit int, after string, idCourier int) ([]dao.DetailedOrder, dao.CursorPagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourierCompletedOrdersAfterCursor", ctx, limit, after, idCourier)
+	ret0, _ := ret[0].([]dao.DetailedOrder)
+	ret1, _ := ret[1].(dao.CursorPagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCourierCompletedOrdersAfterCursor indicates an expected call of GetCourierCompletedOrdersAfterCursor.
+func (mr *MockAllProjectAppMockRecorder) GetCourierCompletedOrdersAfterCursor(ctx, limit, after, idCourier interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourierCompletedOrders", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourierCompletedOrders), limit, page, idCourier)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourierCompletedOrdersAfterCursor", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourierCompletedOrdersAfterCursor), ctx, limit, after, idCourier)
 }
 
 // GetCourierCompletedOrdersByMonth mocks base method.
-func (m *MockAllProjectApp) GetCourierCompletedOrdersByMonth(limit, page, idService, Month, Year int) ([]dao.Order, error) {
+func (m *MockAllProjectApp) GetCourierCompletedOrdersByMonth(ctx context.Context, limit, page, idService, Month, Year int) ([]dao.Order, dao.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourierCompletedOrdersByMonth", ctx, limit, page, idService, Month, Year)
+	ret0, _ := ret[0].([]dao.Order)
+	ret1, _ := ret[1].(dao.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCourierCompletedOrdersByMonth indicates an expected call of GetCourierCompletedOrdersByMonth.
+func (mr *MockAllProjectAppMockRecorder) GetCourierCompletedOrdersByMonth(ctx, limit, page, idService, Month, Year interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourierCompletedOrdersByMonth", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourierCompletedOrdersByMonth), ctx, limit, page, idService, Month, Year)
+}
+
+// GetCourierCompletedOrdersByMonthAfterCursor mocks base method.
+func (m *MockAllProjectApp) GetCourierCompletedOrdersByMonthAfterCursor(ctx context.Context, limit int, after string, idCourier, Month, Year int) ([]dao.Order, dao.CursorPagination, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCourierCompletedOrdersByMonth", limit, page, idService, Month, Year)
+	ret := m.ctrl.Call(m, "GetCourierCompletedOrdersByMonthAfterCursor", ctx, limit, after, idCourier, Month, Year)
 	ret0, _ := ret[0].([]dao.Order)
+	ret1, _ := ret[1].(dao.CursorPagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCourierCompletedOrdersByMonthAfterCursor indicates an expected call of GetCourierCompletedOrdersByMonthAfterCursor.
+func (mr *MockAllProjectAppMockRecorder) GetCourierCompletedOrdersByMonthAfterCursor(ctx, limit, after, idCourier, Month, Year interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourierCompletedOrdersByMonthAfterCursor", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourierCompletedOrdersByMonthAfterCursor), ctx, limit, after, idCourier, Month, Year)
+}
+
+// GetCourierDocuments mocks base method.
+func (m *MockAllProjectApp) GetCourierDocuments(ctx context.Context, courierId int) ([]dao.CourierDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourierDocuments", ctx, courierId)
+	ret0, _ := ret[0].([]dao.CourierDocument)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCourierCompletedOrdersByMonth indicates an expected call of GetCourierCompletedOrdersByMonth.
-func (mr *MockAllProjectAppMockRecorder) GetCourierCompletedOrdersByMonth(limit, page, idService, Month, Year interface{}) *gomock.Call {
+// GetCourierDocuments indicates an expected call of GetCourierDocuments.
+func (mr *MockAllProjectAppMockRecorder) GetCourierDocuments(ctx, courierId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourierDocuments", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourierDocuments), ctx, courierId)
+}
+
+// GetCourierEarnings mocks base method.
+func (m *MockAllProjectApp) GetCourierEarnings(ctx context.Context, idCourier, month, year int) (dao.Earnings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourierEarnings", ctx, idCourier, month, year)
+	ret0, _ := ret[0].(dao.Earnings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCourierEarnings indicates an expected call of GetCourierEarnings.
+func (mr *MockAllProjectAppMockRecorder) GetCourierEarnings(ctx, idCourier, month, year interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourierCompletedOrdersByMonth", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourierCompletedOrdersByMonth), limit, page, idService, Month, Year)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourierEarnings", reflect.TypeOf((*MockAllProjectApp)(nil).GetCourierEarnings), ctx, idCourier, month, year)
 }
 
 // GetCouriers mocks base method.
-func (m *MockAllProjectApp) GetCouriers() ([]dao.SmallInfo, error) {
+func (m *MockAllProjectApp) GetCouriers(ctx context.Context, idService int) ([]dao.SmallInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCouriers")
+	ret := m.ctrl.Call(m, "GetCouriers", ctx, idService)
 	ret0, _ := ret[0].([]dao.SmallInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCouriers indicates an expected call of GetCouriers.
-func (mr *MockAllProjectAppMockRecorder) GetCouriers() *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetCouriers(ctx, idService interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouriers", reflect.TypeOf((*MockAllProjectApp)(nil).GetCouriers))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouriers", reflect.TypeOf((*MockAllProjectApp)(nil).GetCouriers), ctx, idService)
 }
 
 // GetCouriersOfCourierService mocks base method.
-func (m *MockAllProjectApp) GetCouriersOfCourierService(limit, page, idService int) ([]dao.Courier, error) {
+func (m *MockAllProjectApp) GetCouriersOfCourierService(ctx context.Context, limit, page, idService int) ([]dao.Courier, dao.Pagination, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCouriersOfCourierService", limit, page, idService)
+	ret := m.ctrl.Call(m, "GetCouriersOfCourierService", ctx, limit, page, idService)
 	ret0, _ := ret[0].([]dao.Courier)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(dao.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetCouriersOfCourierService indicates an expected call of GetCouriersOfCourierService.
-func (mr *MockAllProjectAppMockRecorder) GetCouriersOfCourierService(limit, page, idService interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetCouriersOfCourierService(ctx, limit, page, idService interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouriersOfCourierService", reflect.TypeOf((*MockAllProjectApp)(nil).GetCouriersOfCourierService), limit, page, idService)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouriersOfCourierService", reflect.TypeOf((*MockAllProjectApp)(nil).GetCouriersOfCourierService), ctx, limit, page, idService)
 }
 
 // GetDeliveryServiceById mocks base method.
-func (m *MockAllProjectApp) GetDeliveryServiceById(Id int) (*dao.DeliveryService, error) {
+func (m *MockAllProjectApp) GetDeliveryServiceById(ctx context.Context, Id int) (*dao.DeliveryService, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDeliveryServiceById", Id)
+	ret := m.ctrl.Call(m, "GetDeliveryServiceById", ctx, Id)
 	ret0, _ := ret[0].(*dao.DeliveryService)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetDeliveryServiceById indicates an expected call of GetDeliveryServiceById.
-func (mr *MockAllProjectAppMockRecorder) GetDeliveryServiceById(Id interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetDeliveryServiceById(ctx, Id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryServiceById", reflect.TypeOf((*MockAllProjectApp)(nil).GetDeliveryServiceById), Id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryServiceById", reflect.TypeOf((*MockAllProjectApp)(nil).GetDeliveryServiceById), ctx, Id)
 }
 
 // GetDetailedOrderById mocks base method.
-func (m *MockAllProjectApp) GetDetailedOrderById(Id int) (*dao.AllInfoAboutOrder, error) {
+func (m *MockAllProjectApp) GetDetailedOrderById(ctx context.Context, Id int) (*dao.AllInfoAboutOrder, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDetailedOrderById", Id)
+	ret := m.ctrl.Call(m, "GetDetailedOrderById", ctx, Id)
 	ret0, _ := ret[0].(*dao.AllInfoAboutOrder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetDetailedOrderById indicates an expected call of GetDetailedOrderById.
-func (mr *MockAllProjectAppMockRecorder) GetDetailedOrderById(Id interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetDetailedOrderById(ctx, Id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetailedOrderById", reflect.TypeOf((*MockAllProjectApp)(nil).GetDetailedOrderById), Id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetailedOrderById", reflect.TypeOf((*MockAllProjectApp)(nil).GetDetailedOrderById), ctx, Id)
+}
+
+// GetDocumentFile mocks base method.
+func (m *MockAllProjectApp) GetDocumentFile(ctx context.Context, courierId int, documentType string) ([]byte, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDocumentFile", ctx, courierId, documentType)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDocumentFile indicates an expected call of GetDocumentFile.
+func (mr *MockAllProjectAppMockRecorder) GetDocumentFile(ctx, courierId, documentType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocumentFile", reflect.TypeOf((*MockAllProjectApp)(nil).GetDocumentFile), ctx, courierId, documentType)
+}
+
+// GetDocumentWarnings mocks base method.
+func (m *MockAllProjectApp) GetDocumentWarnings(ctx context.Context, idService int) ([]dao.DocumentWarning, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDocumentWarnings", ctx, idService)
+	ret0, _ := ret[0].([]dao.DocumentWarning)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDocumentWarnings indicates an expected call of GetDocumentWarnings.
+func (mr *MockAllProjectAppMockRecorder) GetDocumentWarnings(ctx, idService interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocumentWarnings", reflect.TypeOf((*MockAllProjectApp)(nil).GetDocumentWarnings), ctx, idService)
+}
+
+// GetExpiringDocuments mocks base method.
+func (m *MockAllProjectApp) GetExpiringDocuments(ctx context.Context, idService, days int) ([]dao.ExpiringDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiringDocuments", ctx, idService, days)
+	ret0, _ := ret[0].([]dao.ExpiringDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiringDocuments indicates an expected call of GetExpiringDocuments.
+func (mr *MockAllProjectAppMockRecorder) GetExpiringDocuments(ctx, idService, days interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiringDocuments", reflect.TypeOf((*MockAllProjectApp)(nil).GetExpiringDocuments), ctx, idService, days)
+}
+
+// GetFile mocks base method.
+func (m *MockAllProjectApp) GetFile(ctx context.Context, key string) ([]byte, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFile", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockAllProjectAppMockRecorder) GetFile(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockAllProjectApp)(nil).GetFile), ctx, key)
 }
 
 // GetOrder mocks base method.
-func (m *MockAllProjectApp) GetOrder(id int) (dao.Order, error) {
+func (m *MockAllProjectApp) GetOrder(ctx context.Context, id int) (dao.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrder", id)
+	ret := m.ctrl.Call(m, "GetOrder", ctx, id)
 	ret0, _ := ret[0].(dao.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrder indicates an expected call of GetOrder.
-func (mr *MockAllProjectAppMockRecorder) GetOrder(id interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetOrder(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockAllProjectApp)(nil).GetOrder), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockAllProjectApp)(nil).GetOrder), ctx, id)
 }
 
 // GetOrderForChange mocks base method.
-func (m *MockAllProjectApp) GetOrderForChange(id int) (dao.Order, error) {
+func (m *MockAllProjectApp) GetOrderForChange(ctx context.Context, id int) (dao.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrderForChange", id)
+	ret := m.ctrl.Call(m, "GetOrderForChange", ctx, id)
 	ret0, _ := ret[0].(dao.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrderForChange indicates an expected call of GetOrderForChange.
-func (mr *MockAllProjectAppMockRecorder) GetOrderForChange(id interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetOrderForChange(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderForChange", reflect.TypeOf((*MockAllProjectApp)(nil).GetOrderForChange), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderForChange", reflect.TypeOf((*MockAllProjectApp)(nil).GetOrderForChange), ctx, id)
 }
 
 // GetOrders mocks base method.
-func (m *MockAllProjectApp) GetOrders(id int) ([]dao.Order, error) {
+func (m *MockAllProjectApp) GetOrders(ctx context.Context, id int) ([]dao.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrders", id)
+	ret := m.ctrl.Call(m, "GetOrders", ctx, id)
 	ret0, _ := ret[0].([]dao.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrders indicates an expected call of GetOrders.
-func (mr *MockAllProjectAppMockRecorder) GetOrders(id interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetOrders(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockAllProjectApp)(nil).GetOrders), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockAllProjectApp)(nil).GetOrders), ctx, id)
 }
 
 // GetOrdersOfCourierServiceForManager mocks base method.
-func (m *MockAllProjectApp) GetOrdersOfCourierServiceForManager(limit, page, idService int) ([]dao.DetailedOrder, error) {
+func (m *MockAllProjectApp) GetOrdersOfCourierServiceForManager(ctx context.Context, limit, page, idService int) ([]dao.DetailedOrder, dao.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrdersOfCourierServiceForManager", ctx, limit, page, idService)
+	ret0, _ := ret[0].([]dao.DetailedOrder)
+	ret1, _ := ret[1].(dao.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOrdersOfCourierServiceForManager indicates an expected call of GetOrdersOfCourierServiceForManager.
+func (mr *MockAllProjectAppMockRecorder) GetOrdersOfCourierServiceForManager(ctx, limit, page, idService interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersOfCourierServiceForManager", reflect.TypeOf((*MockAllProjectApp)(nil).GetOrdersOfCourierServiceForManager), ctx, limit, page, idService)
+}
+
+// GetOrdersOfCourierServiceForManagerAfterCursor mocks base method.
+func (m *MockAllProjectApp) GetOrdersOfCourierServiceForManagerAfterCursor(ctx context.Context, limit int, after string, idService int) ([]dao.DetailedOrder, dao.CursorPagination, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrdersOfCourierServiceForManager", limit, page, idService)
+	ret := m.ctrl.Call(m, "GetOrdersOfCourierServiceForManagerAfterCursor", ctx, limit, after, idService)
 	ret0, _ := ret[0].([]dao.DetailedOrder)
+	ret1, _ := ret[1].(dao.CursorPagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOrdersOfCourierServiceForManagerAfterCursor indicates an expected call of GetOrdersOfCourierServiceForManagerAfterCursor.
+func (mr *MockAllProjectAppMockRecorder) GetOrdersOfCourierServiceForManagerAfterCursor(ctx, limit, after, idService interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersOfCourierServiceForManagerAfterCursor", reflect.TypeOf((*MockAllProjectApp)(nil).GetOrdersOfCourierServiceForManagerAfterCursor), ctx, limit, after, idService)
+}
+
+// GetProofOfDelivery mocks base method.
+func (m *MockAllProjectApp) GetProofOfDelivery(ctx context.Context, orderId int) ([]byte, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProofOfDelivery", ctx, orderId)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetProofOfDelivery indicates an expected call of GetProofOfDelivery.
+func (mr *MockAllProjectAppMockRecorder) GetProofOfDelivery(ctx, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProofOfDelivery", reflect.TypeOf((*MockAllProjectApp)(nil).GetProofOfDelivery), ctx, orderId)
+}
+
+// GetRolePermissions mocks base method.
+func (m *MockAllProjectApp) GetRolePermissions(ctx context.Context) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolePermissions", ctx)
+	ret0, _ := ret[0].(map[string][]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrdersOfCourierServiceForManager indicates an expected call of GetOrdersOfCourierServiceForManager.
-func (mr *MockAllProjectAppMockRecorder) GetOrdersOfCourierServiceForManager(limit, page, idService interface{}) *gomock.Call {
+// GetRolePermissions indicates an expected call of GetRolePermissions.
+func (mr *MockAllProjectAppMockRecorder) GetRolePermissions(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersOfCourierServiceForManager", reflect.TypeOf((*MockAllProjectApp)(nil).GetOrdersOfCourierServiceForManager), limit, page, idService)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolePermissions", reflect.TypeOf((*MockAllProjectApp)(nil).GetRolePermissions), ctx)
 }
 
 // GetServices mocks base method.
-func (m *MockAllProjectApp) GetServices(in *emptypb.Empty) (*courierProto.ServicesResponse, error) {
+func (m *MockAllProjectApp) GetServices(ctx context.Context, in *emptypb.Empty) (*courierProto.ServicesResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServices", in)
+	ret := m.ctrl.Call(m, "GetServices", ctx, in)
 	ret0, _ := ret[0].(*courierProto.ServicesResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServices indicates an expected call of GetServices.
-func (mr *MockAllProjectAppMockRecorder) GetServices(in interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) GetServices(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServices", reflect.TypeOf((*MockAllProjectApp)(nil).GetServices), ctx, in)
+}
+
+// GetVehicleOfCourier mocks base method.
+func (m *MockAllProjectApp) GetVehicleOfCourier(ctx context.Context, courierId int) (*dao.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVehicleOfCourier", ctx, courierId)
+	ret0, _ := ret[0].(*dao.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVehicleOfCourier indicates an expected call of GetVehicleOfCourier.
+func (mr *MockAllProjectAppMockRecorder) GetVehicleOfCourier(ctx, courierId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicleOfCourier", reflect.TypeOf((*MockAllProjectApp)(nil).GetVehicleOfCourier), ctx, courierId)
+}
+
+// InvalidateToken mocks base method.
+func (m *MockAllProjectApp) InvalidateToken(token string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateToken", token)
+}
+
+// InvalidateToken indicates an expected call of InvalidateToken.
+func (mr *MockAllProjectAppMockRecorder) InvalidateToken(token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServices", reflect.TypeOf((*MockAllProjectApp)(nil).GetServices), in)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateToken", reflect.TypeOf((*MockAllProjectApp)(nil).InvalidateToken), token)
+}
+
+// InvalidateUserTokens mocks base method.
+func (m *MockAllProjectApp) InvalidateUserTokens(userId int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateUserTokens", userId)
+}
+
+// InvalidateUserTokens indicates an expected call of InvalidateUserTokens.
+func (mr *MockAllProjectAppMockRecorder) InvalidateUserTokens(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateUserTokens", reflect.TypeOf((*MockAllProjectApp)(nil).InvalidateUserTokens), userId)
 }
 
 // NewUpdateCourier mocks base method.
-func (m *MockAllProjectApp) NewUpdateCourier(courier dao.Courier) error {
+func (m *MockAllProjectApp) NewUpdateCourier(ctx context.Context, courier dao.Courier) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewUpdateCourier", courier)
+	ret := m.ctrl.Call(m, "NewUpdateCourier", ctx, courier)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // NewUpdateCourier indicates an expected call of NewUpdateCourier.
-func (mr *MockAllProjectAppMockRecorder) NewUpdateCourier(courier interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) NewUpdateCourier(ctx, courier interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewUpdateCourier", reflect.TypeOf((*MockAllProjectApp)(nil).NewUpdateCourier), courier)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewUpdateCourier", reflect.TypeOf((*MockAllProjectApp)(nil).NewUpdateCourier), ctx, courier)
 }
 
 // ParseToken mocks base method.
-func (m *MockAllProjectApp) ParseToken(token string) (*authProto.UserRole, error) {
+func (m *MockAllProjectApp) ParseToken(ctx context.Context, token string) (*authProto.UserRole, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ParseToken", token)
+	ret := m.ctrl.Call(m, "ParseToken", ctx, token)
 	ret0, _ := ret[0].(*authProto.UserRole)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ParseToken indicates an expected call of ParseToken.
-func (mr *MockAllProjectAppMockRecorder) ParseToken(token interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) ParseToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAllProjectApp)(nil).ParseToken), ctx, token)
+}
+
+// PatchCourier mocks base method.
+func (m *MockAllProjectApp) PatchCourier(ctx context.Context, id int, patch dao.CourierPatch) (dao.Courier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchCourier", ctx, id, patch)
+	ret0, _ := ret[0].(dao.Courier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchCourier indicates an expected call of PatchCourier.
+func (mr *MockAllProjectAppMockRecorder) PatchCourier(ctx, id, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchCourier", reflect.TypeOf((*MockAllProjectApp)(nil).PatchCourier), ctx, id, patch)
+}
+
+// PatchDeliveryService mocks base method.
+func (m *MockAllProjectApp) PatchDeliveryService(ctx context.Context, id int, patch dao.DeliveryServicePatch) (dao.DeliveryService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchDeliveryService", ctx, id, patch)
+	ret0, _ := ret[0].(dao.DeliveryService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchDeliveryService indicates an expected call of PatchDeliveryService.
+func (mr *MockAllProjectAppMockRecorder) PatchDeliveryService(ctx, id, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchDeliveryService", reflect.TypeOf((*MockAllProjectApp)(nil).PatchDeliveryService), ctx, id, patch)
+}
+
+// ResolveCaller mocks base method.
+func (m *MockAllProjectApp) ResolveCaller(ctx context.Context, userId int, role string) (dao.Caller, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveCaller", ctx, userId, role)
+	ret0, _ := ret[0].(dao.Caller)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveCaller indicates an expected call of ResolveCaller.
+func (mr *MockAllProjectAppMockRecorder) ResolveCaller(ctx, userId, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveCaller", reflect.TypeOf((*MockAllProjectApp)(nil).ResolveCaller), ctx, userId, role)
+}
+
+// ReviewCourierApplication mocks base method.
+func (m *MockAllProjectApp) ReviewCourierApplication(ctx context.Context, courierId int, approve bool, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewCourierApplication", ctx, courierId, approve, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReviewCourierApplication indicates an expected call of ReviewCourierApplication.
+func (mr *MockAllProjectAppMockRecorder) ReviewCourierApplication(ctx, courierId, approve, reason interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAllProjectApp)(nil).ParseToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewCourierApplication", reflect.TypeOf((*MockAllProjectApp)(nil).ReviewCourierApplication), ctx, courierId, approve, reason)
 }
 
 // SaveCourier mocks base method.
-func (m *MockAllProjectApp) SaveCourier(courier *dao.Courier) (*dao.Courier, error) {
+func (m *MockAllProjectApp) SaveCourier(ctx context.Context, courier *dao.Courier) (*dao.Courier, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveCourier", courier)
+	ret := m.ctrl.Call(m, "SaveCourier", ctx, courier)
 	ret0, _ := ret[0].(*dao.Courier)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveCourier indicates an expected call of SaveCourier.
-func (mr *MockAllProjectAppMockRecorder) SaveCourier(courier interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) SaveCourier(ctx, courier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCourier", reflect.TypeOf((*MockAllProjectApp)(nil).SaveCourier), ctx, courier)
+}
+
+// SaveCourierDocument mocks base method.
+func (m *MockAllProjectApp) SaveCourierDocument(ctx context.Context, document dao.CourierDocument) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCourierDocument", ctx, document)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveCourierDocument indicates an expected call of SaveCourierDocument.
+func (mr *MockAllProjectAppMockRecorder) SaveCourierDocument(ctx, document interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCourier", reflect.TypeOf((*MockAllProjectApp)(nil).SaveCourier), courier)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCourierDocument", reflect.TypeOf((*MockAllProjectApp)(nil).SaveCourierDocument), ctx, document)
 }
 
 // SaveCourierPhoto mocks base method.
-func (m *MockAllProjectApp) SaveCourierPhoto(cover []byte, id int) error {
+func (m *MockAllProjectApp) SaveCourierPhoto(ctx context.Context, cover []byte, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveCourierPhoto", cover, id)
+	ret := m.ctrl.Call(m, "SaveCourierPhoto", ctx, cover, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveCourierPhoto indicates an expected call of SaveCourierPhoto.
-func (mr *MockAllProjectAppMockRecorder) SaveCourierPhoto(cover, id interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) SaveCourierPhoto(ctx, cover, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCourierPhoto", reflect.TypeOf((*MockAllProjectApp)(nil).SaveCourierPhoto), cover, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCourierPhoto", reflect.TypeOf((*MockAllProjectApp)(nil).SaveCourierPhoto), ctx, cover, id)
 }
 
 // SaveLogoFile mocks base method.
-func (m *MockAllProjectApp) SaveLogoFile(cover []byte, id int) error {
+func (m *MockAllProjectApp) SaveLogoFile(ctx context.Context, cover []byte, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveLogoFile", cover, id)
+	ret := m.ctrl.Call(m, "SaveLogoFile", ctx, cover, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveLogoFile indicates an expected call of SaveLogoFile.
-func (mr *MockAllProjectAppMockRecorder) SaveLogoFile(cover, id interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) SaveLogoFile(ctx, cover, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLogoFile", reflect.TypeOf((*MockAllProjectApp)(nil).SaveLogoFile), ctx, cover, id)
+}
+
+// SaveVehicle mocks base method.
+func (m *MockAllProjectApp) SaveVehicle(ctx context.Context, vehicle dao.Vehicle) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVehicle", ctx, vehicle)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveVehicle indicates an expected call of SaveVehicle.
+func (mr *MockAllProjectAppMockRecorder) SaveVehicle(ctx, vehicle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVehicle", reflect.TypeOf((*MockAllProjectApp)(nil).SaveVehicle), ctx, vehicle)
+}
+
+// Search mocks base method.
+func (m *MockAllProjectApp) Search(ctx context.Context, idService int, query string, limit int) (*dao.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, idService, query, limit)
+	ret0, _ := ret[0].(*dao.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockAllProjectAppMockRecorder) Search(ctx, idService, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockAllProjectApp)(nil).Search), ctx, idService, query, limit)
+}
+
+// SetCourierReadyToGo mocks base method.
+func (m *MockAllProjectApp) SetCourierReadyToGo(ctx context.Context, courierId int, ready bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCourierReadyToGo", ctx, courierId, ready)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCourierReadyToGo indicates an expected call of SetCourierReadyToGo.
+func (mr *MockAllProjectAppMockRecorder) SetCourierReadyToGo(ctx, courierId, ready interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCourierReadyToGo", reflect.TypeOf((*MockAllProjectApp)(nil).SetCourierReadyToGo), ctx, courierId, ready)
+}
+
+// SubmitCourierApplication mocks base method.
+func (m *MockAllProjectApp) SubmitCourierApplication(ctx context.Context, courierId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitCourierApplication", ctx, courierId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitCourierApplication indicates an expected call of SubmitCourierApplication.
+func (mr *MockAllProjectAppMockRecorder) SubmitCourierApplication(ctx, courierId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitCourierApplication", reflect.TypeOf((*MockAllProjectApp)(nil).SubmitCourierApplication), ctx, courierId)
+}
+
+// TokenCacheStats mocks base method.
+func (m *MockAllProjectApp) TokenCacheStats() tokencache.Stats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TokenCacheStats")
+	ret0, _ := ret[0].(tokencache.Stats)
+	return ret0
+}
+
+// TokenCacheStats indicates an expected call of TokenCacheStats.
+func (mr *MockAllProjectAppMockRecorder) TokenCacheStats() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLogoFile", reflect.TypeOf((*MockAllProjectApp)(nil).SaveLogoFile), cover, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TokenCacheStats", reflect.TypeOf((*MockAllProjectApp)(nil).TokenCacheStats))
 }
 
 // UpdateCourier mocks base method.
-func (m *MockAllProjectApp) UpdateCourier(id uint16, status bool) (uint16, error) {
+func (m *MockAllProjectApp) UpdateCourier(ctx context.Context, id uint16, status bool) (uint16, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateCourier", id, status)
+	ret := m.ctrl.Call(m, "UpdateCourier", ctx, id, status)
 	ret0, _ := ret[0].(uint16)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateCourier indicates an expected call of UpdateCourier.
-func (mr *MockAllProjectAppMockRecorder) UpdateCourier(id, status interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) UpdateCourier(ctx, id, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCourier", reflect.TypeOf((*MockAllProjectApp)(nil).UpdateCourier), id, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCourier", reflect.TypeOf((*MockAllProjectApp)(nil).UpdateCourier), ctx, id, status)
 }
 
 // UpdateDeliveryService mocks base method.
-func (m *MockAllProjectApp) UpdateDeliveryService(service dao.DeliveryService) error {
+func (m *MockAllProjectApp) UpdateDeliveryService(ctx context.Context, service dao.DeliveryService) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateDeliveryService", service)
+	ret := m.ctrl.Call(m, "UpdateDeliveryService", ctx, service)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateDeliveryService indicates an expected call of UpdateDeliveryService.
-func (mr *MockAllProjectAppMockRecorder) UpdateDeliveryService(service interface{}) *gomock.Call {
+func (mr *MockAllProjectAppMockRecorder) UpdateDeliveryService(ctx, service interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeliveryService", reflect.TypeOf((*MockAllProjectApp)(nil).UpdateDeliveryService), service)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeliveryService", reflect.TypeOf((*MockAllProjectApp)(nil).UpdateDeliveryService), ctx, service)
 }
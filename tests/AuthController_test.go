@@ -0,0 +1,32 @@
+package tests
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+)
+
+func TestHandler_Logout(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	get := mock_service.NewMockAllProjectApp(c)
+	get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 9, Role: "Courier"}, nil)
+	get.EXPECT().InvalidateToken("testToken")
+
+	services := &service.Service{AllProjectApp: get}
+	handler := controller.NewHandler(services)
+	r := handler.InitRoutesGin()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer testToken")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 204, w.Code)
+}
@@ -2,12 +2,15 @@ package tests
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"net/http/httptest"
 	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/imaging"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
 	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
 	"testing"
@@ -15,7 +18,6 @@ import (
 )
 
 func TestHandler_GetCouriers(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, courier dao.SmallInfo)
 
@@ -38,7 +40,6 @@ func TestHandler_GetCouriers(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
@@ -55,20 +56,50 @@ func TestHandler_GetCouriers(t *testing.T) {
 			inputRole:  "Courier manager",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier manager",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier manager"}, role).Return(nil)
+			mockBehavior: func(s *mock_service.MockAllProjectApp, courier dao.SmallInfo) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 1, "Courier manager").Return(dao.Caller{UserId: 1, Role: "Courier manager", DeliveryServiceId: 2}, nil)
+				s.EXPECT().GetCouriers(gomock.Any(), 2).Return(couriers, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id_courier":1,"courier_name":"test","phone_number":"1038812","photo":"my fav photo","photo_thumbnail":"","surname":"Shorokhov","deleted":true}`,
+		},
+		{
+			name:       "Manager without delivery service",
+			inputRole:  "Courier manager",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
+					UserId: 1,
+					Role:   "Courier manager",
+				}, nil)
+			},
+			mockBehavior: func(s *mock_service.MockAllProjectApp, courier dao.SmallInfo) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 1, "Courier manager").Return(dao.Caller{UserId: 1, Role: "Courier manager"}, nil)
+			},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"user has no delivery service"}`,
+		},
+		{
+			name:       "Superadmin sees all couriers",
+			inputRole:  "Superadmin",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
+					UserId: 1,
+					Role:   "Superadmin",
+				}, nil)
 			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, courier dao.SmallInfo) {
-				s.EXPECT().GetCouriers().Return(couriers, nil)
+				s.EXPECT().GetCouriers(gomock.Any(), 0).Return(couriers, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"id_courier":1,"courier_name":"test","phone_number":"1038812","photo":"my fav photo","surname":"Shorokhov","deleted":true}`,
+			expectedRequestBody: `"id_courier":1`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -79,7 +110,6 @@ func TestHandler_GetCouriers(t *testing.T) {
 			get := mock_service.NewMockAllProjectApp(c)
 			testCase.mockBehavior(get, testCase.inputCourier)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -100,17 +130,18 @@ func TestHandler_GetCouriers(t *testing.T) {
 }
 
 func TestHandler_GetOneCourier(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, courier dao.Courier)
 	cour := dao.Courier{
-		Id:          1,
-		UserId:      1,
-		CourierName: "test",
-		PhoneNumber: "1038812",
-		Photo:       "my fav photo",
-		Surname:     "Shorokhov",
-		Deleted:     true,
+		Id:                1,
+		UserId:            1,
+		CourierName:       "test",
+		PhoneNumber:       "1038812",
+		Photo:             "my fav photo",
+		Surname:           "Shorokhov",
+		Deleted:           true,
+		Status:            "approved",
+		DeliveryServiceId: 1,
 	}
 
 	testTable := []struct {
@@ -121,7 +152,6 @@ func TestHandler_GetOneCourier(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
@@ -140,20 +170,17 @@ func TestHandler_GetOneCourier(t *testing.T) {
 			inputRole:  "Courier manager",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier manager",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier", "Courier manager"}, role).Return(nil)
-			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, courier dao.Courier) {
-				s.EXPECT().GetCourier(1).Return(cour, nil)
+				s.EXPECT().GetCourier(gomock.Any(), 1).Return(cour, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"id_courier":1,"user_id":1,"courier_name":"test","ready_to_go":false,"phone_number":"1038812","email":"","rating":0,"photo":"my fav photo","surname":"Shorokhov","number_of_failures":0,"deleted":true,"delivery_service_id":0}`,
+			expectedRequestBody: `{"id_courier":1,"user_id":1,"courier_name":"test","ready_to_go":false,"phone_number":"1038812","email":"","rating":0,"photo":"my fav photo","photo_medium":"","photo_thumbnail":"","surname":"Shorokhov","number_of_failures":0,"deleted":true,"delivery_service_id":1,"status":"approved"}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -162,9 +189,9 @@ func TestHandler_GetOneCourier(t *testing.T) {
 			defer c.Finish()
 
 			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
 			testCase.mockBehavior(get, testCase.inputCourier)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -184,7 +211,6 @@ func TestHandler_GetOneCourier(t *testing.T) {
 }
 
 func TestHandler_GetCourierCompletedOrders(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, order []dao.DetailedOrder)
 
@@ -213,7 +239,6 @@ func TestHandler_GetCourierCompletedOrders(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
@@ -229,20 +254,17 @@ func TestHandler_GetCourierCompletedOrders(t *testing.T) {
 			inputRole:  "Courier",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier", "Courier manager"}, role).Return(nil)
-			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, order []dao.DetailedOrder) {
-				s.EXPECT().GetCourierCompletedOrders(1, 1, 1).Return(orders, nil)
+				s.EXPECT().GetCourierCompletedOrders(gomock.Any(), 1, 1, 1).Return(orders, dao.NewPagination(1, 1, 1), nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2020-05-02T02:02:02.000000002Z","customer_address":"Some address","status":"ready to delivery","order_date":"11.11.2022","picked":false,"name":"","surname":"","phone_number":"","id_from_restaurant":0}]}`,
+			expectedRequestBody: `{"data":[{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2020-05-02T02:02:02.000000002Z","customer_address":"Some address","status":"ready to delivery","order_date":"11.11.2022","picked":false,"name":"","surname":"","phone_number":"","id_from_restaurant":0}],"page":1,"limit":1,"total":1,"total_pages":1}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -251,9 +273,9 @@ func TestHandler_GetCourierCompletedOrders(t *testing.T) {
 			defer c.Finish()
 
 			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
 			testCase.mockBehavior(get, testCase.inputOrder)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -274,7 +296,6 @@ func TestHandler_GetCourierCompletedOrders(t *testing.T) {
 }
 
 func TestHandler_GetAllOrdersOfCourierService(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, order []dao.DetailedOrder)
 
@@ -300,7 +321,6 @@ func TestHandler_GetAllOrdersOfCourierService(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
@@ -324,20 +344,17 @@ func TestHandler_GetAllOrdersOfCourierService(t *testing.T) {
 			inputRole:  "Courier",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier", "Courier manager"}, role).Return(nil)
-			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, order []dao.DetailedOrder) {
-				s.EXPECT().GetAllOrdersOfCourierService(1, 1, 1).Return(orders, nil)
+				s.EXPECT().GetAllOrdersOfCourierService(gomock.Any(), 1, 1, 1).Return(orders, dao.NewPagination(1, 1, 1), nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2020-05-02T02:02:02.000000002Z","customer_address":"Some address","status":"ready to delivery","order_date":"11.11.2022","picked":false,"name":"","surname":"","phone_number":"","id_from_restaurant":0}]}`,
+			expectedRequestBody: `{"data":[{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2020-05-02T02:02:02.000000002Z","customer_address":"Some address","status":"ready to delivery","order_date":"11.11.2022","picked":false,"name":"","surname":"","phone_number":"","id_from_restaurant":0}],"page":1,"limit":1,"total":1,"total_pages":1}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -346,9 +363,9 @@ func TestHandler_GetAllOrdersOfCourierService(t *testing.T) {
 			defer c.Finish()
 
 			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
 			testCase.mockBehavior(get, testCase.inputOrder)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -369,7 +386,6 @@ func TestHandler_GetAllOrdersOfCourierService(t *testing.T) {
 }
 
 func TestHandler_GetCourierCompletedOrdersByMonth(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, order []dao.Order)
 
@@ -395,7 +411,6 @@ func TestHandler_GetCourierCompletedOrdersByMonth(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
@@ -418,20 +433,17 @@ func TestHandler_GetCourierCompletedOrdersByMonth(t *testing.T) {
 			inputRole:  "Courier",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier", "Courier manager"}, role).Return(nil)
-			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, order []dao.Order) {
-				s.EXPECT().GetCourierCompletedOrdersByMonth(1, 1, 1, 11, 2022).Return(orders, nil)
+				s.EXPECT().GetCourierCompletedOrdersByMonth(gomock.Any(), 1, 1, 1, 11, 2022).Return(orders, dao.NewPagination(1, 1, 1), nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2020-05-02T02:02:02.000000002Z","customer_address":"Some address","status":"ready to delivery","order_date":"11.11.2022","restaurant_address":"","picked":false}]}`,
+			expectedRequestBody: `{"data":[{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2020-05-02T02:02:02.000000002Z","customer_address":"Some address","status":"ready to delivery","order_date":"11.11.2022","restaurant_address":"","picked":false}],"page":1,"limit":1,"total":1,"total_pages":1}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -440,9 +452,9 @@ func TestHandler_GetCourierCompletedOrdersByMonth(t *testing.T) {
 			defer c.Finish()
 
 			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
 			testCase.mockBehavior(get, testCase.inputOrder)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -460,3 +472,371 @@ func TestHandler_GetCourierCompletedOrdersByMonth(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_SaveCourierPhoto(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp, cover []byte)
+
+	testTable := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name:      "OK",
+			inputBody: "\xff\xd8\xff\xe0 jpeg",
+			mockBehavior: func(s *mock_service.MockAllProjectApp, cover []byte) {
+				s.EXPECT().SaveCourierPhoto(gomock.Any(), cover, 1).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:      "Not an image",
+			inputBody: "<html></html>",
+			mockBehavior: func(s *mock_service.MockAllProjectApp, cover []byte) {
+				s.EXPECT().SaveCourierPhoto(gomock.Any(), cover, 1).Return(fmt.Errorf("Error in CourierService: %w", imaging.ErrUnsupportedType))
+			},
+			expectedStatusCode: 415,
+		},
+		{
+			name:      "Too many pixels",
+			inputBody: "\xff\xd8\xff\xe0 jpeg",
+			mockBehavior: func(s *mock_service.MockAllProjectApp, cover []byte) {
+				s.EXPECT().SaveCourierPhoto(gomock.Any(), cover, 1).Return(fmt.Errorf("Error in CourierService: %w", imaging.ErrTooManyPixels))
+			},
+			expectedStatusCode: 413,
+		},
+		{
+			name:               "Empty body",
+			inputBody:          "",
+			mockBehavior:       func(s *mock_service.MockAllProjectApp, cover []byte) {},
+			expectedStatusCode: 400,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: "Courier"}, nil)
+			testCase.mockBehavior(get, []byte(testCase.inputBody))
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/couriers/photo?id=1", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_SaveCourier(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		inputRole           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Applicant registers themselves",
+			inputBody: `{"user_id":3,"courier_name":"Ivan","surname":"Petrov","phone_number":"+375291234567","delivery_service_id":2}`,
+			inputRole: "Courier",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				courier := &dao.Courier{UserId: 9, CourierName: "Ivan", Surname: "Petrov", PhoneNumber: "+375291234567"}
+				s.EXPECT().SaveCourier(gomock.Any(), courier).Return(courier, nil)
+			},
+			expectedStatusCode: 201,
+		},
+		{
+			name:      "Already a courier",
+			inputBody: `{"courier_name":"Ivan"}`,
+			inputRole: "Courier",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SaveCourier(gomock.Any(), &dao.Courier{UserId: 9, CourierName: "Ivan"}).
+					Return(nil, fmt.Errorf("Error in CourierService: %w", service.ErrCourierExists))
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name:      "Manager re-roles a user",
+			inputBody: `{"user_id":3,"courier_name":"Ivan"}`,
+			inputRole: "Courier manager",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SaveCourier(gomock.Any(), &dao.Courier{UserId: 3, CourierName: "Ivan", DeliveryServiceId: 1}).
+					Return(nil, fmt.Errorf("Error in CourierService: user 3 manages a delivery service: %w", service.ErrUserHasRole))
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name:      "Role not granted",
+			inputBody: `{"user_id":3,"courier_name":"Ivan"}`,
+			inputRole: "Courier manager",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SaveCourier(gomock.Any(), &dao.Courier{UserId: 3, CourierName: "Ivan", DeliveryServiceId: 1}).
+					Return(nil, fmt.Errorf("Error in CourierService: %w: unavailable", service.ErrRoleNotGranted))
+			},
+			expectedStatusCode: 502,
+		},
+		{
+			name:      "No user",
+			inputBody: `{"courier_name":"Ivan"}`,
+			inputRole: "Courier manager",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SaveCourier(gomock.Any(), &dao.Courier{CourierName: "Ivan", DeliveryServiceId: 1}).
+					Return(nil, fmt.Errorf("Error in CourierService: %w", service.ErrUserRequired))
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name:      "Fields set by the service are ignored",
+			inputBody: `{"user_id":3,"courier_name":"Ivan","id_courier":40,"rating":5,"deleted":true,"number_of_failures":0,"status":"approved"}`,
+			inputRole: "Courier manager",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				courier := &dao.Courier{UserId: 3, CourierName: "Ivan", DeliveryServiceId: 1}
+				s.EXPECT().SaveCourier(gomock.Any(), courier).Return(courier, nil)
+			},
+			expectedStatusCode: 201,
+		},
+		{
+			name:               "Invalid phone and email",
+			inputBody:          `{"user_id":3,"courier_name":"Ivan","phone_number":"call me","email":"ivan"}`,
+			inputRole:          "Courier manager",
+			mockBehavior:       func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode: 400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"invalid request","fields":[` +
+				`{"field":"phone_number","message":"must be a phone number, like +375291234567"},{"field":"email","message":"must be an email address"}]}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 9, Role: testCase.inputRole}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/courier/", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), testCase.expectedRequestBody)
+		})
+	}
+}
+
+func TestHandler_NewUpdateCourier(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		inputIfMatch        string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:         "OK",
+			inputBody:    `{"surname":"Petrov"}`,
+			inputIfMatch: `"3"`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().NewUpdateCourier(gomock.Any(), dao.Courier{Id: 5, Surname: "Petrov", Version: 3}).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:         "Not found",
+			inputBody:    `{"surname":"Petrov"}`,
+			inputIfMatch: `"3"`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().NewUpdateCourier(gomock.Any(), dao.Courier{Id: 5, Surname: "Petrov", Version: 3}).
+					Return(fmt.Errorf("Error in CourierService: %w", service.ErrCourierNotFound))
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"status":404,"error":"not_found","message":"courier not found"}`,
+		},
+		{
+			name:         "Any version of a missing courier",
+			inputBody:    `{"surname":"Petrov"}`,
+			inputIfMatch: `*`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().NewUpdateCourier(gomock.Any(), dao.Courier{Id: 5, Surname: "Petrov"}).
+					Return(fmt.Errorf("Error in CourierService: %w", service.ErrCourierNotFound))
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"status":404,"error":"not_found","message":"courier not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 9, Role: "Courier manager"}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/courier/5", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+			req.Header.Set("If-Match", testCase.inputIfMatch)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_PatchCourier(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		inputRole           string
+		inputIfMatch        string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+		expectedETag        string
+	}{
+		{
+			name:         "Clear email and restore",
+			inputBody:    `{"email":null,"deleted":false}`,
+			inputRole:    "Courier manager",
+			inputIfMatch: `"3"`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().PatchCourier(gomock.Any(), 5, dao.CourierPatch{
+					Email:   dao.PatchString{Set: true, Null: true},
+					Deleted: dao.PatchBool{Set: true},
+					Version: 3,
+				}).Return(dao.Courier{Id: 5, CourierName: "Ivan", DeliveryServiceId: 1, Version: 4}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id_courier":5,"user_id":0,"courier_name":"Ivan"`,
+			expectedETag:        `"4"`,
+		},
+		{
+			name:                "No If-Match",
+			inputBody:           `{"surname":"Petrov"}`,
+			inputRole:           "Courier manager",
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  428,
+			expectedRequestBody: `{"status":428,"error":"precondition_required","message":"If-Match with the ETag of the courier is required"}`,
+		},
+		{
+			name:         "Changed since it was read",
+			inputBody:    `{"surname":"Petrov"}`,
+			inputRole:    "Courier manager",
+			inputIfMatch: `"3"`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().PatchCourier(gomock.Any(), 5, gomock.Any()).Return(dao.Courier{}, fmt.Errorf("Error in CourierService: %w", service.ErrCourierChanged))
+			},
+			expectedStatusCode:  412,
+			expectedRequestBody: `{"status":412,"error":"precondition_failed","message":"courier was changed since it was read"}`,
+		},
+		{
+			name:                "Weak ETag",
+			inputBody:           `{"surname":"Petrov"}`,
+			inputRole:           "Courier manager",
+			inputIfMatch:        `W/"3"`,
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  412,
+			expectedRequestBody: `"error":"precondition_failed"`,
+		},
+		{
+			name:               "Courier restores themselves",
+			inputBody:          `{"deleted":false}`,
+			inputRole:          "Courier",
+			inputIfMatch:       `"3"`,
+			mockBehavior:       func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode: 403,
+		},
+		{
+			name:                "Manager moves the courier",
+			inputBody:           `{"delivery_service_id":3}`,
+			inputRole:           "Courier manager",
+			inputIfMatch:        `"3"`,
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"only Superadmin moves couriers between delivery services"}`,
+		},
+		{
+			name:                "Invalid phone",
+			inputBody:           `{"phone_number":"call me"}`,
+			inputRole:           "Courier manager",
+			inputIfMatch:        `"3"`,
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `"fields":[{"field":"phone_number","message":"must be a phone number, like +375291234567"}]`,
+		},
+		{
+			name:         "Name cleared",
+			inputBody:    `{"courier_name":null}`,
+			inputRole:    "Courier manager",
+			inputIfMatch: `"3"`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().PatchCourier(gomock.Any(), 5, gomock.Any()).Return(dao.Courier{}, fmt.Errorf("Error in CourierService: %w",
+					apperr.Invalid(apperr.FieldError{Field: "courier_name", Message: "can't be empty"})))
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `"fields":[{"field":"courier_name","message":"can't be empty"}]`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 9, Role: testCase.inputRole}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PATCH", "/courier/5", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+			if testCase.inputIfMatch != "" {
+				req.Header.Set("If-Match", testCase.inputIfMatch)
+			}
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), testCase.expectedRequestBody)
+			assert.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"net/http/httptest"
@@ -14,7 +15,6 @@ import (
 )
 
 func TestHandler_CreateDeliveryService(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, service dao.DeliveryService)
 
@@ -26,35 +26,31 @@ func TestHandler_CreateDeliveryService(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
 		{
 			name:      "OK",
-			inputBody: `{"name":"test", "email":"test", "photo":"test", "description": "test","phone_number":"1234567", "status": "active"}`,
+			inputBody: `{"name":"test", "email":"test@mail.com", "photo":"test", "description": "test","phone_number":"1234567", "status": "active"}`,
 			inputService: dao.DeliveryService{
 				Name:        "test",
-				Email:       "test",
-				Photo:       "test",
+				Email:       "test@mail.com",
 				Description: "test",
 				Status:      "active",
 				PhoneNumber: "1234567",
+				ManagerId:   1,
 			},
 			inputRole:  "Courier manager",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier manager",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier manager"}, role).Return(nil)
-			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, service dao.DeliveryService) {
-				s.EXPECT().CreateDeliveryService(service).Return(1, nil)
+				s.EXPECT().CreateDeliveryService(gomock.Any(), service).Return(1, nil)
 			},
 			expectedStatusCode:  200,
 			expectedRequestBody: `{"id":1}`,
@@ -66,18 +62,15 @@ func TestHandler_CreateDeliveryService(t *testing.T) {
 			inputRole:    "Courier manager",
 			inputToken:   "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier manager",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier manager"}, role).Return(nil)
-			},
 			mockBehavior:        func(r *mock_service.MockAllProjectApp, service dao.DeliveryService) {},
 			expectedStatusCode:  400,
-			expectedRequestBody: `{"message":"Invalid request"}`,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"Invalid request"}`,
 		},
 		{
 			name:         "empty fields",
@@ -86,18 +79,15 @@ func TestHandler_CreateDeliveryService(t *testing.T) {
 			inputRole:    "Courier manager",
 			inputToken:   "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier manager",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier manager"}, role).Return(nil)
-			},
 			mockBehavior:        func(r *mock_service.MockAllProjectApp, service dao.DeliveryService) {},
 			expectedStatusCode:  400,
-			expectedRequestBody: `{"message":"empty fields"}`,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"invalid request","fields":[{"field":"name","message":"is required"},{"field":"email","message":"is required"}]}`,
 		},
 	}
 
@@ -108,7 +98,6 @@ func TestHandler_CreateDeliveryService(t *testing.T) {
 			newMock := mock_service.NewMockAllProjectApp(c)
 			testCase.mockBehavior(newMock, testCase.inputService)
 			testCase.mockBehaviorParseToken(newMock, testCase.inputToken)
-			testCase.mockBehaviorCheck(newMock, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: newMock}
 			handler := controller.NewHandler(services)
@@ -126,7 +115,6 @@ func TestHandler_CreateDeliveryService(t *testing.T) {
 }
 
 func TestHandler_GetAllDeliveryServices(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, service dao.DeliveryService)
 
@@ -152,29 +140,40 @@ func TestHandler_GetAllDeliveryServices(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
 		{
 			name:       "OK",
-			inputRole:  "Courier manager",
+			inputRole:  "Superadmin",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
-					Role:        "Courier manager",
+					Role:        "Superadmin",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin"}, role).Return(nil)
-			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, service dao.DeliveryService) {
-				s.EXPECT().GetAllDeliveryServices().Return(servicess, nil)
+				s.EXPECT().GetAllDeliveryServices(gomock.Any()).Return(servicess, nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"id":1,"name":"name","email":"email","photo":"photo","description":"description","phone_number":"123","manager_id":1,"status":"active","NumOfCouriers":5}]}`,
+			expectedRequestBody: `{"data":[{"id":1,"name":"name","email":"email","photo":"photo","photo_medium":"","photo_thumbnail":"","description":"description","phone_number":"123","manager_id":1,"status":"active","courier_rate":0,"NumOfCouriers":5}]}`,
+		},
+		{
+			name:       "Manager can't list all services",
+			inputRole:  "Courier manager",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
+					UserId:      1,
+					Role:        "Courier manager",
+					Permissions: "",
+				}, nil)
+			},
+			mockBehavior:        func(s *mock_service.MockAllProjectApp, service dao.DeliveryService) {},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"not enough rights"}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -184,7 +183,6 @@ func TestHandler_GetAllDeliveryServices(t *testing.T) {
 			newMock := mock_service.NewMockAllProjectApp(c)
 			testCase.mockBehavior(newMock, testCase.inputService)
 			testCase.mockBehaviorParseToken(newMock, testCase.inputToken)
-			testCase.mockBehaviorCheck(newMock, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: newMock}
 			handler := controller.NewHandler(services)
@@ -203,7 +201,6 @@ func TestHandler_GetAllDeliveryServices(t *testing.T) {
 }
 
 func TestHandler_GetDeliveryServiceById(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, service *dao.DeliveryService)
 
@@ -227,7 +224,6 @@ func TestHandler_GetDeliveryServiceById(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
@@ -238,22 +234,19 @@ func TestHandler_GetDeliveryServiceById(t *testing.T) {
 				Id: 1,
 			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, service *dao.DeliveryService) {
-				s.EXPECT().GetDeliveryServiceById(1).Return(serv, nil)
+				s.EXPECT().GetDeliveryServiceById(gomock.Any(), 1).Return(serv, nil)
 			},
 			inputRole:  "Courier manager",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier manager",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier", "Courier manager"}, role).Return(nil)
-			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"id":1,"name":"name","email":"email","photo":"photo","description":"description","phone_number":"123","manager_id":1,"status":"active","NumOfCouriers":3}`,
+			expectedRequestBody: `{"id":1,"name":"name","email":"email","photo":"photo","photo_medium":"","photo_thumbnail":"","description":"description","phone_number":"123","manager_id":1,"status":"active","courier_rate":0,"NumOfCouriers":3}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -261,9 +254,9 @@ func TestHandler_GetDeliveryServiceById(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 			newMock := mock_service.NewMockAllProjectApp(c)
+			sameTenant(newMock)
 			testCase.mockBehavior(newMock, &testCase.inputService)
 			testCase.mockBehaviorParseToken(newMock, testCase.inputToken)
-			testCase.mockBehaviorCheck(newMock, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: newMock}
 			handler := controller.NewHandler(services)
@@ -282,7 +275,6 @@ func TestHandler_GetDeliveryServiceById(t *testing.T) {
 }
 
 func TestHandler_UpdateDeliveryService(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, serv dao.DeliveryService)
 
@@ -293,37 +285,64 @@ func TestHandler_UpdateDeliveryService(t *testing.T) {
 		id                     int
 		inputRole              string
 		inputToken             string
+		inputIfMatch           string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 	}{
 		{
 			name:      "OK",
-			inputBody: `{"name":"name","email":"email"}`,
+			inputBody: `{"name":"name","email":"name@mail.com","manager_id":5}`,
 			inputService: dao.DeliveryService{
-				Id:    1,
-				Name:  "name",
-				Email: "email",
+				Id:      1,
+				Name:    "name",
+				Email:   "name@mail.com",
+				Version: 2,
 			},
-			id: 1,
+			id:           1,
+			inputIfMatch: `"2"`,
 			mockBehavior: func(s *mock_service.MockAllProjectApp, serv dao.DeliveryService) {
-				s.EXPECT().UpdateDeliveryService(serv).Return(nil)
+				s.EXPECT().UpdateDeliveryService(gomock.Any(), serv).Return(nil)
 			},
 			inputRole:  "Courier manager",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier manager",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier manager"}, role).Return(nil)
-			},
 			expectedStatusCode: 204,
 		},
+		{
+			name:         "No If-Match",
+			inputBody:    `{"name":"name"}`,
+			id:           1,
+			mockBehavior: func(s *mock_service.MockAllProjectApp, serv dao.DeliveryService) {},
+			inputRole:    "Courier manager",
+			inputToken:   "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: "Courier manager"}, nil)
+			},
+			expectedStatusCode: 428,
+		},
+		{
+			name:         "Not found",
+			inputBody:    `{"name":"name"}`,
+			inputService: dao.DeliveryService{Id: 1, Name: "name", Version: 2},
+			id:           1,
+			inputIfMatch: `"2"`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp, serv dao.DeliveryService) {
+				s.EXPECT().UpdateDeliveryService(gomock.Any(), serv).Return(fmt.Errorf("Error in DeliveryService: %w", service.ErrDeliveryServiceNotFound))
+			},
+			inputRole:  "Superadmin",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: "Superadmin"}, nil)
+			},
+			expectedStatusCode: 404,
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -331,9 +350,9 @@ func TestHandler_UpdateDeliveryService(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
 			testCase.mockBehavior(get, testCase.inputService)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -343,9 +362,88 @@ func TestHandler_UpdateDeliveryService(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("PUT", "/deliveryservice/1", bytes.NewBufferString(testCase.inputBody))
 			req.Header.Set("Authorization", "Bearer testToken")
+			if testCase.inputIfMatch != "" {
+				req.Header.Set("If-Match", testCase.inputIfMatch)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_PatchDeliveryService(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Clear description and rate",
+			inputBody: `{"description":null,"courier_rate":null}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().PatchDeliveryService(gomock.Any(), 1, dao.DeliveryServicePatch{
+					Description: dao.PatchString{Set: true, Null: true},
+					CourierRate: dao.PatchFloat{Set: true, Null: true},
+					Version:     7,
+				}).Return(dao.DeliveryService{Id: 1, Name: "Fast", Email: "fast@mail.com", Status: "active", Version: 8}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":1,"name":"Fast","email":"fast@mail.com"`,
+		},
+		{
+			name:                "Unknown status",
+			inputBody:           `{"status":"closed"}`,
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `"fields":[{"field":"status","message":"must be one of active, inactive"}]`,
+		},
+		{
+			name:      "Changed since it was read",
+			inputBody: `{"courier_rate":2.5}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().PatchDeliveryService(gomock.Any(), 1, gomock.Any()).
+					Return(dao.DeliveryService{}, fmt.Errorf("Error in DeliveryService: %w", service.ErrDeliveryServiceChanged))
+			},
+			expectedStatusCode:  412,
+			expectedRequestBody: `"message":"delivery service was changed since it was read"`,
+		},
+		{
+			name:      "Unknown service",
+			inputBody: `{"name":"Fast"}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().PatchDeliveryService(gomock.Any(), 1, gomock.Any()).
+					Return(dao.DeliveryService{}, fmt.Errorf("Error in DeliveryService: %w", service.ErrDeliveryServiceNotFound))
+			},
+			expectedStatusCode: 404,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: "Courier manager"}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PATCH", "/deliveryservice/1", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+			req.Header.Set("If-Match", `"7"`)
 			r.ServeHTTP(w, req)
 
 			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), testCase.expectedRequestBody)
 		})
 	}
 }
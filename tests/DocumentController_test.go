@@ -0,0 +1,239 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+	"time"
+)
+
+func TestHandler_SaveCourierDocument(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"number":"AB123","issue_date":"2020-03-01","expiry_date":"2030-02-28"}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SaveCourierDocument(gomock.Any(), dao.CourierDocument{CourierId: 5, Type: "driving_licence", Number: "AB123",
+					IssueDate: dao.NewDate(2020, 3, 1), ExpiryDate: dao.NewDate(2030, 2, 28)}).Return(2, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":2}`,
+		},
+		{
+			name:                "Wrong date",
+			inputBody:           `{"number":"AB123","issue_date":"2020-03-01","expiry_date":"28.02.2030"}`,
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"invalid request","fields":[{"field":"expiry_date","message":"must be a date like 2006-01-02"}]}`,
+		},
+		{
+			name:      "Expiry before issue",
+			inputBody: `{"number":"AB123","issue_date":"2020-03-01","expiry_date":"2019-02-28"}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SaveCourierDocument(gomock.Any(), gomock.Any()).
+					Return(0, fmt.Errorf("Error in DocumentService: %w", apperr.Errorf("%w: expiry_date must be after issue_date", service.ErrInvalidDocument)))
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"invalid document: expiry_date must be after issue_date"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: "Courier manager"}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/courier/5/documents/driving_licence", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_SetCourierReadyToGo(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"ready_to_go":false}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SetCourierReadyToGo(gomock.Any(), 5, false).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:      "Expired documents",
+			inputBody: `{"ready_to_go":true}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SetCourierReadyToGo(gomock.Any(), 5, true).Return(fmt.Errorf("Error in DocumentService: %w", service.ErrCourierDocumentsExpired))
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name:               "No value",
+			inputBody:          `{}`,
+			mockBehavior:       func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode: 400,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: "Courier"}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/courier/5/ready", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetDocumentWarnings(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	get := mock_service.NewMockAllProjectApp(c)
+	sameTenant(get)
+	get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: "Courier manager"}, nil)
+	get.EXPECT().GetDocumentWarnings(gomock.Any(), 1).Return([]dao.DocumentWarning{{
+		Id:        4,
+		CreatedAt: time.Date(2022, 3, 1, 6, 0, 0, 0, time.UTC),
+		Document: dao.ExpiringDocument{
+			CourierDocument: dao.CourierDocument{Id: 2, CourierId: 5, Type: "driving_licence", Number: "AB123",
+				IssueDate: dao.NewDate(2012, 3, 1), ExpiryDate: dao.NewDate(2022, 3, 20)},
+			CourierName: "Ivan", CourierSurname: "Petrov", DeliveryServiceId: 1,
+		},
+	}}, nil)
+
+	services := &service.Service{AllProjectApp: get}
+	handler := controller.NewHandler(services)
+	r := handler.InitRoutesGin()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/documents/warnings", nil)
+	req.Header.Set("Authorization", "Bearer testToken")
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `[{"id":4,"created_at":"2022-03-01T06:00:00Z","document":{"id":2,"courier_id":5,"type":"driving_licence","number":"AB123",`+
+		`"file":"","issue_date":"2012-03-01","expiry_date":"2022-03-20","courier_name":"Ivan","surname":"Petrov","delivery_service_id":1}}]`, w.Body.String())
+}
+
+func TestHandler_AcknowledgeDocumentWarning(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name               string
+		role               string
+		url                string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name: "OK",
+			role: "Courier manager",
+			url:  "/documents/warnings/4/ack",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().AcknowledgeDocumentWarning(gomock.Any(), 1, 4).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name: "Not found",
+			role: "Courier manager",
+			url:  "/documents/warnings/4/ack",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().AcknowledgeDocumentWarning(gomock.Any(), 1, 4).
+					Return(fmt.Errorf("Error in DocumentService: %w", service.ErrDocumentWarningNotFound))
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "Wrong id",
+			role:               "Courier manager",
+			url:                "/documents/warnings/x/ack",
+			mockBehavior:       func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "Courier",
+			role:               "Courier",
+			url:                "/documents/warnings/4/ack",
+			mockBehavior:       func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode: 403,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: testCase.role}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", testCase.url, nil)
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/storage"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+)
+
+func TestHandler_GetFile(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	store := storage.NewMemoryStore("/files")
+	jpeg := []byte("\xff\xd8\xff\xe0 jpeg")
+	_ = store.Put(context.Background(), "courier_photo/1", jpeg, "image/jpeg")
+
+	testTable := []struct {
+		name                string
+		inputKey            string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedContentType string
+		expectedRequestBody string
+	}{
+		{
+			name:     "OK",
+			inputKey: "courier_photo/1",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().GetFile(gomock.Any(), "/courier_photo/1").DoAndReturn(func(_ context.Context, key string) ([]byte, string, error) {
+					return store.Get(context.Background(), key)
+				})
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "image/jpeg",
+			expectedRequestBody: string(jpeg),
+		},
+		{
+			name:     "Not found",
+			inputKey: "courier_photo/2",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().GetFile(gomock.Any(), "/courier_photo/2").DoAndReturn(func(_ context.Context, key string) ([]byte, string, error) {
+					return store.Get(context.Background(), key)
+				})
+			},
+			expectedStatusCode:  404,
+			expectedContentType: "application/json",
+			expectedRequestBody: `{"status":404,"error":"not_found","message":"object not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/files/"+testCase.inputKey, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetProofOfDelivery(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	pdf := []byte("%PDF-1.4")
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedContentType string
+		expectedCache       string
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().GetProofOfDelivery(gomock.Any(), 3).Return(pdf, "application/pdf", nil)
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "application/pdf",
+			expectedCache:       "private, no-store",
+			expectedRequestBody: string(pdf),
+		},
+		{
+			name: "No proof",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().GetProofOfDelivery(gomock.Any(), 3).Return(nil, "", fmt.Errorf("Error in FileService: %w", service.ErrFileNotFound))
+			},
+			expectedStatusCode:  404,
+			expectedContentType: "application/json",
+			expectedRequestBody: `{"status":404,"error":"not_found","message":"file not found"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 9, Role: "Courier"}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/order/3/proof_of_delivery", nil)
+			req.Header.Set("Authorization", "Bearer testToken")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, testCase.expectedCache, w.Header().Get("Cache-Control"))
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC/grpcClient"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+)
+
+func TestHandler_Health(t *testing.T) {
+	testTable := []struct {
+		name                 string
+		health               grpcClient.Health
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "OK",
+			health:               grpcClient.Health{Target: "auth:8090", Connection: "READY", Breaker: grpcClient.BreakerClosed, Healthy: true},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"auth":{"target":"auth:8090","connection":"READY","breaker":"closed","healthy":true}}`,
+		},
+		{
+			name:                 "Breaker open",
+			health:               grpcClient.Health{Target: "auth:8090", Connection: "TRANSIENT_FAILURE", Breaker: grpcClient.BreakerOpen},
+			expectedStatusCode:   503,
+			expectedResponseBody: `{"auth":{"target":"auth:8090","connection":"TRANSIENT_FAILURE","breaker":"open","healthy":false}}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			get.EXPECT().AuthHealth().Return(testCase.health)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/health", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_UserIdentityAuthUnavailable(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	get := mock_service.NewMockAllProjectApp(c)
+	get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(nil, fmt.Errorf("%w: circuit breaker is open", service.ErrAuthUnavailable))
+
+	services := &service.Service{AllProjectApp: get}
+	handler := controller.NewHandler(services)
+	r := handler.InitRoutesGin()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/me/profile", nil)
+	req.Header.Set("Authorization", "Bearer testToken")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 503, w.Code)
+	assert.Equal(t, `{"status":503,"error":"unavailable","message":"auth service unavailable"}`, w.Body.String())
+}
+
+func TestHandler_DebugVars(t *testing.T) {
+	testTable := []struct {
+		name               string
+		role               string
+		expectedStatusCode int
+	}{
+		{name: "Superadmin", role: "Superadmin", expectedStatusCode: 200},
+		{name: "Courier manager", role: "Courier manager", expectedStatusCode: 403},
+		{name: "No token", expectedStatusCode: 401},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			if testCase.role != "" {
+				get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 9, Role: testCase.role}, nil)
+			}
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/debug/vars", nil)
+			if testCase.role != "" {
+				req.Header.Set("Authorization", "Bearer testToken")
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+)
+
+func TestHandler_Me(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	courier := dao.Caller{UserId: 9, Role: "Courier", DeliveryServiceId: 2, CourierId: 4}
+	manager := dao.Caller{UserId: 7, Role: "Courier manager", DeliveryServiceId: 2}
+
+	testTable := []struct {
+		name                string
+		method              string
+		url                 string
+		inputBody           string
+		inputUser           dao.Caller
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Profile",
+			method:    "GET",
+			url:       "/me/profile",
+			inputUser: courier,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 9, "Courier").Return(courier, nil)
+				s.EXPECT().GetCourier(gomock.Any(), 9).Return(dao.Courier{Id: 4, UserId: 9, CourierName: "Ivan", Status: "approved"}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `"id_courier":4,"user_id":9,"courier_name":"Ivan"`,
+		},
+		{
+			name:      "Not a courier",
+			method:    "GET",
+			url:       "/me/profile",
+			inputUser: manager,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 7, "Courier manager").Return(manager, nil)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"status":404,"error":"not_found","message":"user has no courier profile"}`,
+		},
+		{
+			name:      "Active orders",
+			method:    "GET",
+			url:       "/me/orders/active",
+			inputUser: courier,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 9, "Courier").Return(courier, nil)
+				s.EXPECT().GetOrders(gomock.Any(), 4).Return([]dao.Order{{Id: 1, IdCourier: 4, Status: "ready to delivery"}}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `[{"id":1,"courier_id":4,`,
+		},
+		{
+			name:      "Completed orders",
+			method:    "GET",
+			url:       "/me/orders/completed?page=1&limit=10",
+			inputUser: courier,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 9, "Courier").Return(courier, nil)
+				s.EXPECT().GetCourierCompletedOrders(gomock.Any(), 10, 1, 4).Return([]dao.DetailedOrder{}, dao.Pagination{Page: 1, TotalPages: 1}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[],`,
+		},
+		{
+			name:      "Earnings",
+			method:    "GET",
+			url:       "/me/earnings?month=3&year=2022",
+			inputUser: courier,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 9, "Courier").Return(courier, nil)
+				s.EXPECT().GetCourierEarnings(gomock.Any(), 4, 3, 2022).Return(dao.Earnings{Month: 3, Year: 2022, Deliveries: 10, Rate: 2.5, Amount: 25}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"month":3,"year":2022,"deliveries":10,"rate":2.5,"amount":25}`,
+		},
+		{
+			name:                "Earnings of a wrong month",
+			method:              "GET",
+			url:                 "/me/earnings?month=13",
+			inputUser:           courier,
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"invalid request","fields":[{"field":"month","message":"must be at most 12"}]}`,
+		},
+		{
+			name:      "Availability",
+			method:    "PUT",
+			url:       "/me/availability",
+			inputBody: `{"ready_to_go":true}`,
+			inputUser: courier,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 9, "Courier").Return(courier, nil)
+				s.EXPECT().SetCourierReadyToGo(gomock.Any(), 4, true).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:      "Service of the manager",
+			method:    "GET",
+			url:       "/me/service",
+			inputUser: manager,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 7, "Courier manager").Return(manager, nil)
+				s.EXPECT().GetDeliveryServiceById(gomock.Any(), 7).Return(&dao.DeliveryService{Id: 2, Name: "Fast", ManagerId: 7}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":2,"name":"Fast"`,
+		},
+		{
+			name:      "Manager without service",
+			method:    "GET",
+			url:       "/me/service",
+			inputUser: dao.Caller{UserId: 8, Role: "Courier manager"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 8, "Courier manager").Return(dao.Caller{UserId: 8, Role: "Courier manager"}, nil)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"status":404,"error":"not_found","message":"user has no delivery service"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{
+				UserId: int32(testCase.inputUser.UserId),
+				Role:   testCase.inputUser.Role,
+			}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(testCase.method, testCase.url, bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), testCase.expectedRequestBody)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+)
+
+func TestHandler_SubmitCourierApplication(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SubmitCourierApplication(gomock.Any(), 5).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name: "Missing documents",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SubmitCourierApplication(gomock.Any(), 5).
+					Return(fmt.Errorf("Error in OnboardingService: %w", apperr.Errorf("%w: missing or expired medical_certificate", service.ErrApplicationIncomplete)))
+			},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"status":422,"error":"unprocessable","message":"application is incomplete: missing or expired medical_certificate"}`,
+		},
+		{
+			name: "Already approved",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SubmitCourierApplication(gomock.Any(), 5).
+					Return(fmt.Errorf("Error in OnboardingService: %w", apperr.Errorf("%w: courier is approved", service.ErrOnboardingStatus)))
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"status":409,"error":"conflict","message":"not allowed in the courier's onboarding status: courier is approved"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 9, Role: "Courier"}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/courier/5/submit", nil)
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_ReviewCourierApplication(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name:      "Approve",
+			inputBody: `{"decision":"approve"}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ReviewCourierApplication(gomock.Any(), 5, true, "").Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:      "Reject without reason",
+			inputBody: `{"decision":"reject"}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ReviewCourierApplication(gomock.Any(), 5, false, "").
+					Return(fmt.Errorf("Error in OnboardingService: %w", apperr.Errorf("%w: a rejection needs a reason", service.ErrInvalidReview)))
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name:      "Not pending review",
+			inputBody: `{"decision":"reject","reason":"no photo"}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ReviewCourierApplication(gomock.Any(), 5, false, "no photo").
+					Return(fmt.Errorf("Error in OnboardingService: %w", apperr.Errorf("%w: courier is applied", service.ErrOnboardingStatus)))
+			},
+			expectedStatusCode: 409,
+		},
+		{
+			name:               "Unknown decision",
+			inputBody:          `{"decision":"maybe"}`,
+			mockBehavior:       func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode: 400,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: "Courier manager"}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/courier/5/review", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"net/http/httptest"
@@ -15,7 +16,6 @@ import (
 )
 
 func TestHandler_GetOrders(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, courier dao.Order)
 
@@ -39,7 +39,6 @@ func TestHandler_GetOrders(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
@@ -56,20 +55,17 @@ func TestHandler_GetOrders(t *testing.T) {
 				OrderDate:         "11.11.2022",
 			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, courier dao.Order) {
-				s.EXPECT().GetOrders(3).Return(orders, nil)
+				s.EXPECT().GetOrders(gomock.Any(), 3).Return(orders, nil)
 			},
 			inputRole:  "Courier",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier", "Courier manager"}, role).Return(nil)
-			},
 			expectedStatusCode:  200,
 			expectedRequestBody: `{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2022-02-19T13:34:53.000093589Z","customer_address":"Some address","status":"ready to delivery","order_date":"11.11.2022","restaurant_address":"","picked":false}`,
 		},
@@ -80,9 +76,9 @@ func TestHandler_GetOrders(t *testing.T) {
 			defer c.Finish()
 
 			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
 			testCase.mockBehavior(get, testCase.inputCourier)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -103,7 +99,6 @@ func TestHandler_GetOrders(t *testing.T) {
 }
 
 func TestHandler_GetOneOrder(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, courier dao.Order)
 
@@ -115,6 +110,7 @@ func TestHandler_GetOneOrder(t *testing.T) {
 		CustomerAddress:   "Some address",
 		Status:            "ready to delivery",
 		OrderDate:         "11.11.2022",
+		Version:           6,
 	}
 
 	testTable := []struct {
@@ -125,9 +121,9 @@ func TestHandler_GetOneOrder(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
+		expectedETag           string
 	}{
 		{
 			name:      "OK",
@@ -142,22 +138,20 @@ func TestHandler_GetOneOrder(t *testing.T) {
 				OrderDate:         "11.11.2022",
 			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, courier dao.Order) {
-				s.EXPECT().GetOrder(1).Return(ord, nil)
+				s.EXPECT().GetOrder(gomock.Any(), 1).Return(ord, nil)
 			},
 			inputRole:  "Courier",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier", "Courier manager"}, role).Return(nil)
-			},
 			expectedStatusCode:  200,
 			expectedRequestBody: `{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2022-02-19T13:34:53.000093589Z","customer_address":"Some address","status":"ready to delivery","order_date":"11.11.2022","restaurant_address":"","picked":false}`,
+			expectedETag:        `"6"`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -166,9 +160,9 @@ func TestHandler_GetOneOrder(t *testing.T) {
 			defer c.Finish()
 
 			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
 			testCase.mockBehavior(get, testCase.inputCourier)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -182,13 +176,13 @@ func TestHandler_GetOneOrder(t *testing.T) {
 			assert.Equal(t, testCase.expectedStatusCode, w.Code)
 			//assert.Equal(t, testCase.expectedRequestBody,w.Body.String())
 			assert.Contains(t, w.Body.String(), testCase.expectedRequestBody)
+			assert.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
 
 		})
 	}
 }
 
 func TestHandler_UpdateOrder(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, order dao.Order)
 
@@ -198,9 +192,9 @@ func TestHandler_UpdateOrder(t *testing.T) {
 		inputOrder             dao.Order
 		inputRole              string
 		inputToken             string
+		inputIfMatch           string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 	}{
 		{
@@ -209,24 +203,84 @@ func TestHandler_UpdateOrder(t *testing.T) {
 			inputOrder: dao.Order{
 				Id:        1,
 				IdCourier: 8,
+				Version:   2,
 			},
+			inputIfMatch: `"2"`,
 			mockBehavior: func(s *mock_service.MockAllProjectApp, order dao.Order) {
-				s.EXPECT().AssigningOrderToCourier(order).Return(nil)
+				s.EXPECT().AssigningOrderToCourier(gomock.Any(), order).Return(nil)
 			},
 			inputRole:  "Courier",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier", "Courier manager"}, role).Return(nil)
-			},
 			expectedStatusCode: 204,
 		},
+		{
+			name:      "Assigned by another dispatcher",
+			inputBody: `{"courier_id":8}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp, order dao.Order) {
+				s.EXPECT().AssigningOrderToCourier(gomock.Any(), gomock.Any()).Return(fmt.Errorf("Error in OrderService: %w", service.ErrOrderChanged))
+			},
+			inputRole:    "Courier",
+			inputToken:   "testToken",
+			inputIfMatch: `"2"`,
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: "Courier"}, nil)
+			},
+			expectedStatusCode: 412,
+		},
+		{
+			name:      "Unknown order",
+			inputBody: `{"courier_id":8}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp, order dao.Order) {
+				s.EXPECT().AssigningOrderToCourier(gomock.Any(), gomock.Any()).Return(fmt.Errorf("Error in OrderService: %w", service.ErrOrderNotFound))
+			},
+			inputRole:    "Courier",
+			inputToken:   "testToken",
+			inputIfMatch: `"2"`,
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: "Courier"}, nil)
+			},
+			expectedStatusCode: 404,
+		},
+		{
+			name:         "No If-Match",
+			inputBody:    `{"courier_id":8}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp, order dao.Order) {},
+			inputRole:    "Courier",
+			inputToken:   "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: "Courier"}, nil)
+			},
+			expectedStatusCode: 428,
+		},
+		{
+			name:         "No courier",
+			inputBody:    `{"id":5,"status":"completed"}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp, order dao.Order) {},
+			inputRole:    "Courier",
+			inputToken:   "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: "Courier"}, nil)
+			},
+			expectedStatusCode: 400,
+		},
+		{
+			name:         "Courier id of a wrong type",
+			inputBody:    `{"courier_id":"8"}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp, order dao.Order) {},
+			inputRole:    "Courier",
+			inputToken:   "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: "Courier"}, nil)
+			},
+			expectedStatusCode: 400,
+		},
 	}
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -234,9 +288,9 @@ func TestHandler_UpdateOrder(t *testing.T) {
 			defer c.Finish()
 
 			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
 			testCase.mockBehavior(get, testCase.inputOrder)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -245,6 +299,9 @@ func TestHandler_UpdateOrder(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("PUT", "/orders/1", bytes.NewBufferString(testCase.inputBody))
 			req.Header.Set("Authorization", "Bearer testToken")
+			if testCase.inputIfMatch != "" {
+				req.Header.Set("If-Match", testCase.inputIfMatch)
+			}
 			r.ServeHTTP(w, req)
 
 			assert.Equal(t, testCase.expectedStatusCode, w.Code)
@@ -254,7 +311,6 @@ func TestHandler_UpdateOrder(t *testing.T) {
 }
 
 func TestHandler_GetDetailedOrdersById(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, order *dao.AllInfoAboutOrder)
 
@@ -286,7 +342,6 @@ func TestHandler_GetDetailedOrdersById(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
@@ -299,17 +354,14 @@ func TestHandler_GetDetailedOrdersById(t *testing.T) {
 			inputRole:  "Courier",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier", "Courier manager"}, role).Return(nil)
-			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, order *dao.AllInfoAboutOrder) {
-				s.EXPECT().GetDetailedOrderById(1).Return(ord, nil)
+				s.EXPECT().GetDetailedOrderById(gomock.Any(), 1).Return(ord, nil)
 			},
 			expectedStatusCode:  200,
 			expectedRequestBody: `{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2022-02-19T13:34:53.000093589Z","customer_address":"Some address","status":"ready to delivery","order_date":"2022-11-11","restaurant_address":"Some address","restaurant_name":"","picked":true,"name":"Sam","surname":"","phone_number":"1234567","id_from_restaurant":0,"customer_name":"","customer_phone":"","payment_type":0}`,
@@ -321,9 +373,9 @@ func TestHandler_GetDetailedOrdersById(t *testing.T) {
 			defer c.Finish()
 
 			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
 			testCase.mockBehavior(get, &testCase.inputOrder)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -343,7 +395,6 @@ func TestHandler_GetDetailedOrdersById(t *testing.T) {
 }
 
 func TestHandler_GetCompletedOrdersOfCourierService(t *testing.T) {
-	type mockBehaviorCheck func(s *mock_service.MockAllProjectApp, role string)
 	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
 	type mockBehavior func(s *mock_service.MockAllProjectApp, order []dao.Order)
 	var orders []dao.Order
@@ -368,7 +419,6 @@ func TestHandler_GetCompletedOrdersOfCourierService(t *testing.T) {
 		inputToken             string
 		mockBehaviorParseToken mockBehaviorParseToken
 		mockBehavior           mockBehavior
-		mockBehaviorCheck      mockBehaviorCheck
 		expectedStatusCode     int
 		expectedRequestBody    string
 	}{
@@ -391,20 +441,17 @@ func TestHandler_GetCompletedOrdersOfCourierService(t *testing.T) {
 			inputRole:  "Courier",
 			inputToken: "testToken",
 			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
-				s.EXPECT().ParseToken(token).Return(&authProto.UserRole{
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
 					UserId:      1,
 					Role:        "Courier",
 					Permissions: "",
 				}, nil)
 			},
-			mockBehaviorCheck: func(s *mock_service.MockAllProjectApp, role string) {
-				s.EXPECT().CheckRole([]string{"Superadmin", "Courier", "Courier manager"}, role).Return(nil)
-			},
 			mockBehavior: func(s *mock_service.MockAllProjectApp, order []dao.Order) {
-				s.EXPECT().GetCompletedOrdersOfCourierService(1, 1, 1).Return(orders, nil)
+				s.EXPECT().GetCompletedOrdersOfCourierService(gomock.Any(), 1, 1, 1).Return(orders, dao.NewPagination(1, 1, 1), nil)
 			},
 			expectedStatusCode:  200,
-			expectedRequestBody: `{"data":[{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2020-05-02T02:02:02.000000002Z","customer_address":"Some address","status":"completed","order_date":"2022-02-02","restaurant_address":"","picked":false}]}`,
+			expectedRequestBody: `{"data":[{"delivery_service_id":1,"id":1,"courier_id":1,"delivery_time":"2020-05-02T02:02:02.000000002Z","customer_address":"Some address","status":"completed","order_date":"2022-02-02","restaurant_address":"","picked":false}],"page":1,"limit":1,"total":1,"total_pages":1}`,
 		},
 	}
 	for _, testCase := range testTable {
@@ -413,9 +460,9 @@ func TestHandler_GetCompletedOrdersOfCourierService(t *testing.T) {
 			defer c.Finish()
 
 			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
 			testCase.mockBehavior(get, testCase.inputOrder)
 			testCase.mockBehaviorParseToken(get, testCase.inputToken)
-			testCase.mockBehaviorCheck(get, testCase.inputRole)
 
 			services := &service.Service{AllProjectApp: get}
 			handler := controller.NewHandler(services)
@@ -433,3 +480,117 @@ func TestHandler_GetCompletedOrdersOfCourierService(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_GetCompletedOrdersOfCourierServiceAfterCursor(t *testing.T) {
+	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string)
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+	var orders []dao.Order
+	ord := dao.Order{
+		IdDeliveryService: 1,
+		Id:                2,
+		IdCourier:         1,
+		DeliveryTime:      time.Date(2020, time.May, 2, 2, 2, 2, 2, time.UTC),
+		CustomerAddress:   "Some address",
+		Status:            "completed",
+		OrderDate:         "2022-02-02",
+	}
+	orders = append(orders, ord)
+	nextCursor := dao.CursorFromOrder(ord).Encode()
+
+	testTable := []struct {
+		name                   string
+		inputQuery             string
+		inputRole              string
+		inputToken             string
+		mockBehaviorParseToken mockBehaviorParseToken
+		mockBehavior           mockBehavior
+		expectedStatusCode     int
+		expectedRequestBody    string
+	}{
+		{
+			name:       "First page",
+			inputQuery: "limit=1&after=&iddeliveryservice=1&sort=date",
+			inputRole:  "Courier manager",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
+					UserId:      1,
+					Role:        "Courier manager",
+					Permissions: "",
+				}, nil)
+			},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().GetCompletedOrdersOfCourierServiceByDateAfterCursor(gomock.Any(), 1, "", 1).
+					Return(orders, dao.CursorPagination{Limit: 1, NextCursor: nextCursor}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[{"delivery_service_id":1,"id":2,"courier_id":1,"delivery_time":"2020-05-02T02:02:02.000000002Z","customer_address":"Some address","status":"completed","order_date":"2022-02-02","restaurant_address":"","picked":false}],"limit":1,"next_cursor":"` + nextCursor + `"}`,
+		},
+		{
+			name:       "Last page",
+			inputQuery: "limit=1&after=" + nextCursor + "&iddeliveryservice=1",
+			inputRole:  "Courier manager",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{
+					UserId:      1,
+					Role:        "Courier manager",
+					Permissions: "",
+				}, nil)
+			},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().GetCompletedOrdersOfCourierServiceAfterCursor(gomock.Any(), 1, nextCursor, 1).
+					Return([]dao.Order{}, dao.CursorPagination{Limit: 1}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[],"limit":1}`,
+		},
+		{
+			name:       "Neither page nor cursor",
+			inputQuery: "limit=1&iddeliveryservice=1",
+			inputRole:  "Courier manager",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: "Courier manager"}, nil)
+			},
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"invalid request","fields":[{"field":"page","message":"is required"}]}`,
+		},
+		{
+			name:       "Limit over the cap",
+			inputQuery: "limit=1000&page=1&iddeliveryservice=1",
+			inputRole:  "Courier manager",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: "Courier manager"}, nil)
+			},
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"invalid request","fields":[{"field":"limit","message":"must be at most 100"}]}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			testCase.mockBehavior(get)
+			testCase.mockBehaviorParseToken(get, testCase.inputToken)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/orders/service/completed?"+testCase.inputQuery, nil)
+			req.Header.Set("Authorization", "Bearer testToken")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+)
+
+func TestHandler_Search(t *testing.T) {
+	type mockBehaviorParseToken func(s *mock_service.MockAllProjectApp, token string, role string)
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	result := &dao.SearchResult{
+		Couriers: []dao.FoundCourier{{Id: 1, CourierName: "Ivan", Surname: "Petrov", PhoneNumber: "+375291234567", Rank: 0.5}},
+		Orders:   []dao.FoundOrder{},
+	}
+
+	testTable := []struct {
+		name                   string
+		inputQuery             string
+		inputRole              string
+		inputToken             string
+		mockBehaviorParseToken mockBehaviorParseToken
+		mockBehavior           mockBehavior
+		expectedStatusCode     int
+		expectedRequestBody    string
+	}{
+		{
+			name:       "OK",
+			inputQuery: "q=petrov",
+			inputRole:  "Courier manager",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string, role string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 7, Role: role}, nil)
+			},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 7, "Courier manager").Return(dao.Caller{UserId: 7, Role: "Courier manager", DeliveryServiceId: 2}, nil)
+				s.EXPECT().Search(gomock.Any(), 2, "petrov", 20).Return(result, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"couriers":[{"id_courier":1,"courier_name":"Ivan","surname":"Petrov","phone_number":"+375291234567","email":"","photo":"","deleted":false,"rank":0.5}],"orders":[]}`,
+		},
+		{
+			name:       "Superadmin with service",
+			inputQuery: "q=petrov&limit=5&iddeliveryservice=3",
+			inputRole:  "Superadmin",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string, role string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: role}, nil)
+			},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().Search(gomock.Any(), 3, "petrov", 5).Return(result, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"couriers":[{"id_courier":1,"courier_name":"Ivan","surname":"Petrov","phone_number":"+375291234567","email":"","photo":"","deleted":false,"rank":0.5}],"orders":[]}`,
+		},
+		{
+			name:       "Empty query",
+			inputQuery: "q=",
+			inputRole:  "Courier manager",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string, role string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 7, Role: role}, nil)
+			},
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"invalid request","fields":[{"field":"q","message":"is required"}]}`,
+		},
+		{
+			name:       "Manager of another service",
+			inputQuery: "q=petrov&iddeliveryservice=3",
+			inputRole:  "Courier manager",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string, role string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 7, Role: role}, nil)
+			},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 7, "Courier manager").Return(dao.Caller{UserId: 7, Role: "Courier manager", DeliveryServiceId: 2}, nil)
+			},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"delivery service belongs to another delivery service"}`,
+		},
+		{
+			name:       "Superadmin without service",
+			inputQuery: "q=petrov",
+			inputRole:  "Superadmin",
+			inputToken: "testToken",
+			mockBehaviorParseToken: func(s *mock_service.MockAllProjectApp, token string, role string) {
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(&authProto.UserRole{UserId: 1, Role: role}, nil)
+			},
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"expect an integer greater than 0"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			testCase.mockBehavior(get)
+			testCase.mockBehaviorParseToken(get, testCase.inputToken, testCase.inputRole)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/search?"+testCase.inputQuery, nil)
+			req.Header.Set("Authorization", "Bearer testToken")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
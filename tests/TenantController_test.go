@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+)
+
+// sameTenant makes every caller a member of delivery service 1 and courier 1
+// that may work with whatever the request is about, for tests that aren't
+// about tenant scoping.
+func sameTenant(s *mock_service.MockAllProjectApp) {
+	s.EXPECT().ResolveCaller(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, userId int, role string) (dao.Caller, error) {
+		return dao.Caller{UserId: userId, Role: role, DeliveryServiceId: 1, CourierId: 1}, nil
+	}).AnyTimes()
+	s.EXPECT().CheckCourierAccess(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s.EXPECT().CheckOrderAccess(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s.EXPECT().CheckDocumentAccess(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
+func TestHandler_TenantScoping(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	manager := dao.Caller{UserId: 7, Role: "Courier manager", DeliveryServiceId: 2}
+	courier := dao.Caller{UserId: 9, Role: "Courier", DeliveryServiceId: 2, CourierId: 4}
+
+	testTable := []struct {
+		name                string
+		method              string
+		url                 string
+		inputBody           string
+		inputUser           dao.Caller
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Courier of another service",
+			method:    "GET",
+			url:       "/courier/5/documents",
+			inputUser: manager,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 7, "Courier manager").Return(manager, nil)
+				s.EXPECT().CheckCourierAccess(gomock.Any(), manager, 5).Return(fmt.Errorf("Error in TenantService: %w", apperr.Errorf("courier 5 %w", service.ErrOtherTenant)))
+			},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"courier 5 belongs to another delivery service"}`,
+		},
+		{
+			name:      "Unknown courier",
+			method:    "GET",
+			url:       "/courier/5/documents",
+			inputUser: manager,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 7, "Courier manager").Return(manager, nil)
+				s.EXPECT().CheckCourierAccess(gomock.Any(), manager, 5).Return(fmt.Errorf("Error in TenantService: %w", service.ErrCourierNotFound))
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"status":404,"error":"not_found","message":"courier not found"}`,
+		},
+		{
+			name:      "Order of another courier",
+			method:    "GET",
+			url:       "/order/detailed/3",
+			inputUser: courier,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 9, "Courier").Return(courier, nil)
+				s.EXPECT().CheckOrderAccess(gomock.Any(), courier, 3).Return(fmt.Errorf("Error in TenantService: %w", apperr.Errorf("order 3 %w", service.ErrOtherTenant)))
+			},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"order 3 belongs to another delivery service"}`,
+		},
+		{
+			name:      "Proof of delivery of another courier",
+			method:    "GET",
+			url:       "/order/3/proof_of_delivery",
+			inputUser: courier,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 9, "Courier").Return(courier, nil)
+				s.EXPECT().CheckOrderAccess(gomock.Any(), courier, 3).Return(fmt.Errorf("Error in TenantService: %w", apperr.Errorf("order 3 %w", service.ErrOtherTenant)))
+			},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"order 3 belongs to another delivery service"}`,
+		},
+		{
+			name:      "Document scan of another service",
+			method:    "GET",
+			url:       "/courier/5/documents/id_card/file",
+			inputUser: manager,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 7, "Courier manager").Return(manager, nil)
+				s.EXPECT().CheckCourierAccess(gomock.Any(), manager, 5).Return(fmt.Errorf("Error in TenantService: %w", apperr.Errorf("courier 5 %w", service.ErrOtherTenant)))
+			},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"courier 5 belongs to another delivery service"}`,
+		},
+		{
+			name:      "Other delivery service",
+			method:    "PUT",
+			url:       "/deliveryservice/3",
+			inputBody: `{"name":"name"}`,
+			inputUser: manager,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 7, "Courier manager").Return(manager, nil)
+			},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"delivery service 3 belongs to another delivery service"}`,
+		},
+		{
+			name:      "Orders of another service",
+			method:    "GET",
+			url:       "/orders/?page=1&limit=1&iddeliveryservice=3",
+			inputUser: manager,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 7, "Courier manager").Return(manager, nil)
+			},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"delivery service belongs to another delivery service"}`,
+		},
+		{
+			name:      "Orders of own service",
+			method:    "GET",
+			url:       "/orders/?page=1&limit=1",
+			inputUser: manager,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().ResolveCaller(gomock.Any(), 7, "Courier manager").Return(manager, nil)
+				s.EXPECT().GetAllOrdersOfCourierService(gomock.Any(), 1, 1, 2).Return([]dao.DetailedOrder{}, dao.Pagination{Page: 1, TotalPages: 1}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":[]`,
+		},
+		{
+			name:      "Superadmin crosses services",
+			method:    "GET",
+			url:       "/courier/5/documents",
+			inputUser: dao.Caller{UserId: 1, Role: "Superadmin"},
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().CheckCourierAccess(gomock.Any(), dao.Caller{UserId: 1, Role: "Superadmin"}, 5).Return(nil)
+				s.EXPECT().GetCourierDocuments(gomock.Any(), 5).Return([]dao.CourierDocument{}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `[]`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{
+				UserId: int32(testCase.inputUser.UserId),
+				Role:   testCase.inputUser.Role,
+			}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(testCase.method, testCase.url, bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), testCase.expectedRequestBody)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+	"time"
+)
+
+func TestHandler_CreateUpload(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+	expiresAt := time.Date(2022, 3, 1, 12, 15, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		inputRole           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"kind":"proof_of_delivery","target_id":7,"content_type":"application/pdf"}`,
+			inputRole: "Courier",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().CreateUpload(gomock.Any(), dao.Upload{Kind: "proof_of_delivery", TargetId: 7, ContentType: "application/pdf"}, 1).
+					Return(&dao.PresignedUpload{
+						Upload: dao.Upload{Id: "abc", Kind: "proof_of_delivery", TargetId: 7, ContentType: "application/pdf",
+							Status: "pending", ExpiresAt: expiresAt},
+						URL:    "https://bucket.example.com/uploads/proof_of_delivery/abc?X-Amz-Signature=1",
+						Method: "PUT",
+					}, nil)
+			},
+			expectedStatusCode:  201,
+			expectedRequestBody: `{"upload_id":"abc","kind":"proof_of_delivery","target_id":7,"content_type":"application/pdf","status":"pending","expires_at":"2022-03-01T12:15:00Z","url":"https://bucket.example.com/uploads/proof_of_delivery/abc?X-Amz-Signature=1","method":"PUT"}`,
+		},
+		{
+			name:                "Unknown kind",
+			inputBody:           `{"kind":"avatar","target_id":7,"content_type":"image/png"}`,
+			inputRole:           "Courier",
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"invalid request","fields":[{"field":"kind","message":"must be one of courier_photo, logo, proof_of_delivery, courier_document"}]}`,
+		},
+		{
+			name:      "Logo by courier",
+			inputBody: `{"kind":"logo","target_id":7,"content_type":"image/png"}`,
+			inputRole: "Courier",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+			},
+			expectedStatusCode:  403,
+			expectedRequestBody: `{"status":403,"error":"forbidden","message":"not enough rights"}`,
+		},
+		{
+			name:      "Storage without presigning",
+			inputBody: `{"kind":"courier_photo","target_id":7,"content_type":"image/png"}`,
+			inputRole: "Courier",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().CreateUpload(gomock.Any(), gomock.Any(), 1).Return(nil, fmt.Errorf("Error in UploadService: %w", service.ErrDirectUploadUnsupported))
+			},
+			expectedStatusCode:  501,
+			expectedRequestBody: `{"status":501,"error":"not_implemented","message":"storage does not support direct uploads"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: testCase.inputRole}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/uploads", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_CompleteUpload(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().CompleteUpload(gomock.Any(), "abc", 1, "Courier").Return("https://bucket.example.com/uploads/proof_of_delivery/abc", nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"completed","upload_id":"abc","url":"https://bucket.example.com/uploads/proof_of_delivery/abc"}`,
+		},
+		{
+			name: "Not uploaded yet",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().CompleteUpload(gomock.Any(), "abc", 1, "Courier").Return("", fmt.Errorf("Error in UploadService: %w", service.ErrUploadNotReceived))
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"status":409,"error":"conflict","message":"file has not been uploaded yet"}`,
+		},
+		{
+			name: "Expired",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().CompleteUpload(gomock.Any(), "abc", 1, "Courier").Return("", fmt.Errorf("Error in UploadService: %w", service.ErrUploadExpired))
+			},
+			expectedStatusCode:  410,
+			expectedRequestBody: `{"status":410,"error":"gone","message":"upload has expired"}`,
+		},
+		{
+			name: "Rejected",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().CompleteUpload(gomock.Any(), "abc", 1, "Courier").Return("", fmt.Errorf("Error in UploadService: %w", apperr.Errorf("%w: too big", service.ErrUploadRejected)))
+			},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"status":422,"error":"unprocessable","message":"uploaded file was rejected: too big"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: "Courier"}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/uploads/abc/complete", nil)
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
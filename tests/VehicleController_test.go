@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	authProto "stlab.itechart-group.com/go/food_delivery/courier_service/GRPCC"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/controller"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/dao"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/pkg/apperr"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service"
+	"stlab.itechart-group.com/go/food_delivery/courier_service/service/mocks"
+	"testing"
+)
+
+func TestHandler_SaveVehicle(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"type":"car","capacity_kg":250,"plate":"1234 AB-7"}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SaveVehicle(gomock.Any(), dao.Vehicle{CourierId: 5, Type: "car", CapacityKg: 250, Plate: "1234 AB-7"}).Return(3, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":3}`,
+		},
+		{
+			name:      "No plate",
+			inputBody: `{"type":"scooter"}`,
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().SaveVehicle(gomock.Any(), dao.Vehicle{CourierId: 5, Type: "scooter"}).
+					Return(0, fmt.Errorf("Error in VehicleService: %w", apperr.Errorf("%w: a scooter needs a plate", service.ErrInvalidVehicle)))
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"invalid vehicle: a scooter needs a plate"}`,
+		},
+		{
+			name:                "Invalid body",
+			inputBody:           `{"type":`,
+			mockBehavior:        func(s *mock_service.MockAllProjectApp) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"status":400,"error":"validation","message":"Invalid request"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: "Courier"}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/courier/5/vehicle", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetVehicle(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAllProjectApp)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().GetVehicleOfCourier(gomock.Any(), 5).Return(&dao.Vehicle{Id: 3, CourierId: 5, Type: "bike"}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":3,"courier_id":5,"type":"bike","capacity_kg":0,"plate":""}`,
+		},
+		{
+			name: "No vehicle",
+			mockBehavior: func(s *mock_service.MockAllProjectApp) {
+				s.EXPECT().GetVehicleOfCourier(gomock.Any(), 5).Return(nil, nil)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"status":404,"error":"not_found","message":"courier has no vehicle"}`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			get := mock_service.NewMockAllProjectApp(c)
+			sameTenant(get)
+			get.EXPECT().ParseToken(gomock.Any(), "testToken").Return(&authProto.UserRole{UserId: 1, Role: "Courier manager"}, nil)
+			testCase.mockBehavior(get)
+
+			services := &service.Service{AllProjectApp: get}
+			handler := controller.NewHandler(services)
+			r := handler.InitRoutesGin()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/courier/5/vehicle", nil)
+			req.Header.Set("Authorization", "Bearer testToken")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}